@@ -2,7 +2,12 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
@@ -46,6 +51,18 @@ func NewCli(GitCommit string, GitData string) *cli.App {
 				Description: "Run database migration",
 				Action:      runMigrations,
 			},
+			{
+				Name:        "rebuild-balances",
+				Flags:       flags,
+				Description: "Rebuild balances of all businesses from ledger entries",
+				Action:      runRebuildBalances,
+			},
+			{
+				Name:        "export-snapshot",
+				Flags:       append([]cli.Flag{flags2.SnapshotBusinessFlag, flags2.SnapshotBlockFlag, flags2.SnapshotDateFlag, flags2.SnapshotOutputFlag}, flags...),
+				Description: "Export a balance snapshot of a business as csv",
+				Action:      runExportSnapshot,
+			},
 			{
 				Name:        "notify",
 				Flags:       flags,
@@ -99,7 +116,128 @@ func runMigrations(ctx *cli.Context) error {
 		}
 	}(db)
 
-	return db.ExecuteSQLMigration(cfg.Migrations)
+	if err := db.ExecuteSQLMigration(cfg.Migrations); err != nil {
+		return err
+	}
+	// EVM 地址的 checksum 需要 keccak256，不能在 sql 迁移里完成
+	return db.NormalizeStoredAddresses()
+}
+
+func runRebuildBalances(ctx *cli.Context) error {
+	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
+	log.Info("running rebuild balances.....")
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	db, err := database.NewDB(ctx.Context, cfg.MasterDB)
+	if err != nil {
+		log.Error("failed to connect to database", "err", err)
+		return err
+	}
+	defer func(db *database.DB) {
+		err := db.Close()
+		if err != nil {
+			log.Error("failed to close database", "err", err)
+		}
+	}(db)
+
+	businessList, err := db.Business.QueryBusinessList()
+	if err != nil {
+		log.Error("query business list fail", "err", err)
+		return err
+	}
+	for _, business := range businessList {
+		if err := db.Balances.RebuildBalances(business.BusinessUid); err != nil {
+			log.Error("rebuild balances fail", "business", business.BusinessUid, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// runExportSnapshot 按 --block 或 --date 导出该区块或该日日终的余额为 csv，都不指定时导出最新已同步区块的余额；
+// 日终余额读取快照任务在第二天零点写入的快照，没有该快照时按账本分录汇总
+func runExportSnapshot(ctx *cli.Context) error {
+	ctx.Context = opio.CancelOnInterrupt(ctx.Context)
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		log.Error("failed to load config", "err", err)
+		return err
+	}
+	db, err := database.NewDB(ctx.Context, cfg.MasterDB)
+	if err != nil {
+		log.Error("failed to connect to database", "err", err)
+		return err
+	}
+	defer func(db *database.DB) {
+		err := db.Close()
+		if err != nil {
+			log.Error("failed to close database", "err", err)
+		}
+	}(db)
+
+	businessId := ctx.String(flags2.SnapshotBusinessFlag.Name)
+	chain := cfg.ChainNode.ChainName
+	var snapshot []*database.BalanceSnapshots
+	switch {
+	case ctx.String(flags2.SnapshotBlockFlag.Name) != "":
+		number, ok := new(big.Int).SetString(ctx.String(flags2.SnapshotBlockFlag.Name), 10)
+		if !ok {
+			return fmt.Errorf("invalid block number: %s", ctx.String(flags2.SnapshotBlockFlag.Name))
+		}
+		snapshot, err = db.Snapshots.QuerySnapshotAtBlock(businessId, chain, number)
+	case ctx.String(flags2.SnapshotDateFlag.Name) != "":
+		date, parseErr := time.Parse(time.DateOnly, ctx.String(flags2.SnapshotDateFlag.Name))
+		if parseErr != nil {
+			return fmt.Errorf("invalid date: %w", parseErr)
+		}
+		// 日终余额为第二天零点（UTC）之前最后一个区块上的余额，即第二天零点的快照
+		snapshot, err = db.Snapshots.QuerySnapshotAtTime(businessId, chain, uint64(date.AddDate(0, 0, 1).Unix()))
+	default:
+		snapshot, err = db.Snapshots.QuerySnapshotAtTime(businessId, chain, uint64(time.Now().Unix()))
+	}
+	if err != nil {
+		log.Error("query balance snapshot fail", "business", businessId, "chain", chain, "err", err)
+		return err
+	}
+	if snapshot == nil {
+		return fmt.Errorf("requested block or date not synced for business %s on chain %s", businessId, chain)
+	}
+
+	output := os.Stdout
+	if path := ctx.String(flags2.SnapshotOutputFlag.Name); path != "" {
+		if output, err = os.Create(path); err != nil {
+			return err
+		}
+		defer output.Close()
+	}
+	writer := csv.NewWriter(output)
+	if err := writer.Write([]string{"chain", "block_number", "timestamp", "address", "address_type", "token_address", "balance", "lock_balance"}); err != nil {
+		return err
+	}
+	for _, balance := range snapshot {
+		record := []string{
+			balance.Chain,
+			balance.BlockNumber.String(),
+			strconv.FormatUint(balance.Timestamp, 10),
+			balance.Address.String(),
+			string(balance.AddressType),
+			balance.TokenAddress.String(),
+			balance.Balance.String(),
+			balance.LockBalance.String(),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	log.Info("export balance snapshot success", "business", businessId, "chain", chain, "balances", len(snapshot))
+	return nil
 }
 
 func runMultichainSync(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycle, error) {
@@ -121,8 +259,17 @@ func runRpc(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycl
 	}
 
 	grpcServerCfg := &services.BusinessMiddleConfig{
-		GrpcHostName: cfg.RpcServer.Host,
-		GrpcPort:     cfg.RpcServer.Port,
+		GrpcHostName:   cfg.RpcServer.Host,
+		GrpcPort:       cfg.RpcServer.Port,
+		AddressFormats: make(map[string]database.AddressFormat, len(cfg.Chains)),
+	}
+	for _, chain := range cfg.Chains {
+		addressFormat, err := database.ParseAddressFormat(chain.AddressFormat)
+		if err != nil {
+			log.Error("parse address format fail", "chain", chain.ChainName, "err", err)
+			return nil, err
+		}
+		grpcServerCfg.AddressFormats[chain.ChainName] = addressFormat
 	}
 	db, err := database.NewDB(ctx.Context, cfg.MasterDB)
 	if err != nil {
@@ -138,11 +285,15 @@ func runRpc(ctx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycl
 	}
 
 	client := account.NewWalletAccountServiceClient(conn)
-	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, "Ethereum")
-	if err != nil {
-		log.Error("new wallet account client failed", "err", err)
-		return nil, err
+	var accountClients []*rpcclient.WalletChainAccountClient
+	for _, chain := range cfg.Chains {
+		accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, chain.ChainName, chain.Network)
+		if err != nil {
+			log.Error("new wallet account client failed", "chain", chain.ChainName, "err", err)
+			return nil, err
+		}
+		accountClients = append(accountClients, accountClient)
 	}
 
-	return services.NewBusinessMiddleWireServices(db, grpcServerCfg, accountClient)
+	return services.NewBusinessMiddleWireServices(db, grpcServerCfg, accountClients)
 }
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"fmt"
+	"math"
+	"os"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 
 	"github.com/JokingLove/multichain-sync-account/flags"
 )
@@ -14,11 +18,24 @@ const (
 	defaultSynchronizerInterval = 5000
 	defaultWorkerInterval       = 500
 	defaultBlocksStep           = 500
+	defaultBlockFetchWorkers    = 8
+	defaultCollectInterval      = time.Minute
+	defaultRebalanceInterval    = time.Minute
+	defaultReconcileInterval    = 10 * time.Minute
+	defaultSnapshotInterval     = 24 * time.Hour
+	defaultStuckBlocks          = 50
+	defaultFeeBumpPercent       = 125
+	defaultMaxBroadcastAttempts = 5
+	defaultNetwork              = "mainnet"
+	defaultAddressFormat        = "evm"
+	defaultUtxoAddressFormat    = "bech32"
 )
 
 type Config struct {
 	Migrations      string
 	ChainNode       ChainNodeConfig
+	Chains          []ChainNodeConfig
+	ChainsConfig    string
 	MasterDB        DBConfig
 	SlaveDB         DBConfig
 	SlaveDbEnable   bool
@@ -29,15 +46,27 @@ type Config struct {
 	ChainAccountRpc string
 }
 
+// ChainNodeConfig 单条链的同步配置，ChainName 作为链的唯一标识，区块和交易记录都按它区分
 type ChainNodeConfig struct {
-	ChainId              uint64
-	ChainName            string
-	RpcUrl               string
-	StartingHeight       uint
-	Confirmations        uint
-	SynchronizerInterval time.Duration
-	WorkerInterval       time.Duration
-	BlocksStep           uint64
+	ChainId              uint64        `yaml:"chain_id"`
+	ChainName            string        `yaml:"chain_name"`
+	Network              string        `yaml:"network"`
+	RpcUrl               string        `yaml:"rpc_url"`
+	StartingHeight       uint          `yaml:"starting_height"`
+	Confirmations        uint          `yaml:"confirmations"`
+	SynchronizerInterval time.Duration `yaml:"sync_interval"`
+	WorkerInterval       time.Duration `yaml:"worker_interval"`
+	BlocksStep           uint64        `yaml:"blocks_step"`
+	BlockFetchWorkers    int           `yaml:"block_fetch_workers"`
+	Utxo                 bool          `yaml:"utxo"`
+	AddressFormat        string        `yaml:"address_format"`
+	CollectInterval      time.Duration `yaml:"collect_interval"`
+	RebalanceInterval    time.Duration `yaml:"rebalance_interval"`
+	ReconcileInterval    time.Duration `yaml:"reconcile_interval"`
+	SnapshotInterval     time.Duration `yaml:"snapshot_interval"`
+	StuckBlocks          uint64        `yaml:"stuck_blocks"`
+	FeeBumpPercent       uint64        `yaml:"fee_bump_percent"`
+	MaxBroadcastAttempts uint64        `yaml:"max_broadcast_attempts"`
 }
 
 type DBConfig struct {
@@ -80,23 +109,173 @@ func LoadConfig(cliCtx *cli.Context) (Config, error) {
 		cfg.ChainNode.BlocksStep = defaultBlocksStep
 	}
 
-	log.Info("loaded chain config", "config", cfg.ChainNode)
+	if cfg.ChainNode.CollectInterval == 0 {
+		cfg.ChainNode.CollectInterval = defaultCollectInterval
+	}
+
+	if cfg.ChainNode.RebalanceInterval == 0 {
+		cfg.ChainNode.RebalanceInterval = defaultRebalanceInterval
+	}
+
+	if cfg.ChainNode.ReconcileInterval == 0 {
+		cfg.ChainNode.ReconcileInterval = defaultReconcileInterval
+	}
+
+	if cfg.ChainNode.SnapshotInterval == 0 {
+		cfg.ChainNode.SnapshotInterval = defaultSnapshotInterval
+	}
+
+	if cfg.ChainNode.StuckBlocks == 0 {
+		cfg.ChainNode.StuckBlocks = defaultStuckBlocks
+	}
+
+	if cfg.ChainNode.FeeBumpPercent == 0 {
+		cfg.ChainNode.FeeBumpPercent = defaultFeeBumpPercent
+	}
+
+	if cfg.ChainNode.MaxBroadcastAttempts == 0 {
+		cfg.ChainNode.MaxBroadcastAttempts = defaultMaxBroadcastAttempts
+	}
+
+	if cfg.ChainNode.BlockFetchWorkers <= 0 {
+		cfg.ChainNode.BlockFetchWorkers = defaultBlockFetchWorkers
+	}
+
+	if cfg.ChainNode.Network == "" {
+		cfg.ChainNode.Network = defaultNetwork
+	}
+
+	if cfg.ChainNode.AddressFormat == "" {
+		cfg.ChainNode.AddressFormat = defaultAddressFormat
+		if cfg.ChainNode.Utxo {
+			cfg.ChainNode.AddressFormat = defaultUtxoAddressFormat
+		}
+	}
+
+	chains, err := loadChains(cfg.ChainsConfig, cfg.ChainNode)
+	if err != nil {
+		log.Error("load chains config fail", "path", cfg.ChainsConfig, "err", err)
+		return cfg, err
+	}
+	cfg.Chains = chains
+
+	for _, chain := range cfg.Chains {
+		// 充值的 confirms、notified_confirms 按 uint8 保存，超过上限会回绕导致确认数倒退
+		if chain.Confirmations > math.MaxUint8 {
+			return cfg, fmt.Errorf("confirmations of chain %s must not exceed %d: %d", chain.ChainName, math.MaxUint8, chain.Confirmations)
+		}
+		log.Info("loaded chain config", "config", chain)
+	}
 	return cfg, nil
 }
 
+// loadChains 读取多链配置文件，文件中没有填写的字段使用命令行里的 ChainNode 配置；
+// 没有配置文件时只同步 ChainNode 这一条链
+func loadChains(path string, base ChainNodeConfig) ([]ChainNodeConfig, error) {
+	if path == "" {
+		return []ChainNodeConfig{base}, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Chains []ChainNodeConfig `yaml:"chains"`
+	}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Chains) == 0 {
+		return nil, fmt.Errorf("no chains configured in %s", path)
+	}
+
+	names := make(map[string]struct{}, len(file.Chains))
+	chains := make([]ChainNodeConfig, 0, len(file.Chains))
+	for _, chain := range file.Chains {
+		if chain.ChainName == "" {
+			return nil, fmt.Errorf("chain name is required in %s", path)
+		}
+		if _, exists := names[chain.ChainName]; exists {
+			return nil, fmt.Errorf("duplicate chain name: %s", chain.ChainName)
+		}
+		names[chain.ChainName] = struct{}{}
+
+		if chain.Network == "" {
+			chain.Network = base.Network
+		}
+		if chain.Confirmations == 0 {
+			chain.Confirmations = base.Confirmations
+		}
+		if chain.SynchronizerInterval == 0 {
+			chain.SynchronizerInterval = base.SynchronizerInterval
+		}
+		if chain.WorkerInterval == 0 {
+			chain.WorkerInterval = base.WorkerInterval
+		}
+		if chain.BlocksStep == 0 {
+			chain.BlocksStep = base.BlocksStep
+		}
+		if chain.CollectInterval == 0 {
+			chain.CollectInterval = base.CollectInterval
+		}
+		if chain.RebalanceInterval == 0 {
+			chain.RebalanceInterval = base.RebalanceInterval
+		}
+		if chain.ReconcileInterval == 0 {
+			chain.ReconcileInterval = base.ReconcileInterval
+		}
+		if chain.SnapshotInterval == 0 {
+			chain.SnapshotInterval = base.SnapshotInterval
+		}
+		if chain.StuckBlocks == 0 {
+			chain.StuckBlocks = base.StuckBlocks
+		}
+		if chain.FeeBumpPercent == 0 {
+			chain.FeeBumpPercent = base.FeeBumpPercent
+		}
+		if chain.MaxBroadcastAttempts == 0 {
+			chain.MaxBroadcastAttempts = base.MaxBroadcastAttempts
+		}
+		if chain.BlockFetchWorkers <= 0 {
+			chain.BlockFetchWorkers = base.BlockFetchWorkers
+		}
+		if chain.AddressFormat == "" {
+			chain.AddressFormat = base.AddressFormat
+			if chain.Utxo {
+				chain.AddressFormat = defaultUtxoAddressFormat
+			}
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
+
 func NewConfig(ctx *cli.Context) Config {
 	return Config{
 		Migrations:      ctx.String(flags.MigrationsFlag.Name),
 		ChainAccountRpc: ctx.String(flags.ChainAccountRpcFlag.Name),
+		ChainsConfig:    ctx.String(flags.ChainsConfigFlag.Name),
 		ChainNode: ChainNodeConfig{
 			ChainId:              ctx.Uint64(flags.ChainIdFlag.Name),
 			ChainName:            ctx.String(flags.ChainNameFlag.Name),
+			Network:              ctx.String(flags.NetworkFlag.Name),
 			RpcUrl:               ctx.String(flags.RpcUrlFlag.Name),
 			StartingHeight:       ctx.Uint(flags.StartingHeightFlag.Name),
 			Confirmations:        ctx.Uint(flags.ConfirmationsFlag.Name),
 			SynchronizerInterval: ctx.Duration(flags.SynchronizerIntervalFlag.Name),
 			WorkerInterval:       ctx.Duration(flags.WorkerIntervalFlag.Name),
 			BlocksStep:           ctx.Uint64(flags.BlocksStepFlag.Name),
+			BlockFetchWorkers:    ctx.Int(flags.BlockFetchWorkersFlag.Name),
+			Utxo:                 ctx.Bool(flags.UtxoFlag.Name),
+			AddressFormat:        ctx.String(flags.AddressFormatFlag.Name),
+			CollectInterval:      ctx.Duration(flags.CollectIntervalFlag.Name),
+			RebalanceInterval:    ctx.Duration(flags.RebalanceIntervalFlag.Name),
+			ReconcileInterval:    ctx.Duration(flags.ReconcileIntervalFlag.Name),
+			SnapshotInterval:     ctx.Duration(flags.SnapshotIntervalFlag.Name),
+			StuckBlocks:          ctx.Uint64(flags.StuckBlocksFlag.Name),
+			FeeBumpPercent:       ctx.Uint64(flags.FeeBumpPercentFlag.Name),
+			MaxBroadcastAttempts: ctx.Uint64(flags.MaxBroadcastAttemptsFlag.Name),
 		},
 		MasterDB: DBConfig{
 			Host:     ctx.String(flags.MasterDbHostFlag.Name),
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Address 链上原生地址，按所属链的 AddressFormat 规范化后保存，不再统一转换成 20 字节的 EVM 地址
+type Address string
+
+func (a Address) String() string {
+	return string(a)
+}
+
+// AddressFormat 链的地址格式，决定地址的规范化方式
+type AddressFormat string
+
+const (
+	AddressFormatEVM    AddressFormat = "evm"    // 0x 开头的十六进制地址，统一为 checksum 格式
+	AddressFormatBech32 AddressFormat = "bech32" // 比特币 bc1、Cosmos 等 bech32 地址统一为小写，其他地址原样保存
+	AddressFormatNative AddressFormat = "native" // Solana、Tron 等 base58 地址大小写敏感，原样保存
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func ParseAddressFormat(s string) (AddressFormat, error) {
+	switch strings.ToLower(s) {
+	case string(AddressFormatEVM):
+		return AddressFormatEVM, nil
+	case string(AddressFormatBech32):
+		return AddressFormatBech32, nil
+	case string(AddressFormatNative):
+		return AddressFormatNative, nil
+	default:
+		return "", fmt.Errorf("unknown address format: %s", s)
+	}
+}
+
+// Normalize 只做格式上的规范化，地址是否合法由链账户服务的 validAddress 接口校验
+func (f AddressFormat) Normalize(address string) Address {
+	address = strings.TrimSpace(address)
+	switch f {
+	case AddressFormatEVM:
+		if common.IsHexAddress(address) {
+			return Address(common.HexToAddress(address).String())
+		}
+	case AddressFormatBech32:
+		if isBech32(address) {
+			return Address(strings.ToLower(address))
+		}
+	}
+	return Address(address)
+}
+
+// isBech32 bech32 地址不区分大小写但不允许大小写混用，数据部分只包含 bech32 字符集
+func isBech32(address string) bool {
+	if address != strings.ToLower(address) && address != strings.ToUpper(address) {
+		return false
+	}
+	lower := strings.ToLower(address)
+	separator := strings.LastIndexByte(lower, '1')
+	if separator < 1 || separator+7 > len(lower) {
+		return false
+	}
+	for _, c := range lower[separator+1:] {
+		if !strings.ContainsRune(bech32Charset, c) {
+			return false
+		}
+	}
+	return true
+}
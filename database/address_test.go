@@ -0,0 +1,116 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressFormatNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  AddressFormat
+		address string
+		want    Address
+	}{
+		{
+			name:    "evm lower case to checksum",
+			format:  AddressFormatEVM,
+			address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			want:    "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		},
+		{
+			name:    "evm trims spaces",
+			format:  AddressFormatEVM,
+			address: " 0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED\n",
+			want:    "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		},
+		{
+			name:    "evm keeps non hex address",
+			format:  AddressFormatEVM,
+			address: "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8",
+			want:    "TJRabPrwbZy45sbavfcjinPJC18kjpRTv8",
+		},
+		{
+			name:    "bech32 upper case to lower case",
+			format:  AddressFormatBech32,
+			address: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			want:    "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			name:    "bech32 keeps legacy address",
+			format:  AddressFormatBech32,
+			address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+			want:    "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		},
+		{
+			name:    "bech32 keeps mixed case address",
+			format:  AddressFormatBech32,
+			address: "bc1QW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+			want:    "bc1QW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			name:    "native is case sensitive",
+			format:  AddressFormatNative,
+			address: "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV",
+			want:    "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV",
+		},
+		{
+			name:    "native keeps hex address",
+			format:  AddressFormatNative,
+			address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			want:    "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.format.Normalize(tt.address))
+		})
+	}
+}
+
+func TestIsBech32(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", want: true},
+		{address: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", want: true},
+		{address: "cosmos1hsk6jryyqjfhp5dhc55tc9jtckygx0eph6dd02", want: true},
+		{address: "bc1QW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", want: false},
+		{address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3tb", want: false},
+		{address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", want: false},
+		{address: "bc1qqqqq", want: false},
+		{address: "qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", want: false},
+		{address: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			require.Equal(t, tt.want, isBech32(tt.address))
+		})
+	}
+}
+
+func TestParseAddressFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    AddressFormat
+		wantErr bool
+	}{
+		{input: "evm", want: AddressFormatEVM},
+		{input: "BECH32", want: AddressFormatBech32},
+		{input: "Native", want: AddressFormatNative},
+		{input: "base58", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			format, err := ParseAddressFormat(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, format)
+		})
+	}
+}
@@ -1,28 +1,30 @@
 package database
 
 import (
-	"strings"
-	
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 )
 
+// Addresses 业务方在某条链上的地址，同一个地址在不同的链上是不同的记录
 type Addresses struct {
-	GUID        uuid.UUID      `gorm:"primary_key" json:"guid"`
-	Address     common.Address `gorm:"type:varchar;unique;not null" json:"address"`
-	AddressType AddressType    `gorm:"type:varchar(10);not null; default:'eoa'" json:"address_type"`
-	PublicKey   string         `gorm:"type:varchar;not null" json:"public_key"`
-	Timestamp   uint64         `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+	GUID        uuid.UUID   `gorm:"primary_key" json:"guid"`
+	Chain       string      `gorm:"type:varchar;not null" json:"chain"`
+	Address     Address     `gorm:"type:varchar;not null" json:"address"`
+	Memo        string      `gorm:"type:varchar;not null;default:''" json:"memo"`
+	AddressType AddressType `gorm:"type:varchar(10);not null; default:'eoa'" json:"address_type"`
+	PublicKey   string      `gorm:"type:varchar;not null" json:"public_key"`
+	Timestamp   uint64      `gorm:"type:bigint;not null;check:timestamp > 0" json:"timestamp"`
+	Seq         uint64      `gorm:"column:seq;->" json:"seq"` // 写入顺序，由数据库分配
 }
 
 type AddressesView interface {
-	AddressExists(requestId string, address *common.Address) (bool, AddressType)
-	QueryAddressByToAddress(requestId string, toAddress *common.Address) (*Addresses, error)
-	QueryHotWalletInfo(requestId string) (*Addresses, error)
-	QueryColdWalletInfo(requestId string) (*Addresses, error)
-	GetAllAddresses(requestId string) ([]*Addresses, error)
+	AddressExists(requestId string, chain string, address Address) (bool, AddressType)
+	QueryAddressByToAddress(requestId string, chain string, toAddress Address) (*Addresses, error)
+	QueryHotWalletInfo(requestId string, chain string) (*Addresses, error)
+	QueryColdWalletInfo(requestId string, chain string) (*Addresses, error)
+	GetAllAddresses(requestId string, chain string) ([]*Addresses, error)
+	QueryAddressesAfter(requestId string, chain string, seq uint64) ([]*Addresses, error)
 }
 
 type AddressesDB interface {
@@ -39,10 +41,10 @@ func NewAddressesDB(db *gorm.DB) AddressesDB {
 	return &addressesDB{gorm: db}
 }
 
-func (a *addressesDB) AddressExists(requestId string, address *common.Address) (bool, AddressType) {
+func (a *addressesDB) AddressExists(requestId string, chain string, address Address) (bool, AddressType) {
 	var addressEntry Addresses
 	err := a.gorm.Table(TableAddressesPrefix+requestId).
-		Where("address = ?", strings.ToLower(address.String())).
+		Where("chain = ? and address = ?", chain, address.String()).
 		First(&addressEntry).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -53,10 +55,10 @@ func (a *addressesDB) AddressExists(requestId string, address *common.Address) (
 	return true, addressEntry.AddressType
 }
 
-func (a addressesDB) QueryAddressByToAddress(requestId string, toAddress *common.Address) (*Addresses, error) {
+func (a addressesDB) QueryAddressByToAddress(requestId string, chain string, toAddress Address) (*Addresses, error) {
 	var addressEntry Addresses
 	err := a.gorm.Table(TableAddressesPrefix+requestId).
-		Where("address = ?", strings.ToLower(toAddress.String())).
+		Where("chain = ? and address = ?", chain, toAddress.String()).
 		First(&addressEntry).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -67,10 +69,10 @@ func (a addressesDB) QueryAddressByToAddress(requestId string, toAddress *common
 	return &addressEntry, nil
 }
 
-func (a addressesDB) QueryHotWalletInfo(requestId string) (*Addresses, error) {
+func (a addressesDB) QueryHotWalletInfo(requestId string, chain string) (*Addresses, error) {
 	var addressEntry Addresses
 	err := a.gorm.Table(TableAddressesPrefix+requestId).
-		Where("address_type = ?", AddressTypeHot).
+		Where("chain = ? and address_type = ?", chain, AddressTypeHot).
 		First(&addressEntry).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -81,10 +83,10 @@ func (a addressesDB) QueryHotWalletInfo(requestId string) (*Addresses, error) {
 	return &addressEntry, nil
 }
 
-func (a addressesDB) QueryColdWalletInfo(requestId string) (*Addresses, error) {
+func (a addressesDB) QueryColdWalletInfo(requestId string, chain string) (*Addresses, error) {
 	var addressEntry Addresses
 	err := a.gorm.Table(TableAddressesPrefix+requestId).
-		Where("address_type = ?", AddressTypeCold).
+		Where("chain = ? and address_type = ?", chain, AddressTypeCold).
 		First(&addressEntry).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -95,9 +97,10 @@ func (a addressesDB) QueryColdWalletInfo(requestId string) (*Addresses, error) {
 	return &addressEntry, nil
 }
 
-func (a addressesDB) GetAllAddresses(requestId string) ([]*Addresses, error) {
+func (a addressesDB) GetAllAddresses(requestId string, chain string) ([]*Addresses, error) {
 	var addresses []*Addresses
-	err := a.gorm.Table(TableAddressesPrefix + requestId).
+	err := a.gorm.Table(TableAddressesPrefix+requestId).
+		Where("chain = ?", chain).
 		Find(&addresses).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -108,16 +111,26 @@ func (a addressesDB) GetAllAddresses(requestId string) ([]*Addresses, error) {
 	return addresses, nil
 }
 
+// QueryAddressesAfter 按写入顺序查询 seq 之后写入的地址，用于增量刷新内存地址索引
+func (a addressesDB) QueryAddressesAfter(requestId string, chain string, seq uint64) ([]*Addresses, error) {
+	var addresses []*Addresses
+	err := a.gorm.Table(TableAddressesPrefix+requestId).
+		Where("chain = ? and seq > ?", chain, seq).
+		Order("seq asc").
+		Find(&addresses).Error
+	if err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
 func (a addressesDB) StoreAddresses(requestId string, addressList []*Addresses) error {
 
-	for _, address := range addressList {
-		address.Address = common.HexToAddress(address.Address.Hex())
-	}
-	return a.gorm.CreateInBatches(&addressList, len(addressList)).Error
+	return a.gorm.Table(TableAddressesPrefix+requestId).CreateInBatches(&addressList, len(addressList)).Error
 }
 
 func (a Addresses) validate() error {
-	if a.Address == (common.Address{}) {
+	if a.Address == "" {
 		return errors.New("address is required")
 	}
 	if a.PublicKey == "" {
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressesScopedByChain(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	address := AddressFormatEVM.Normalize("0x1000000000000000000000000000000000000001")
+	newAddress := func(chain string, addressType AddressType) *Addresses {
+		return &Addresses{
+			GUID:        uuid.New(),
+			Chain:       chain,
+			Address:     address,
+			AddressType: addressType,
+			PublicKey:   "0x01",
+			Timestamp:   uint64(time.Now().Unix()),
+		}
+	}
+	require.NoError(t, db.Addresses.StoreAddresses(requestId, []*Addresses{
+		newAddress("Ethereum", AddressTypeHot),
+		newAddress("Polygon", AddressTypeEOA),
+	}))
+	require.Error(t, db.Addresses.StoreAddresses(requestId, []*Addresses{newAddress("Polygon", AddressTypeEOA)}))
+
+	hot, err := db.Addresses.QueryHotWalletInfo(requestId, "Ethereum")
+	require.NoError(t, err)
+	require.NotNil(t, hot)
+	require.Equal(t, address, hot.Address)
+
+	hot, err = db.Addresses.QueryHotWalletInfo(requestId, "Polygon")
+	require.NoError(t, err)
+	require.Nil(t, hot)
+
+	exists, addressType := db.Addresses.AddressExists(requestId, "Polygon", address)
+	require.True(t, exists)
+	require.Equal(t, AddressTypeEOA, addressType)
+
+	exists, _ = db.Addresses.AddressExists(requestId, "Arbitrum", address)
+	require.False(t, exists)
+
+	addresses, err := db.Addresses.GetAllAddresses(requestId, "Ethereum")
+	require.NoError(t, err)
+	require.Len(t, addresses, 1)
+	require.Equal(t, AddressTypeHot, addresses[0].AddressType)
+}
+
+func TestQueryAddressesAfterSeq(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	newAddress := func(address string, timestamp uint64) *Addresses {
+		return &Addresses{
+			GUID:        uuid.New(),
+			Chain:       "Ethereum",
+			Address:     AddressFormatEVM.Normalize(address),
+			AddressType: AddressTypeEOA,
+			PublicKey:   "0x01",
+			Timestamp:   timestamp,
+		}
+	}
+	now := uint64(time.Now().Unix())
+	require.NoError(t, db.Addresses.StoreAddresses(requestId, []*Addresses{newAddress("0x1000000000000000000000000000000000000001", now)}))
+	first, err := db.Addresses.QueryAddressesAfter(requestId, "Ethereum", 0)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	require.NotZero(t, first[0].Seq)
+
+	// 后写入的地址 timestamp 更早，按 seq 仍能加载到
+	require.NoError(t, db.Addresses.StoreAddresses(requestId, []*Addresses{newAddress("0x1000000000000000000000000000000000000002", now-60)}))
+	after, err := db.Addresses.QueryAddressesAfter(requestId, "Ethereum", first[0].Seq)
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	require.Greater(t, after[0].Seq, first[0].Seq)
+	require.Equal(t, AddressFormatEVM.Normalize("0x1000000000000000000000000000000000000002"), after[0].Address)
+
+	empty, err := db.Addresses.QueryAddressesAfter(requestId, "Polygon", 0)
+	require.NoError(t, err)
+	require.Empty(t, empty)
+}
@@ -0,0 +1,242 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BalanceSnapshots 某条链在某个区块上的余额，按账本分录中该链在该区块及之前生效的分录汇总得到。
+// 定期快照同一次的记录 SnapshotId 相同，余额和锁定余额都为 0 的地址不写入，
+// 但每次快照都会写入一条 Address 为空的头记录，没有余额的快照也能确定快照时间
+type BalanceSnapshots struct {
+	GUID         uuid.UUID      `gorm:"primaryKey" json:"guid"`
+	SnapshotId   uuid.UUID      `gorm:"type:varchar;not null" json:"snapshot_id"`
+	Chain        string         `gorm:"type:varchar;not null" json:"chain"`
+	BlockNumber  *big.Int       `gorm:"not null;serializer:u256" json:"block_number"`
+	Address      Address        `gorm:"type:varchar;not null" json:"address"`
+	AddressType  AddressType    `gorm:"type:varchar(10);not null" json:"address_type"`
+	TokenAddress common.Address `gorm:"serializer:bytes" json:"token_address"`
+	Balance      *big.Int       `gorm:"not null;serializer:u256" json:"balance"`
+	LockBalance  *big.Int       `gorm:"not null;serializer:u256" json:"lock_balance"`
+	Timestamp    uint64         `gorm:"not null" json:"timestamp"`
+}
+
+type BalanceSnapshotsView interface {
+	LatestSnapshotTime(requestId string, chain string) (uint64, error)
+	QuerySnapshotAtBlock(requestId string, chain string, number *big.Int) ([]*BalanceSnapshots, error)
+	QuerySnapshotAtTime(requestId string, chain string, timestamp uint64) ([]*BalanceSnapshots, error)
+	QueryBalanceAtBlock(requestId string, chain string, number *big.Int, address Address, tokenAddress common.Address) (*BalanceSnapshots, error)
+	QueryBalanceAtTime(requestId string, chain string, timestamp uint64, address Address, tokenAddress common.Address) (*BalanceSnapshots, error)
+}
+
+type BalanceSnapshotsDB interface {
+	BalanceSnapshotsView
+
+	TakeSnapshot(requestId string, chain string, number *big.Int, timestamp uint64) (int64, error)
+}
+
+type balanceSnapshotsDB struct {
+	gorm *gorm.DB
+}
+
+func NewBalanceSnapshotsDB(db *gorm.DB) BalanceSnapshotsDB {
+	return &balanceSnapshotsDB{gorm: db}
+}
+
+// TakeSnapshot 把链在区块高度 number 上的余额写入 timestamp 时刻的快照，返回写入的余额记录数
+func (db balanceSnapshotsDB) TakeSnapshot(requestId string, chain string, number *big.Int, timestamp uint64) (int64, error) {
+	header, err := NewBlocksDB(db.gorm).BlockHeaderByNumber(chain, number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block %s of chain %s not synced", number, chain)
+	}
+	balances, err := db.ledgerBalances(requestId, chain, header.Number, header.Timestamp, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshotId := uuid.New()
+	snapshot := []*BalanceSnapshots{{
+		GUID:        uuid.New(),
+		SnapshotId:  snapshotId,
+		Chain:       chain,
+		BlockNumber: header.Number,
+		Balance:     big.NewInt(0),
+		LockBalance: big.NewInt(0),
+		Timestamp:   timestamp,
+	}}
+	for _, balance := range balances {
+		if balance.Balance.Sign() == 0 && balance.LockBalance.Sign() == 0 {
+			continue
+		}
+		balance.GUID = uuid.New()
+		balance.SnapshotId = snapshotId
+		balance.Timestamp = timestamp
+		snapshot = append(snapshot, balance)
+	}
+	if err := db.gorm.Table(TableBalanceSnapshotsPrefix+requestId).CreateInBatches(snapshot, 1000).Error; err != nil {
+		return 0, fmt.Errorf("take balance snapshot failed: %w", err)
+	}
+	return int64(len(snapshot) - 1), nil
+}
+
+// LatestSnapshotTime 链上最近一次快照的时间，没有快照时返回 0
+func (db balanceSnapshotsDB) LatestSnapshotTime(requestId string, chain string) (uint64, error) {
+	var timestamp uint64
+	err := db.gorm.Table(TableBalanceSnapshotsPrefix+requestId).
+		Where("chain = ?", chain).
+		Select("coalesce(max(timestamp), 0)").
+		Scan(&timestamp).Error
+	if err != nil {
+		return 0, err
+	}
+	return timestamp, nil
+}
+
+// QuerySnapshotAtBlock 查询链在区块高度 number 上全部地址的余额，区块还没有同步时返回 nil
+func (db balanceSnapshotsDB) QuerySnapshotAtBlock(requestId string, chain string, number *big.Int) ([]*BalanceSnapshots, error) {
+	header, err := NewBlocksDB(db.gorm).BlockHeaderByNumber(chain, number)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return db.ledgerBalances(requestId, chain, header.Number, header.Timestamp, nil)
+}
+
+// QuerySnapshotAtTime 查询链在出块时间不晚于 timestamp 的最新已同步区块上全部地址的余额；
+// timestamp 是定期快照的时间（如日终）时直接读取写入的快照，否则按账本分录汇总
+func (db balanceSnapshotsDB) QuerySnapshotAtTime(requestId string, chain string, timestamp uint64) ([]*BalanceSnapshots, error) {
+	head, err := db.storedSnapshot(requestId, chain, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if head != nil {
+		balances := make([]*BalanceSnapshots, 0)
+		if err := db.gorm.Table(TableBalanceSnapshotsPrefix+requestId).
+			Where("snapshot_id = ? and address <> ?", head.SnapshotId, "").
+			Order("address asc").
+			Find(&balances).Error; err != nil {
+			return nil, fmt.Errorf("query balance snapshot failed: %w", err)
+		}
+		return balances, nil
+	}
+
+	header, err := NewBlocksDB(db.gorm).BlockHeaderByTime(chain, timestamp)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return db.ledgerBalances(requestId, chain, header.Number, header.Timestamp, nil)
+}
+
+// QueryBalanceAtBlock 查询地址在区块高度 number 上的余额，没有分录时余额为 0，区块还没有同步时返回 nil
+func (db balanceSnapshotsDB) QueryBalanceAtBlock(requestId string, chain string, number *big.Int, address Address, tokenAddress common.Address) (*BalanceSnapshots, error) {
+	header, err := NewBlocksDB(db.gorm).BlockHeaderByNumber(chain, number)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return db.addressBalance(requestId, chain, header.Number, header.Timestamp, address, tokenAddress)
+}
+
+// QueryBalanceAtTime 查询地址在出块时间不晚于 timestamp 的最新已同步区块上的余额，规则同 QueryBalanceAtBlock；
+// 有该时间的定期快照时从快照中读取，快照中没有的地址余额为 0
+func (db balanceSnapshotsDB) QueryBalanceAtTime(requestId string, chain string, timestamp uint64, address Address, tokenAddress common.Address) (*BalanceSnapshots, error) {
+	head, err := db.storedSnapshot(requestId, chain, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if head != nil {
+		var balance BalanceSnapshots
+		result := db.gorm.Table(TableBalanceSnapshotsPrefix+requestId).
+			Where("snapshot_id = ? and address = ? and token_address = ?", head.SnapshotId, address.String(), tokenAddress.String()).
+			Limit(1).
+			Find(&balance)
+		if result.Error != nil {
+			return nil, fmt.Errorf("query balance snapshot failed: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return &balance, nil
+		}
+		return &BalanceSnapshots{
+			SnapshotId:   head.SnapshotId,
+			Chain:        chain,
+			BlockNumber:  head.BlockNumber,
+			Address:      address,
+			TokenAddress: tokenAddress,
+			Balance:      big.NewInt(0),
+			LockBalance:  big.NewInt(0),
+			Timestamp:    timestamp,
+		}, nil
+	}
+
+	header, err := NewBlocksDB(db.gorm).BlockHeaderByTime(chain, timestamp)
+	if err != nil || header == nil {
+		return nil, err
+	}
+	return db.addressBalance(requestId, chain, header.Number, header.Timestamp, address, tokenAddress)
+}
+
+// storedSnapshot 查询链在 timestamp 时刻写入的快照的头记录，没有时返回 nil
+func (db balanceSnapshotsDB) storedSnapshot(requestId string, chain string, timestamp uint64) (*BalanceSnapshots, error) {
+	var head BalanceSnapshots
+	result := db.gorm.Table(TableBalanceSnapshotsPrefix+requestId).
+		Where("chain = ? and timestamp = ? and address = ?", chain, timestamp, "").
+		Order("block_number desc").
+		Limit(1).
+		Find(&head)
+	if result.Error != nil {
+		return nil, fmt.Errorf("query balance snapshot head failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &head, nil
+}
+
+func (db balanceSnapshotsDB) addressBalance(requestId string, chain string, number *big.Int, timestamp uint64, address Address, tokenAddress common.Address) (*BalanceSnapshots, error) {
+	balances, err := db.ledgerBalances(requestId, chain, number, timestamp, db.gorm.Where("address = ? and token_address = ?", address.String(), tokenAddress.String()))
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) > 0 {
+		return balances[0], nil
+	}
+	return &BalanceSnapshots{
+		Chain:        chain,
+		BlockNumber:  number,
+		Address:      address,
+		TokenAddress: tokenAddress,
+		Balance:      big.NewInt(0),
+		LockBalance:  big.NewInt(0),
+		Timestamp:    timestamp,
+	}, nil
+}
+
+// ledgerBalances 按地址和代币汇总链在区块高度 number 及之前生效的分录，得到可用余额和锁定余额，
+// timestamp 为该区块的出块时间
+func (db balanceSnapshotsDB) ledgerBalances(requestId string, chain string, number *big.Int, timestamp uint64, cond *gorm.DB) ([]*BalanceSnapshots, error) {
+	query := db.gorm.Table(TableLedgerEntriesPrefix+requestId).
+		Select(`address, token_address, min(address_type) as address_type,
+			coalesce(sum(case when account = ? and direction = ? then amount when account = ? then -amount else 0 end), 0) as balance,
+			coalesce(sum(case when account = ? and direction = ? then amount when account = ? then -amount else 0 end), 0) as lock_balance`,
+			LedgerAccountAvailable, LedgerDebit, LedgerAccountAvailable,
+			LedgerAccountLocked, LedgerDebit, LedgerAccountLocked).
+		Where("account <> ? and chain = ? and block_number <= ?", LedgerAccountExternal, chain, number.String())
+	if cond != nil {
+		query = query.Where(cond)
+	}
+	balances := make([]*BalanceSnapshots, 0)
+	if err := query.Group("address, token_address").Order("address asc").Scan(&balances).Error; err != nil {
+		return nil, fmt.Errorf("sum ledger entries failed: %w", err)
+	}
+	for _, balance := range balances {
+		balance.Chain = chain
+		balance.BlockNumber = number
+		balance.Timestamp = timestamp
+	}
+	return balances, nil
+}
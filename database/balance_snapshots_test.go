@@ -0,0 +1,64 @@
+package database
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotAtTimeReadsStoredSnapshot(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	chain := requestId
+	t.Cleanup(func() {
+		db.gorm.Where("chain = ?", chain).Delete(&Blocks{})
+	})
+	require.NoError(t, db.Blocks.StoreBlocks([]Blocks{
+		{Chain: chain, Hash: common.HexToHash("0x0a"), ParentHash: common.HexToHash("0x09"), Number: big.NewInt(10), Timestamp: 1000},
+		{Chain: chain, Hash: common.HexToHash("0x14"), ParentHash: common.HexToHash("0x0a"), Number: big.NewInt(20), Timestamp: 2000},
+	}))
+
+	user := AddressFormatEVM.Normalize("0x2000000000000000000000000000000000000002")
+	external := AddressFormatEVM.Normalize("0x4000000000000000000000000000000000000004")
+	deposit := func(amount int64) *TokenBalance {
+		return &TokenBalance{
+			FromAddress: external,
+			ToAddress:   user,
+			Balance:     big.NewInt(amount),
+			TxType:      TxTypeDeposit,
+			SourceGuid:  uuid.New(),
+			Chain:       chain,
+			BlockNumber: big.NewInt(10),
+		}
+	}
+	require.NoError(t, db.Balances.UpdateOrCreate(requestId, []*TokenBalance{deposit(100)}))
+	count, err := db.Snapshots.TakeSnapshot(requestId, chain, big.NewInt(10), 1500)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// 快照之后补记的分录不改变已写入的快照
+	require.NoError(t, db.Balances.UpdateOrCreate(requestId, []*TokenBalance{deposit(50)}))
+
+	snapshot, err := db.Snapshots.QuerySnapshotAtTime(requestId, chain, 1500)
+	require.NoError(t, err)
+	require.Len(t, snapshot, 1)
+	require.Equal(t, user, snapshot[0].Address)
+	require.Equal(t, "100", snapshot[0].Balance.String())
+
+	balance, err := db.Snapshots.QueryBalanceAtTime(requestId, chain, 1500, user, common.Address{})
+	require.NoError(t, err)
+	require.Equal(t, "100", balance.Balance.String())
+
+	balance, err = db.Snapshots.QueryBalanceAtTime(requestId, chain, 1500, external, common.Address{})
+	require.NoError(t, err)
+	require.Equal(t, "0", balance.Balance.String())
+	require.Equal(t, "10", balance.BlockNumber.String())
+
+	// 没有快照的时间按账本分录汇总
+	snapshot, err = db.Snapshots.QuerySnapshotAtTime(requestId, chain, 1800)
+	require.NoError(t, err)
+	require.Len(t, snapshot, 1)
+	require.Equal(t, "150", snapshot[0].Balance.String())
+}
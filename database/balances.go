@@ -13,31 +13,43 @@ import (
 	"gorm.io/gorm"
 )
 
+// Balances 地址在某条链上的代币余额，不同链上同一个地址、同一个代币地址的余额分开记录
 type Balances struct {
 	GUID         uuid.UUID      `gorm:"primary_key" json:"guid"`
-	Address      common.Address `gorm:"serializer:bytes;"json:"address"`
+	Chain        string         `gorm:"type:varchar;not null" json:"chain"`
+	Address      Address        `json:"address"`
 	TokenAddress common.Address `gorm:"serializer:bytes;"json:"token_address"`
 	AddressType  AddressType    `gorm:"type:varchar(10);not null;"json:"address_type"`
 	Balance      *big.Int       `gorm:"not null;default:0;"json:"balance"`
 	LockBalance  *big.Int       `gorm:"not null;default:0;"json:"lock_balance"`
 	Timestamp    uint64         `gorm:"not null;"json:"timestamp"`
+
+	// SourceGuid、BlockNumber 记入账本的来源交易和生效的区块，不落库
+	SourceGuid  uuid.UUID `gorm:"-" json:"-"`
+	BlockNumber *big.Int  `gorm:"-" json:"-"`
 }
 
 type BalancesView interface {
 	QueryWalletBalanceByTokenAndAddress(
 		requestId string,
+		chain string,
 		addressType AddressType,
-		address, tokenAddress common.Address,
+		address Address, tokenAddress common.Address,
 	) (*Balances, error)
+	QueryCollectBalances(requestId string, chain string, tokenAddress common.Address, collectAmount *big.Int) ([]*Balances, error)
+	QueryBalance(requestId string, chain string, address Address, tokenAddress common.Address) (*Balances, error)
 }
 
 type BalancesDB interface {
 	BalancesView
 
 	UpdateOrCreate(string, []*TokenBalance) error
+	RollbackBalances(string, []*TokenBalance) error
 	StoreBalances(string, []*Balances) error
 	UpdateBalanceListByTwoAddress(string, []*Balances) error
+	ReleaseLockBalance(string, []*Balances) error
 	UpdateBalance(string, *Balances) error
+	RebuildBalances(requestId string) error
 }
 
 type balanceDB struct {
@@ -48,18 +60,48 @@ func NewBalancesDB(db *gorm.DB) BalancesDB {
 	return &balanceDB{gorm: db}
 }
 
-func (db balanceDB) QueryWalletBalanceByTokenAndAddress(requestId string, addressType AddressType, address, tokenAddress common.Address) (*Balances, error) {
-	balance, err := db.queryBalance(requestId, address, tokenAddress)
+func (db balanceDB) QueryWalletBalanceByTokenAndAddress(requestId string, chain string, addressType AddressType, address Address, tokenAddress common.Address) (*Balances, error) {
+	balance, err := db.queryBalance(requestId, chain, address, tokenAddress)
 	if err == nil {
 		return balance, nil
 	}
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return db.createInitialBalance(requestId, addressType, address, tokenAddress)
+		return db.createInitialBalance(requestId, chain, addressType, address, tokenAddress)
+	}
+	return nil, fmt.Errorf("query balance failed: %w", err)
+}
+
+// QueryBalance 只读查询地址在链上的余额，没有余额记录时返回 0，不创建记录
+func (db balanceDB) QueryBalance(requestId string, chain string, address Address, tokenAddress common.Address) (*Balances, error) {
+	balance, err := db.queryBalance(requestId, chain, address, tokenAddress)
+	if err == nil {
+		return balance, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Balances{
+			Chain:        chain,
+			Address:      address,
+			TokenAddress: tokenAddress,
+			Balance:      big.NewInt(0),
+			LockBalance:  big.NewInt(0),
+		}, nil
 	}
 	return nil, fmt.Errorf("query balance failed: %w", err)
 }
 
+// QueryCollectBalances 查询链上可用余额达到归集金额的用户地址
+func (db balanceDB) QueryCollectBalances(requestId string, chain string, tokenAddress common.Address, collectAmount *big.Int) ([]*Balances, error) {
+	var balances []*Balances
+	err := db.gorm.Table(TableBalancesPrefix+requestId).
+		Where("chain = ? and address_type = ? and token_address = ? and balance >= ?", chain, AddressTypeEOA, tokenAddress.String(), collectAmount.String()).
+		Find(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
 func (db balanceDB) UpdateOrCreate(requestId string, balances []*TokenBalance) error {
 	if len(balances) == 0 {
 		return nil
@@ -82,64 +124,130 @@ func (db balanceDB) UpdateOrCreate(requestId string, balances []*TokenBalance) e
 	})
 }
 
+// RollbackBalances 链重组时按来源交易追加反向分录，撤销原分录对余额的影响；
+// 没有分录的交易（启用账本前入账）按交易类型生成分录后反向记账
+func (db balanceDB) RollbackBalances(requestId string, balances []*TokenBalance) error {
+	if len(balances) == 0 {
+		return nil
+	}
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, balance := range balances {
+			var entries []*LedgerEntries
+			err := tx.Table(TableLedgerEntriesPrefix+requestId).
+				Where("source_guid = ?", balance.SourceGuid.String()).
+				Find(&entries).Error
+			if err != nil {
+				return fmt.Errorf("query ledger entries failed: %w", err)
+			}
+
+			reversed := false
+			for _, entry := range entries {
+				if entry.SourceType == LedgerSourceReversal {
+					reversed = true
+				}
+			}
+			if reversed {
+				continue
+			}
+
+			if len(entries) == 0 {
+				posting, err := db.transferPosting(tx, requestId, balance)
+				if err != nil {
+					return err
+				}
+				fee, err := db.feePosting(tx, requestId, balance)
+				if err != nil {
+					return err
+				}
+				entries = append(posting.entries, fee.entries...)
+			}
+
+			// 反向分录记在原分录的区块上，按区块汇总时重组掉的区块不再计入
+			reversal := newLedgerPosting(LedgerSourceReversal, balance.SourceGuid, balance.TxType, balance.TokenAddress, balance.Chain, balance.BlockNumber)
+			for _, entry := range entries {
+				direction := LedgerDebit
+				if entry.Direction == LedgerDebit {
+					direction = LedgerCredit
+				}
+				reversal.token = entry.TokenAddress
+				reversal.chain = entry.Chain
+				reversal.number = entry.BlockNumber
+				reversal.add(ledgerSide{address: entry.Address, addressType: entry.AddressType, account: entry.Account}, direction, entry.Amount)
+			}
+			if err := db.post(tx, requestId, reversal); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (db balanceDB) StoreBalances(requestId string, balances []*Balances) error {
 	valueList := make([]Balances, len(balances))
 	for i, balance := range balances {
 		if balance != nil {
-			balance.Address = common.HexToAddress(balance.Address.Hex())
 			balance.TokenAddress = common.HexToAddress(balance.TokenAddress.Hex())
 			valueList[i] = *balance
 		}
 	}
-	return db.gorm.Table(TableBalancesPrefix+requestId).CreateInBatches(&valueList, len(valueList)).Error
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(TableBalancesPrefix+requestId).CreateInBatches(&valueList, len(valueList)).Error; err != nil {
+			return err
+		}
+		// 导出地址时已有的链上余额记为期初分录，余额投影已直接写入
+		for _, balance := range valueList {
+			if balance.Balance == nil || balance.Balance.Sign() <= 0 {
+				continue
+			}
+			posting := newLedgerPosting(LedgerSourceOpening, balance.GUID, TxTypeUnknown, balance.TokenAddress, balance.Chain, balance.BlockNumber)
+			posting.move(
+				ledgerSide{address: balance.Address, addressType: balance.AddressType, account: LedgerAccountExternal},
+				ledgerSide{address: balance.Address, addressType: balance.AddressType, account: LedgerAccountAvailable},
+				balance.Balance,
+			)
+			if err := tx.Table(TableLedgerEntriesPrefix + requestId).Create(posting.entries).Error; err != nil {
+				return fmt.Errorf("store ledger entries failed: %w", err)
+			}
+		}
+		return nil
+	})
 }
 
-func (db *balanceDB) UpdateAndSaveBalance(tx *gorm.DB, requestId string, balance *Balances) error {
-	if balance == nil {
-		return fmt.Errorf("balance can not be nil")
+// UpdateBalanceListByTwoAddress 提现和内部交易广播时把发送金额从可用余额转入锁定余额
+func (db balanceDB) UpdateBalanceListByTwoAddress(requestId string, balanceList []*Balances) error {
+	if len(balanceList) == 0 {
+		return nil
 	}
 
-	var currentBalance Balances
-	result := tx.Table(TableBalancesPrefix+requestId).
-		Where("address = ? and token_address = ?",
-			balance.Address.String(), balance.TokenAddress.String()).
-		Take(&currentBalance)
-
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			log.Debug("Balance record not found",
-				"requestId", requestId,
-				"address", balance.Address.String(),
-				"tokenAddress", balance.TokenAddress.String(),
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, balance := range balanceList {
+			var currentBalance Balances
+			result := tx.Table(TableBalancesPrefix+requestId).
+				Where("chain = ? and address = ? and token_address = ?", balance.Chain, balance.Address.String(), balance.TokenAddress.String()).
+				Take(&currentBalance)
+			if result.Error != nil {
+				if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+					continue
+				}
+				return fmt.Errorf("query balance failed: %w", result.Error)
+			}
+
+			posting := newLedgerPosting(LedgerSourceLock, balance.SourceGuid, TxTypeUnknown, balance.TokenAddress, balance.Chain, balance.BlockNumber)
+			posting.move(
+				ledgerSide{address: currentBalance.Address, addressType: currentBalance.AddressType, account: LedgerAccountAvailable},
+				ledgerSide{address: currentBalance.Address, addressType: currentBalance.AddressType, account: LedgerAccountLocked},
+				balance.LockBalance,
 			)
-			return nil
+			if err := db.post(tx, requestId, posting); err != nil {
+				return err
+			}
 		}
-		return fmt.Errorf("query balance failed: %w", result.Error)
-	}
-
-	currentBalance.Balance = balance.Balance // 上游修改这里不做重复计算
-	currentBalance.LockBalance = new(big.Int).Add(currentBalance.LockBalance, balance.LockBalance)
-	currentBalance.Timestamp = uint64(time.Now().Unix())
-
-	if err := tx.Table(TableBalancesPrefix + requestId).Save(&currentBalance).Error; err != nil {
-		log.Error("Failed to save balance",
-			"requestId", requestId,
-			"address", balance.Address.String(),
-			"error", err)
-		return fmt.Errorf("save balance failed: %w", err)
-	}
-
-	log.Debug("Balance updated and save successfully",
-		"requestId", requestId,
-		"address", balance.Address.String(),
-		"tokenAddress", balance.TokenAddress.String(),
-		"newBalance", currentBalance.Balance.String(),
-		"lockBalance", currentBalance.LockBalance.String(),
-	)
-	return nil
+		return nil
+	})
 }
 
-func (db balanceDB) UpdateBalanceListByTwoAddress(requestId string, balanceList []*Balances) error {
+// ReleaseLockBalance 提现或归集在链上失败时，把发送时锁定的金额退回可用余额
+func (db balanceDB) ReleaseLockBalance(requestId string, balanceList []*Balances) error {
 	if len(balanceList) == 0 {
 		return nil
 	}
@@ -148,7 +256,7 @@ func (db balanceDB) UpdateBalanceListByTwoAddress(requestId string, balanceList
 		for _, balance := range balanceList {
 			var currentBalance Balances
 			result := tx.Table(TableBalancesPrefix+requestId).
-				Where("address = ? and token_address = ?", balance.Address.String(), balance.TokenAddress.String()).
+				Where("chain = ? and address = ? and token_address = ?", balance.Chain, balance.Address.String(), balance.TokenAddress.String()).
 				Take(&currentBalance)
 			if result.Error != nil {
 				if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -157,13 +265,21 @@ func (db balanceDB) UpdateBalanceListByTwoAddress(requestId string, balanceList
 				return fmt.Errorf("query balance failed: %w", result.Error)
 			}
 
-			currentBalance.Balance = new(big.Int).Sub(currentBalance.Balance, balance.LockBalance)
-			currentBalance.LockBalance = balance.LockBalance
-			currentBalance.Timestamp = balance.Timestamp
-
-			if err := tx.Table(TableBalancesPrefix + requestId).Save(&currentBalance).Error; err != nil {
-				return fmt.Errorf("save balance failed: %w", err)
+			amount := new(big.Int).Set(balance.LockBalance)
+			if currentBalance.LockBalance.Cmp(amount) < 0 {
+				log.Warn("lock balance less than released amount", "requestId", requestId, "address", balance.Address, "lockBalance", currentBalance.LockBalance, "amount", amount)
+				amount.Set(currentBalance.LockBalance)
 			}
+			posting := newLedgerPosting(LedgerSourceUnlock, balance.SourceGuid, TxTypeUnknown, balance.TokenAddress, balance.Chain, balance.BlockNumber)
+			posting.move(
+				ledgerSide{address: currentBalance.Address, addressType: currentBalance.AddressType, account: LedgerAccountLocked},
+				ledgerSide{address: currentBalance.Address, addressType: currentBalance.AddressType, account: LedgerAccountAvailable},
+				amount,
+			)
+			if err := db.post(tx, requestId, posting); err != nil {
+				return err
+			}
+			log.Info("release lock balance", "requestId", requestId, "address", balance.Address, "amount", amount)
 		}
 		return nil
 	})
@@ -176,12 +292,13 @@ func (db balanceDB) UpdateBalance(s string, balances *Balances) error {
 
 func (db *balanceDB) queryBalance(
 	requestId string,
-	address, tokenAddress common.Address,
+	chain string,
+	address Address, tokenAddress common.Address,
 ) (*Balances, error) {
 	var balance Balances
 
 	err := db.gorm.Table(TableBalancesPrefix+requestId).
-		Where("address = ? and token_address = ?", strings.ToLower(address.String()),
+		Where("chain = ? and address = ? and token_address = ?", chain, address.String(),
 			strings.ToLower(tokenAddress.String()),
 		).Take(&balance).Error
 	if err != nil {
@@ -190,9 +307,10 @@ func (db *balanceDB) queryBalance(
 	return &balance, nil
 }
 
-func (db balanceDB) createInitialBalance(requestId string, addressType AddressType, address common.Address, tokenAddress common.Address) (*Balances, error) {
+func (db balanceDB) createInitialBalance(requestId string, chain string, addressType AddressType, address Address, tokenAddress common.Address) (*Balances, error) {
 	balance := &Balances{
 		GUID:         uuid.New(),
+		Chain:        chain,
 		Address:      address,
 		TokenAddress: tokenAddress,
 		AddressType:  addressType,
@@ -214,124 +332,130 @@ func (db balanceDB) createInitialBalance(requestId string, addressType AddressTy
 }
 
 func (db balanceDB) handleBalanceUpdate(tx *gorm.DB, requestId string, balance *TokenBalance) error {
-	switch balance.TxType {
-	case TxTypeDeposit:
-		return db.handleDeposit(tx, requestId, balance)
-	case TxTypeWithdraw:
-		return db.handleWithdraw(tx, requestId, balance)
-	case TxTypeCollection:
-		return db.handleCollection(tx, requestId, balance)
-	case TxTypeHot2Cold:
-		return db.handleHot2Cold(tx, requestId, balance)
-	case TxTypeCold2Hot:
-		return db.handleCold2Hot(tx, requestId, balance)
-	default:
-		return fmt.Errorf("unsupported transaction type: %s", balance.TxType)
-	}
-}
-
-func (db balanceDB) handleDeposit(tx *gorm.DB, requestId string, balance *TokenBalance) error {
-	userAddress, err := db.QueryWalletBalanceByTokenAndAddress(requestId, AddressTypeEOA, balance.ToAddress, balance.TokenAddress)
+	posting, err := db.transferPosting(tx, requestId, balance)
 	if err != nil {
-		log.Error("Query user address failed", "requestId", requestId, "address", balance.ToAddress, "err", err)
 		return err
 	}
-
-	userAddress.Balance = new(big.Int).Add(userAddress.Balance, balance.Balance)
-	return db.UpdateAndSaveBalance(tx, requestId, userAddress)
-}
-
-func (db balanceDB) handleWithdraw(tx *gorm.DB, requestId string, balance *TokenBalance) error {
-	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, AddressTypeHot, balance.FromAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("Query hot wallet address balance failed", "requestId", requestId, "address", balance.FromAddress, "err", err)
+	if err := db.post(tx, requestId, posting); err != nil {
 		return err
 	}
-	hotWallet.Balance = new(big.Int).Sub(hotWallet.Balance, balance.Balance)
-	return db.UpdateAndSaveBalance(tx, requestId, hotWallet)
-}
-
-func (db balanceDB) handleCollection(tx *gorm.DB, requestId string, balance *TokenBalance) error {
-	userWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, AddressTypeEOA, balance.FromAddress, balance.TokenAddress)
+	fee, err := db.feePosting(tx, requestId, balance)
 	if err != nil {
-		log.Error("query user wallet address balance failed", "requestId", requestId, "address", balance.FromAddress, "err", err)
 		return err
 	}
+	return db.post(tx, requestId, fee)
+}
 
-	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, AddressTypeHot, balance.ToAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("query hot wallet address balance failed", "requestId", requestId, "address", balance.ToAddress, "err", err)
-		return err
+// transferPosting 按交易类型生成转账分录：
+//   - 充值：外部地址 -> 用户地址
+//   - 提现：热钱包 -> 外部地址
+//   - 归集：用户地址 -> 热钱包
+//   - 热转冷、冷转热、补充手续费、用户间转账：from -> to
+//
+// 钱包发出的交易广播时已锁定金额，上链后先从锁定余额扣除，不足的部分从可用余额扣除
+func (db balanceDB) transferPosting(tx *gorm.DB, requestId string, balance *TokenBalance) (*ledgerPosting, error) {
+	posting := newLedgerPosting(LedgerSourceTransfer, balance.SourceGuid, balance.TxType, balance.TokenAddress, balance.Chain, balance.BlockNumber)
+	external := func(address Address) ledgerSide {
+		return ledgerSide{address: address, addressType: AddressTypeEOA, account: LedgerAccountExternal}
 	}
-
-	userWallet.Balance = new(big.Int).Sub(userWallet.Balance, balance.Balance)
-	hotWallet.Balance = new(big.Int).Add(hotWallet.Balance, balance.Balance)
-
-	// update user wallet
-	if err := db.UpdateAndSaveBalance(tx, requestId, userWallet); err != nil {
-		log.Error("update user wallet failed for collection", "requestId", requestId, "address", balance.ToAddress, "err", err)
-		return err
+	available := func(address Address, addressType AddressType) ledgerSide {
+		return ledgerSide{address: address, addressType: addressType, account: LedgerAccountAvailable}
 	}
-	// update hot wallet
-	if err := db.UpdateAndSaveBalance(tx, requestId, hotWallet); err != nil {
-		log.Error("update hot wallet failed for collection", "requestId", requestId, "address", balance.ToAddress, "err", err)
-		return err
+	outflow := func(from Address, fromType AddressType, to ledgerSide) error {
+		current, err := db.projection(tx, requestId, balance.Chain, from, fromType, balance.TokenAddress)
+		if err != nil {
+			return err
+		}
+		fromLocked := new(big.Int).Set(balance.Balance)
+		if current.LockBalance.Cmp(fromLocked) < 0 {
+			fromLocked.Set(current.LockBalance)
+		}
+		if fromLocked.Sign() < 0 {
+			fromLocked.SetInt64(0)
+		}
+		fromAvailable := new(big.Int).Sub(balance.Balance, fromLocked)
+		if err := db.fundShortfall(tx, requestId, balance, from, fromType, balance.TokenAddress, current, fromAvailable); err != nil {
+			return err
+		}
+		posting.move(ledgerSide{address: from, addressType: fromType, account: LedgerAccountLocked}, to, fromLocked)
+		posting.move(available(from, fromType), to, fromAvailable)
+		return nil
 	}
-	return nil
-}
 
-func (db balanceDB) handleHot2Cold(tx *gorm.DB, requestId string, balance *TokenBalance) error {
-	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, AddressTypeHot, balance.FromAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("hot2cold query hot wallet failed", "requestId", requestId, "address", balance.FromAddress, "err", err)
-		return err
+	var err error
+	switch balance.TxType {
+	case TxTypeDeposit:
+		posting.move(external(balance.FromAddress), available(balance.ToAddress, AddressTypeEOA), balance.Balance)
+	case TxTypeWithdraw:
+		err = outflow(balance.FromAddress, AddressTypeHot, external(balance.ToAddress))
+	case TxTypeCollection:
+		err = outflow(balance.FromAddress, AddressTypeEOA, available(balance.ToAddress, AddressTypeHot))
+	case TxTypeHot2Cold:
+		err = outflow(balance.FromAddress, AddressTypeHot, available(balance.ToAddress, AddressTypeCold))
+	case TxTypeCold2Hot:
+		err = outflow(balance.FromAddress, AddressTypeCold, available(balance.ToAddress, AddressTypeHot))
+	case TxTypeGasTopUp:
+		err = outflow(balance.FromAddress, AddressTypeHot, available(balance.ToAddress, AddressTypeEOA))
+	case TxTypeInternalTransfer:
+		posting.move(available(balance.FromAddress, AddressTypeEOA), available(balance.ToAddress, AddressTypeEOA), balance.Balance)
+	default:
+		return nil, fmt.Errorf("unsupported transaction type: %s", balance.TxType)
 	}
-
-	coldWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, AddressTypeCold, balance.ToAddress, balance.TokenAddress)
 	if err != nil {
-		log.Error("hot2cold query cold wallet failed", "requestId", requestId, "address", balance.ToAddress, "err", err)
-		return err
+		return nil, err
 	}
+	return posting, nil
+}
 
-	hotWallet.Balance = new(big.Int).Sub(hotWallet.Balance, balance.Balance)
-	coldWallet.Balance = new(big.Int).Add(coldWallet.Balance, balance.Balance)
-
-	if err := db.UpdateAndSaveBalance(tx, requestId, hotWallet); err != nil {
-		log.Error("hot2cold update hot wallet balance failed", "requestId", requestId, "address", balance.ToAddress, "err", err)
-		return err
+// feePosting 钱包发出的交易从发送地址的原生币中扣除手续费，可用余额不足的部分先补记外部转入
+func (db balanceDB) feePosting(tx *gorm.DB, requestId string, balance *TokenBalance) (*ledgerPosting, error) {
+	posting := newLedgerPosting(LedgerSourceFee, balance.SourceGuid, balance.TxType, common.Address{}, balance.Chain, balance.BlockNumber)
+	if balance.Fee == nil || balance.Fee.Sign() <= 0 {
+		return posting, nil
 	}
-
-	if err := db.UpdateAndSaveBalance(tx, requestId, coldWallet); err != nil {
-		log.Error("hot2cold update cold wallet balance failed", "requestId", requestId, "address", balance.ToAddress, "err", err)
-		return err
+	fromType := AddressTypeHot
+	switch balance.TxType {
+	case TxTypeCollection:
+		fromType = AddressTypeEOA
+	case TxTypeCold2Hot:
+		fromType = AddressTypeCold
+	case TxTypeWithdraw, TxTypeHot2Cold, TxTypeGasTopUp:
+	default:
+		return posting, nil
 	}
-	return nil
-}
 
-func (db balanceDB) handleCold2Hot(tx *gorm.DB, requestId string, balance *TokenBalance) error {
-	hotWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, AddressTypeHot, balance.FromAddress, balance.TokenAddress)
+	current, err := db.projection(tx, requestId, balance.Chain, balance.FromAddress, fromType, common.Address{})
 	if err != nil {
-		log.Error("cold2hot query hot wallet failed", "requestId", requestId, "address", balance.FromAddress, "err", err)
-		return err
+		return nil, err
 	}
-
-	coldWallet, err := db.QueryWalletBalanceByTokenAndAddress(requestId, AddressTypeCold, balance.ToAddress, balance.TokenAddress)
-	if err != nil {
-		log.Error("cold2hot query cold wallet failed", "requestId", requestId, "address", balance.ToAddress, "err", err)
-		return err
+	if err := db.fundShortfall(tx, requestId, balance, balance.FromAddress, fromType, common.Address{}, current, balance.Fee); err != nil {
+		return nil, err
 	}
+	posting.move(
+		ledgerSide{address: balance.FromAddress, addressType: fromType, account: LedgerAccountAvailable},
+		ledgerSide{address: LedgerFeeAddress, addressType: fromType, account: LedgerAccountExternal},
+		balance.Fee,
+	)
+	return posting, nil
+}
 
-	hotWallet.Balance = new(big.Int).Add(hotWallet.Balance, balance.Balance)
-	coldWallet.Balance = new(big.Int).Sub(coldWallet.Balance, balance.Balance)
-
-	if err := db.UpdateAndSaveBalance(tx, requestId, hotWallet); err != nil {
-		log.Error("cold2hot update hot wallet balance failed", "requestId", requestId, "address", balance.ToAddress, "err", err)
-		return err
+// fundShortfall 链上已经转出但可用余额不足时，差额来自同步没有记账的外部转入（热钱包、冷钱包被直接充值等），
+// 补记一笔 funding 分录后再出账，余额投影不会小于 0，差额在账本中可查
+func (db balanceDB) fundShortfall(tx *gorm.DB, requestId string, balance *TokenBalance, address Address, addressType AddressType, tokenAddress common.Address, current *Balances, amount *big.Int) error {
+	if current.Balance.Cmp(amount) >= 0 {
+		return nil
 	}
-
-	if err := db.UpdateAndSaveBalance(tx, requestId, coldWallet); err != nil {
-		log.Error("cold2hot update cold wallet balance failed", "requestId", requestId, "address", balance.ToAddress, "err", err)
+	shortfall := new(big.Int).Sub(amount, current.Balance)
+	log.Warn("available balance less than outflow, record unsynced funding", "requestId", requestId, "address", address, "tokenAddress", tokenAddress, "balance", current.Balance, "amount", amount, "shortfall", shortfall)
+	posting := newLedgerPosting(LedgerSourceFunding, balance.SourceGuid, balance.TxType, tokenAddress, balance.Chain, balance.BlockNumber)
+	posting.move(
+		ledgerSide{address: LedgerFundingAddress, addressType: addressType, account: LedgerAccountExternal},
+		ledgerSide{address: address, addressType: addressType, account: LedgerAccountAvailable},
+		shortfall,
+	)
+	if err := db.post(tx, requestId, posting); err != nil {
 		return err
 	}
+	current.Balance = new(big.Int).Add(current.Balance, shortfall)
 	return nil
 }
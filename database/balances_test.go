@@ -0,0 +1,132 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JokingLove/multichain-sync-account/config"
+)
+
+// testBusinessTables 业务方的分表，按模板表建表
+var testBusinessTables = []string{
+	TableAddressesPrefix,
+	TableTokensPrefix,
+	TableBalancesPrefix,
+	TableDepositsPrefix,
+	TableTransactionsPrefix,
+	TableWithdrawsPrefix,
+	TableInternalsPrefix,
+	TableNftOwnershipsPrefix,
+	TableLedgerEntriesPrefix,
+	TableReconciliationsPrefix,
+	TableBalanceSnapshotsPrefix,
+	TableReplacementsPrefix,
+}
+
+// setupTestBusiness 连接测试数据库并为一个新的业务方建表，测试结束后删除；数据库不可用时跳过
+func setupTestBusiness(t *testing.T) (*DB, string) {
+	t.Helper()
+	dbConfig := config.DbConfigTest()
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", dbConfig.Host, dbConfig.Port), time.Second)
+	if err != nil {
+		t.Skipf("test database not available: %v", err)
+	}
+	conn.Close()
+
+	db := SetupDb()
+	require.NotNil(t, db)
+	require.NoError(t, db.ExecuteSQLMigration("../migrations"))
+
+	requestId := "test_" + strings.ReplaceAll(uuid.New().String(), "-", "")[:12]
+	for _, prefix := range testBusinessTables {
+		db.CreateTable.CreateTable(prefix+requestId, strings.TrimSuffix(prefix, "_"))
+	}
+	t.Cleanup(func() {
+		for _, prefix := range testBusinessTables {
+			db.gorm.Exec("drop table if exists " + prefix + requestId)
+		}
+		db.Close()
+	})
+	return db, requestId
+}
+
+func TestBalancesScopedByChain(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	user := AddressFormatEVM.Normalize("0x2000000000000000000000000000000000000002")
+	external := AddressFormatEVM.Normalize("0x4000000000000000000000000000000000000004")
+	deposit := func(chain string, amount int64) *TokenBalance {
+		return &TokenBalance{
+			FromAddress: external,
+			ToAddress:   user,
+			Balance:     big.NewInt(amount),
+			TxType:      TxTypeDeposit,
+			SourceGuid:  uuid.New(),
+			Chain:       chain,
+			BlockNumber: big.NewInt(10),
+		}
+	}
+	require.NoError(t, db.Balances.UpdateOrCreate(requestId, []*TokenBalance{deposit("Ethereum", 100), deposit("Polygon", 30)}))
+	require.NoError(t, db.Balances.UpdateBalanceListByTwoAddress(requestId, []*Balances{
+		{Chain: "Polygon", Address: user, LockBalance: big.NewInt(10), SourceGuid: uuid.New(), BlockNumber: big.NewInt(11)},
+	}))
+
+	assertBalances := func() {
+		ethereum, err := db.Balances.QueryWalletBalanceByTokenAndAddress(requestId, "Ethereum", AddressTypeEOA, user, common.Address{})
+		require.NoError(t, err)
+		require.Equal(t, "100", ethereum.Balance.String())
+		require.Equal(t, "0", ethereum.LockBalance.String())
+
+		polygon, err := db.Balances.QueryWalletBalanceByTokenAndAddress(requestId, "Polygon", AddressTypeEOA, user, common.Address{})
+		require.NoError(t, err)
+		require.Equal(t, "20", polygon.Balance.String())
+		require.Equal(t, "10", polygon.LockBalance.String())
+
+		collect, err := db.Balances.QueryCollectBalances(requestId, "Ethereum", common.Address{}, big.NewInt(50))
+		require.NoError(t, err)
+		require.Len(t, collect, 1)
+		require.Equal(t, "Ethereum", collect[0].Chain)
+	}
+	assertBalances()
+
+	require.NoError(t, db.Balances.RebuildBalances(requestId))
+	assertBalances()
+}
+
+func TestQueryBalanceReadOnly(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	user := AddressFormatEVM.Normalize("0x2000000000000000000000000000000000000002")
+	external := AddressFormatEVM.Normalize("0x4000000000000000000000000000000000000004")
+
+	balance, err := db.Balances.QueryBalance(requestId, "Ethereum", user, common.Address{})
+	require.NoError(t, err)
+	require.Equal(t, "0", balance.Balance.String())
+	require.Equal(t, "0", balance.LockBalance.String())
+	collect, err := db.Balances.QueryCollectBalances(requestId, "Ethereum", common.Address{}, big.NewInt(0))
+	require.NoError(t, err)
+	require.Empty(t, collect)
+
+	require.NoError(t, db.Balances.UpdateOrCreate(requestId, []*TokenBalance{{
+		FromAddress: external,
+		ToAddress:   user,
+		Balance:     big.NewInt(100),
+		TxType:      TxTypeDeposit,
+		SourceGuid:  uuid.New(),
+		Chain:       "Polygon",
+		BlockNumber: big.NewInt(10),
+	}}))
+	collect, err = db.Balances.QueryCollectBalances(requestId, "Ethereum", common.Address{}, big.NewInt(50))
+	require.NoError(t, err)
+	require.Empty(t, collect)
+	collect, err = db.Balances.QueryCollectBalances(requestId, "Polygon", common.Address{}, big.NewInt(50))
+	require.NoError(t, err)
+	require.Len(t, collect, 1)
+	require.Equal(t, user, collect[0].Address)
+}
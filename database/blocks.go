@@ -20,20 +20,34 @@ func BlockHeaderFromHeader(header *types.Header) rpcclient.BlockHeader {
 }
 
 type Blocks struct {
+	Chain      string      `gorm:"primaryKey" json:"chain"`
 	Hash       common.Hash `gorm:"primaryKey; serializer:bytes" json:"hash"`
 	ParentHash common.Hash `gorm:"serializer:bytes" json:"parent_hash"`
 	Number     *big.Int    `gorm:"serializer:u256" json:"number"`
 	Timestamp  uint64
 }
 
+func (b *Blocks) BlockHeader() *rpcclient.BlockHeader {
+	return &rpcclient.BlockHeader{
+		Hash:       b.Hash,
+		ParentHash: b.ParentHash,
+		Number:     b.Number,
+		Timestamp:  b.Timestamp,
+	}
+}
+
+// BlocksView 每条链的区块互不影响，chain 即配置中的链名称
 type BlocksView interface {
-	LatestBlocks() (*rpcclient.BlockHeader, error)
+	LatestBlocks(chain string) (*rpcclient.BlockHeader, error)
+	BlockHeaderByNumber(chain string, number *big.Int) (*rpcclient.BlockHeader, error)
+	BlockHeaderByTime(chain string, timestamp uint64) (*rpcclient.BlockHeader, error)
 }
 
 type BlocksDB interface {
 	BlocksView
 
 	StoreBlocks([]Blocks) error
+	DeleteBlocksAfter(chain string, number *big.Int) error
 }
 
 type blocksDB struct {
@@ -46,16 +60,47 @@ func NewBlocksDB(db *gorm.DB) BlocksDB {
 	}
 }
 
-func (b blocksDB) LatestBlocks() (*rpcclient.BlockHeader, error) {
+func (b blocksDB) LatestBlocks(chain string) (*rpcclient.BlockHeader, error) {
+	var header Blocks
+	result := b.gorm.Where("chain = ?", chain).Order("number desc").Take(&header)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return header.BlockHeader(), nil
+}
+
+func (b blocksDB) BlockHeaderByNumber(chain string, number *big.Int) (*rpcclient.BlockHeader, error) {
 	var header Blocks
-	result := b.gorm.Order("number desc").Take(&header)
+	result := b.gorm.Where("chain = ? and number = ?", chain, number.String()).Take(&header)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
 		return nil, result.Error
 	}
-	return (*rpcclient.BlockHeader)(&header), nil
+	return header.BlockHeader(), nil
+}
+
+// BlockHeaderByTime 该链出块时间不晚于 timestamp 的最新区块，没有时返回 nil
+func (b blocksDB) BlockHeaderByTime(chain string, timestamp uint64) (*rpcclient.BlockHeader, error) {
+	var header Blocks
+	result := b.gorm.Where("chain = ? and timestamp <= ?", chain, timestamp).Order("number desc").Take(&header)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return header.BlockHeader(), nil
+}
+
+// DeleteBlocksAfter 删除该链高度大于 number 的区块，用于回滚被重组掉的分叉
+func (b blocksDB) DeleteBlocksAfter(chain string, number *big.Int) error {
+	result := b.gorm.Where("chain = ? and number > ?", chain, number.String()).Delete(&Blocks{})
+	return result.Error
 }
 
 func (b blocksDB) StoreBlocks(blocks []Blocks) error {
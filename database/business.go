@@ -11,6 +11,7 @@ type Business struct {
 	GUID        uuid.UUID `gorm:"primaryKey" json:"guid"`
 	BusinessUid string    `json:"business_uid"`
 	NotifyUrl   string    `json:"notify_url"`
+	Classifier  string    `json:"classifier"`
 	Timestamp   uint64    `json:"timestamp"`
 }
 
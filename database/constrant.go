@@ -8,12 +8,18 @@ import (
 type TxStatus string
 
 const (
-	TxStatusCreateUnsigned TxStatus = "create_unsigned"
-	TxStatusSigned         TxStatus = "signed"
-	TxStatusBoradcasted    TxStatus = "boradcasted"
-	TxStatusWalletDone     TxStatus = "wallet_done"
-	TxStatusNotified       TxStatus = "notified"
-	TxStatusSuccess        TxStatus = "success"
+	TxStatusCreateUnsigned  TxStatus = "create_unsigned"
+	TxStatusSigned          TxStatus = "signed"
+	TxStatusBoradcasted     TxStatus = "boradcasted"
+	TxStatusWalletDone      TxStatus = "wallet_done"
+	TxStatusNotified        TxStatus = "notified"
+	TxStatusSuccess         TxStatus = "success"
+	TxStatusSuspense        TxStatus = "suspense" // 共享地址充值缺少或无法识别 memo，待人工审核
+	TxStatusFailed          TxStatus = "failed"   // 链上执行失败或回滚的交易，不入账
+	TxStatusFailedNotified  TxStatus = "failed_notified"
+	TxStatusBroadcastFailed TxStatus = "broadcast_failed" // 多次广播失败后放弃的交易，通知业务方；nonce 保留到链上 nonce 超过它
+	TxStatusQuarantine      TxStatus = "quarantine"       // 未在白名单中的代币充值，不入账也不通知，代币加入白名单后重放
+	TxStatusDust            TxStatus = "dust"             // 低于最小充值金额的充值，仅留存审计，不入账也不通知
 )
 
 type TokenType string
@@ -26,6 +32,25 @@ const (
 	TokenTypeERC1155 TokenType = "ERC1155"
 )
 
+// IsNft ERC721 和 ERC1155 按 token id 记录持有，不计入余额
+func (tt TokenType) IsNft() bool {
+	return tt == TokenTypeERC721 || tt == TokenTypeERC1155
+}
+
+// ParseTokenType 注册代币时未指定类型默认为 ERC20
+func ParseTokenType(s string) (TokenType, error) {
+	switch strings.ToUpper(s) {
+	case "", string(TokenTypeERC20):
+		return TokenTypeERC20, nil
+	case string(TokenTypeERC721):
+		return TokenTypeERC721, nil
+	case string(TokenTypeERC1155):
+		return TokenTypeERC1155, nil
+	default:
+		return TokenTypeERC20, fmt.Errorf("invalid token type: %s", s)
+	}
+}
+
 type AddressType string
 
 const (
@@ -61,6 +86,10 @@ const (
 	TxTypeCollection TransactionType = "collection"
 	TxTypeHot2Cold   TransactionType = "hot2cold"
 	TxTypeCold2Hot   TransactionType = "cold2hot"
+	TxTypeGasTopUp   TransactionType = "gas_topup" // 热钱包给用户地址补充归集代币所需的手续费
+
+	TxTypeInternalTransfer TransactionType = "internal_transfer"
+	TxTypeChange           TransactionType = "change" // UTXO 找零，只记流水不影响余额
 )
 
 func (tt TransactionType) string() string {
@@ -79,6 +108,12 @@ func ParseTransactionType(s string) (TransactionType, error) {
 		return TxTypeHot2Cold, nil
 	case string(TxTypeCold2Hot):
 		return TxTypeCold2Hot, nil
+	case string(TxTypeGasTopUp):
+		return TxTypeGasTopUp, nil
+	case string(TxTypeInternalTransfer):
+		return TxTypeInternalTransfer, nil
+	case string(TxTypeChange):
+		return TxTypeChange, nil
 	default:
 		return TxTypeUnknown, fmt.Errorf("unkown transaction type: %s", s)
 
@@ -86,12 +121,17 @@ func ParseTransactionType(s string) (TransactionType, error) {
 }
 
 const (
-	TableAddressesPrefix    = "addresses_"
-	TableTokensPrefix       = "tokens_"
-	TableDepositsPrefix     = "deposits_"
-	TableWithdrawsPrefix    = "withdraws_"
-	TableBusinessPrefix     = "business_"
-	TableTransactionsPrefix = "transactions_"
-	TableBalancesPrefix     = "balances_"
-	TableInternalsPrefix    = "internals_"
+	TableAddressesPrefix        = "addresses_"
+	TableTokensPrefix           = "tokens_"
+	TableDepositsPrefix         = "deposits_"
+	TableWithdrawsPrefix        = "withdraws_"
+	TableBusinessPrefix         = "business_"
+	TableTransactionsPrefix     = "transactions_"
+	TableBalancesPrefix         = "balances_"
+	TableInternalsPrefix        = "internals_"
+	TableNftOwnershipsPrefix    = "nft_ownerships_"
+	TableLedgerEntriesPrefix    = "ledger_entries_"
+	TableReconciliationsPrefix  = "reconciliations_"
+	TableBalanceSnapshotsPrefix = "balance_snapshots_"
+	TableReplacementsPrefix     = "replacements_"
 )
@@ -30,6 +30,12 @@ type DB struct {
 	Trasactions TransactionsDB
 	Internals   InternalsDB
 	Withdraws   WithdrawDB
+	Nfts        NftOwnershipsDB
+	Ledger      LedgerEntriesView
+	Reconciles  ReconciliationsDB
+	Snapshots   BalanceSnapshotsDB
+	Nonces      NoncesDB
+	Replaces    ReplacementsDB
 }
 
 func NewDB(ctx context.Context, dbConfig config.DBConfig) (*DB, error) {
@@ -75,29 +81,34 @@ func NewDB(ctx context.Context, dbConfig config.DBConfig) (*DB, error) {
 		return nil, err
 	}
 
-	db := &DB{
-		gorm:        gormDbBox,
-		CreateTable: NewCreateTableDB(gormDbBox),
-		Blocks:      NewBlocksDB(gormDbBox),
-		Addresses:   NewAddressesDB(gormDbBox),
-		Balances:    NewBalancesDB(gormDbBox),
-		Deposits:    NewDepositsDB(gormDbBox),
-		Tokens:      NewTokensDB(gormDbBox),
-		Business:    NewBusinessDB(gormDbBox),
-		Withdraws:   NewWithdrawDB(gormDbBox),
-		Trasactions: NewTransactionsDB(gormDbBox),
-		Internals:   NewInternalsDB(gormDbBox),
-	}
+	return newDBFromGorm(gormDbBox), nil
+}
 
-	return db, nil
+func newDBFromGorm(gormDb *gorm.DB) *DB {
+	return &DB{
+		gorm:        gormDb,
+		CreateTable: NewCreateTableDB(gormDb),
+		Blocks:      NewBlocksDB(gormDb),
+		Addresses:   NewAddressesDB(gormDb),
+		Balances:    NewBalancesDB(gormDb),
+		Deposits:    NewDepositsDB(gormDb),
+		Tokens:      NewTokensDB(gormDb),
+		Business:    NewBusinessDB(gormDb),
+		Withdraws:   NewWithdrawDB(gormDb),
+		Trasactions: NewTransactionsDB(gormDb),
+		Internals:   NewInternalsDB(gormDb),
+		Nfts:        NewNftOwnershipsDB(gormDb),
+		Ledger:      NewLedgerEntriesDB(gormDb),
+		Reconciles:  NewReconciliationsDB(gormDb),
+		Snapshots:   NewBalanceSnapshotsDB(gormDb),
+		Nonces:      NewNoncesDB(gormDb),
+		Replaces:    NewReplacementsDB(gormDb),
+	}
 }
 
 func (db *DB) Transaction(fn func(db *DB) error) error {
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
-		txDB := &DB{
-			gorm: tx,
-		}
-		return fn(txDB)
+		return fn(newDBFromGorm(tx))
 	})
 
 }
@@ -131,3 +142,55 @@ func (db *DB) ExecuteSQLMigration(migrationsFolder string) error {
 	})
 	return err
 }
+
+// legacyAddressColumns 旧版本按 common.Address 保存地址的业务表和地址字段
+var legacyAddressColumns = map[string][]string{
+	TableAddressesPrefix:    {"address"},
+	TableBalancesPrefix:     {"address"},
+	TableDepositsPrefix:     {"from_address", "to_address"},
+	TableWithdrawsPrefix:    {"from_address", "to_address"},
+	TableInternalsPrefix:    {"from_address", "to_address"},
+	TableTransactionsPrefix: {"from_address", "to_address"},
+}
+
+// NormalizeStoredAddresses 旧数据中 0x 开头的十六进制地址按 AddressFormatEVM 统一为 checksum 格式，
+// 和同步、接口写入的地址保持一致，否则按地址等值查询匹配不到旧数据；已规范化的地址不会被修改，可以重复执行
+func (db *DB) NormalizeStoredAddresses() error {
+	businessList, err := db.Business.QueryBusinessList()
+	if err != nil {
+		return err
+	}
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, business := range businessList {
+			for prefix, columns := range legacyAddressColumns {
+				for _, column := range columns {
+					if err := normalizeAddressColumn(tx, prefix+business.BusinessUid, column); err != nil {
+						return errors.Wrap(err, fmt.Sprintf("failed to normalize %s.%s", prefix+business.BusinessUid, column))
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func normalizeAddressColumn(tx *gorm.DB, tableName string, column string) error {
+	var addresses []string
+	err := tx.Table(tableName).
+		Where(column+" ~* ?", "^0x[0-9a-f]{40}$").
+		Distinct(column).
+		Pluck(column, &addresses).Error
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		normalized := AddressFormatEVM.Normalize(address).String()
+		if normalized == address {
+			continue
+		}
+		if err := tx.Table(tableName).Where(column+" = ?", address).Update(column, normalized).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
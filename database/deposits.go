@@ -3,7 +3,7 @@ package database
 import (
 	"fmt"
 	"math/big"
-	
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/google/uuid"
@@ -16,15 +16,23 @@ type Deposits struct {
 	Timestamp uint64    `gorm:"not null; check: timestamp > 0" json:"timestamp"`
 	Status    TxStatus  `gorm:"type:varchar(10);not null" json:"status"`
 	Confirms  uint8     `gorm:"not null; default 0" json:"confirms"`
+	Chain     string    `gorm:"type:varchar;not null" json:"chain"`
+
+	// NotifiedConfirms 最近一次通知业务方时的确认数
+	NotifiedConfirms uint8 `gorm:"not null; default 0" json:"notified_confirms"`
 
 	BlockHash   common.Hash     `gorm:"type:varchar;not null;serializer:bytes" json:"block_hash"`
 	BlockNumber *big.Int        `gorm:"not null; check: block_number > 0; serializer: u256" json:"block_number"`
 	TxHash      common.Hash     `gorm:"type:varchar;not null;serializer:bytes" json:"tx_hash"`
 	TxType      TransactionType `gorm:"type:varchar;not null" json:"tx_type"`
 
-	FromAddress common.Address `gorm:"type:varchar;not null;serializer:bytes" json:"from_address"`
-	ToAddress   common.Address `gorm:"type:varchar;not null;serializer:bytes" json:"to_address"`
-	Amount      *big.Int       `gorm:"not null;serializer:u256" json:"amount"`
+	// TransferIndex 转账在交易内的序号，链上充值按 (tx_hash, transfer_index) 区分同一交易中的多笔转账
+	TransferIndex uint32 `gorm:"not null;default:0" json:"transfer_index"`
+
+	FromAddress Address  `gorm:"type:varchar;not null" json:"from_address"`
+	ToAddress   Address  `gorm:"type:varchar;not null" json:"to_address"`
+	Amount      *big.Int `gorm:"not null;serializer:u256" json:"amount"`
+	Memo        string   `gorm:"type:varchar;not null" json:"memo"`
 
 	GasLimit          uint64 `gorm:"not null" json:"gas_limit"`
 	MaxFeePerGas      string `gorm:"type:varchar;not null" json:"max_fee_per_gas"`
@@ -42,18 +50,23 @@ type DepositsView interface {
 	QueryNotifyDeposits(requestId string) ([]*Deposits, error)
 	QueryDepositsByTxHash(requestId string, txHash common.Hash) (*Deposits, error)
 	QueryDepositsById(requestId string, guid string) (*Deposits, error)
+	QuerySuspenseDeposits(requestId string) ([]*Deposits, error)
+	QueryQuarantineDeposits(requestId string, tokenAddress common.Address) ([]*Deposits, error)
+	QueryConfirmChangedDeposits(requestId string) ([]*Deposits, error)
 }
 
 type DepositsDB interface {
 	DepositsView
 
 	StoreDeposits(string, []*Deposits) error
-	UpdateDepositsConfirms(requestId string, blockNumber uint64, confirms uint64) error
+	UpdateDepositsConfirms(requestId string, chain string, blockNumber uint64, confirms uint64) error
 	UpdateDepositById(requestId string, guid string, signedTx string, status TxStatus) error
 	UpdateDepositsStatusById(requestId string, status TxStatus, depositsList []*Deposits) error
 	UpdateDepositsStatusByTxHash(requestId string, status TxStatus, depositsList []*Deposits) error
 	UpdateDepositListByTxHash(requestId string, depositsList []*Deposits) error
 	UpdateDepositListById(requestId string, depositsList []*Deposits) error
+	DeleteDepositsAfterBlock(requestId string, chain string, blockNumber *big.Int) error
+	MarkDepositsConfirmsNotified(requestId string, depositsList []*Deposits) error
 }
 
 type depositsDB struct {
@@ -70,7 +83,7 @@ func (db depositsDB) QueryNotifyDeposits(requestId string) ([]*Deposits, error)
 	var deposits []*Deposits
 	result := db.gorm.Table(TableDepositsPrefix+requestId).
 		Where("status = ? or status = ? ", TxStatusWalletDone, TxStatusNotified).
-		Find(deposits)
+		Find(&deposits)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -109,6 +122,32 @@ func (db depositsDB) QueryDepositsById(requestId string, guid string) (*Deposits
 	return deposits, nil
 }
 
+// QuerySuspenseDeposits 查询 memo 缺失或无法识别、等待人工审核的充值
+func (db depositsDB) QuerySuspenseDeposits(requestId string) ([]*Deposits, error) {
+	var deposits []*Deposits
+	result := db.gorm.Table(TableDepositsPrefix+requestId).
+		Where("status = ?", TxStatusSuspense).
+		Order("timestamp desc").
+		Find(&deposits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return deposits, nil
+}
+
+// QueryQuarantineDeposits 查询代币不在白名单中而被隔离的充值
+func (db depositsDB) QueryQuarantineDeposits(requestId string, tokenAddress common.Address) ([]*Deposits, error) {
+	var deposits []*Deposits
+	result := db.gorm.Table(TableDepositsPrefix+requestId).
+		Where("status = ? and token_address = ?", TxStatusQuarantine, tokenAddress.String()).
+		Order("block_number asc").
+		Find(&deposits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return deposits, nil
+}
+
 func (db depositsDB) StoreDeposits(requestId string, deposits []*Deposits) error {
 	if len(deposits) == 0 {
 		return nil
@@ -122,11 +161,11 @@ func (db depositsDB) StoreDeposits(requestId string, deposits []*Deposits) error
 }
 
 // 查询所有还没有过确认位的交易，用最新的区块减去对应区块更新确认，如果这个大于我们预设的确认位，那么这笔交易可以认为已经入账
-func (db depositsDB) UpdateDepositsConfirms(requestId string, blockNumber uint64, confirms uint64) error {
+func (db depositsDB) UpdateDepositsConfirms(requestId string, chain string, blockNumber uint64, confirms uint64) error {
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		var unConfirmDeposits []*Deposits
 		result := tx.Table(TableDepositsPrefix+requestId).
-			Where("block_number <= ? and status = ?", blockNumber, TxStatusBoradcasted).
+			Where("chain = ? and block_number <= ? and status = ?", chain, blockNumber, TxStatusBoradcasted).
 			Find(&unConfirmDeposits)
 		if result.Error != nil {
 			return result.Error
@@ -134,13 +173,17 @@ func (db depositsDB) UpdateDepositsConfirms(requestId string, blockNumber uint64
 
 		for _, deposit := range unConfirmDeposits {
 			chainConfirm := blockNumber - deposit.BlockNumber.Uint64()
+			status := deposit.Status
 			if chainConfirm >= confirms {
-				deposit.Confirms = uint8(confirms)
-				deposit.Status = TxStatusWalletDone
-			} else {
-				deposit.Confirms = uint8(chainConfirm)
+				chainConfirm = confirms
+				status = TxStatusWalletDone
+			}
+			if uint8(chainConfirm) == deposit.Confirms && status == deposit.Status {
+				continue
 			}
 
+			deposit.Confirms = uint8(chainConfirm)
+			deposit.Status = status
 			if err := tx.Table(TableDepositsPrefix + requestId).Save(deposit).Error; err != nil {
 				return err
 			}
@@ -149,6 +192,45 @@ func (db depositsDB) UpdateDepositsConfirms(requestId string, blockNumber uint64
 	})
 }
 
+// QueryConfirmChangedDeposits 查询确认数比上次通知时有变化、但还没有达到确认数的充值
+func (db depositsDB) QueryConfirmChangedDeposits(requestId string) ([]*Deposits, error) {
+	var deposits []*Deposits
+	result := db.gorm.Table(TableDepositsPrefix+requestId).
+		Where("status = ? and confirms <> notified_confirms", TxStatusBoradcasted).
+		Find(&deposits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return deposits, nil
+}
+
+// MarkDepositsConfirmsNotified 记录已经通知给业务方的确认数
+func (db depositsDB) MarkDepositsConfirmsNotified(requestId string, depositsList []*Deposits) error {
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, deposit := range depositsList {
+			result := tx.Table(TableDepositsPrefix+requestId).
+				Where("guid = ?", deposit.GUID).
+				Update("notified_confirms", deposit.Confirms)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteDepositsAfterBlock 删除链上同步到的、高度大于 blockNumber 的充值记录，用于链重组回滚
+func (db depositsDB) DeleteDepositsAfterBlock(requestId string, chain string, blockNumber *big.Int) error {
+	result := db.gorm.Table(TableDepositsPrefix+requestId).
+		Where("chain = ? and block_number > ? and status not in ?", chain, blockNumber.String(), []TxStatus{TxStatusCreateUnsigned, TxStatusSigned}).
+		Delete(&Deposits{})
+	if result.Error != nil {
+		return result.Error
+	}
+	log.Info("Rollback deposits success", "requestId", requestId, "chain", chain, "blockNumber", blockNumber, "count", result.RowsAffected)
+	return nil
+}
+
 func (db depositsDB) UpdateDepositById(requestId string, guid string, signedTx string, status TxStatus) error {
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		var deposits Deposits
@@ -198,7 +280,7 @@ func (db depositsDB) UpdateDepositsStatusByTxHash(requestId string, status TxSta
 	if len(depositsList) == 0 {
 		return nil
 	}
-	tableName := fmt.Sprintf("%s_%s", TableDepositsPrefix, requestId)
+	tableName := TableDepositsPrefix + requestId
 
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		var txHashList []string
@@ -229,7 +311,7 @@ func (db depositsDB) UpdateDepositListByTxHash(requestId string, depositsList []
 	if len(depositsList) == 0 {
 		return nil
 	}
-	tableName := fmt.Sprintf("%s_%s", TableDepositsPrefix, requestId)
+	tableName := TableDepositsPrefix + requestId
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		for _, deposit := range depositsList {
 			result := tx.Table(tableName).
@@ -260,7 +342,7 @@ func (db depositsDB) UpdateDepositListById(requestId string, depositsList []*Dep
 		return nil
 	}
 
-	tableName := fmt.Sprintf("%s_%s", TableDepositsPrefix, requestId)
+	tableName := TableDepositsPrefix + requestId
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		for _, deposit := range depositsList {
 			result := tx.Table(tableName).
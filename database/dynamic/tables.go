@@ -13,6 +13,11 @@ func CreateTableFromTemplate(requestId string, db *database.DB) {
 	createTransactions(requestId, db)
 	createWithdraws(requestId, db)
 	createInternals(requestId, db)
+	createNftOwnerships(requestId, db)
+	createLedgerEntries(requestId, db)
+	createReconciliations(requestId, db)
+	createBalanceSnapshots(requestId, db)
+	createReplacements(requestId, db)
 }
 
 func createAddresses(requestId string, db *database.DB) {
@@ -55,3 +60,33 @@ func createInternals(requestId string, db *database.DB) {
 	tableNameByChainId := fmt.Sprintf("internals_%s", requestId)
 	db.CreateTable.CreateTable(tableNameByChainId, tableName)
 }
+
+func createNftOwnerships(requestId string, db *database.DB) {
+	tableName := "nft_ownerships"
+	tableNameByChainId := fmt.Sprintf("nft_ownerships_%s", requestId)
+	db.CreateTable.CreateTable(tableNameByChainId, tableName)
+}
+
+func createLedgerEntries(requestId string, db *database.DB) {
+	tableName := "ledger_entries"
+	tableNameByChainId := fmt.Sprintf("ledger_entries_%s", requestId)
+	db.CreateTable.CreateTable(tableNameByChainId, tableName)
+}
+
+func createReconciliations(requestId string, db *database.DB) {
+	tableName := "reconciliations"
+	tableNameByChainId := fmt.Sprintf("reconciliations_%s", requestId)
+	db.CreateTable.CreateTable(tableNameByChainId, tableName)
+}
+
+func createBalanceSnapshots(requestId string, db *database.DB) {
+	tableName := "balance_snapshots"
+	tableNameByChainId := fmt.Sprintf("balance_snapshots_%s", requestId)
+	db.CreateTable.CreateTable(tableNameByChainId, tableName)
+}
+
+func createReplacements(requestId string, db *database.DB) {
+	tableName := "replacements"
+	tableNameByChainId := fmt.Sprintf("replacements_%s", requestId)
+	db.CreateTable.CreateTable(tableNameByChainId, tableName)
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
@@ -17,6 +18,7 @@ type Internals struct {
 	GUID      uuid.UUID `gorm:"primaryKey;not null" json:"guid"`
 	Timestamp uint64    `gorm:"not null" json:"timestamp"`
 	Status    TxStatus  `gorm:"not null" json:"status"`
+	Chain     string    `gorm:"not null" json:"chain"`
 
 	// 区块信息
 	BlockHash   common.Hash     `gorm:"column:block_hash;serializer:bytes" json:"block_hash"`
@@ -25,9 +27,9 @@ type Internals struct {
 	TxType      TransactionType `gorm:"column:tx_type; not null" json:"tx_type"`
 
 	// 交易基础信息
-	FromAddress common.Address `gorm:"column:from_address;serializer:bytes" json:"from_address"`
-	ToAddress   common.Address `gorm:"column:to_address;serializer:bytes" json:"to_address"`
-	Amount      *big.Int       `gorm:"column:amount;serializer:u256" json:"amount"`
+	FromAddress Address  `gorm:"column:from_address" json:"from_address"`
+	ToAddress   Address  `gorm:"column:to_address" json:"to_address"`
+	Amount      *big.Int `gorm:"column:amount;serializer:u256" json:"amount"`
 
 	// Gas 费用
 	GasLimit             uint64 `gorm:"column:gas_limit" json:"gas_limit"`
@@ -40,26 +42,44 @@ type Internals struct {
 	TokenId      string         `json:"token_id"`   // ERC721 / ERC1155 的 token id
 	TokenMeta    string         `json:"token_meta"` // Token 元数据
 
-	// 交易签名
+	// 构建的待签名交易和交易签名
+	UnSignTx  string `gorm:"column:un_sign_tx" json:"un_sign_tx"`
 	TxSignHex string `gorm:"column:tx_sign_hex" json:"tx_sign_hex"`
+
+	// 广播时已同步的最新区块，用于判断交易是否卡住
+	BroadcastBlock *big.Int `gorm:"column:broadcast_block;serializer:u256" json:"broadcast_block"`
+
+	// 广播重试状态
+	BroadcastAttempts uint64 `gorm:"column:broadcast_attempts" json:"broadcast_attempts"`
+	LastError         string `gorm:"column:last_error" json:"last_error"`
+	NextRetryAt       uint64 `gorm:"column:next_retry_at" json:"next_retry_at"`
 }
 
 type InternalsView interface {
 	QueryNotifyInternals(requestId string) ([]*Internals, error)
 	QueryInternalByTxHash(requestId string, txHash common.Hash) (*Internals, error)
 	QueryInternalById(requestId string, guid string) (*Internals, error)
-	UnSendInternalList(requestId string) ([]*Internals, error)
+	UnSendInternalList(requestId string, chain string) ([]*Internals, error)
+	QueryFailedInternals(requestId string) ([]*Internals, error)
+	QueryPendingInternals(requestId string, chain string) ([]*Internals, error)
+	QueryUnsignedInternals(requestId string, chain string) ([]*Internals, error)
+	QueryStuckInternals(requestId string, chain string, number *big.Int) ([]*Internals, error)
+	QueryBroadcastFailedInternals(requestId string) ([]*Internals, error)
 }
 
 type InternalsDB interface {
 	InternalsView
 
 	StoreInternal(string, *Internals) error
+	StoreInternals(string, []*Internals) error
 	UpdateInternalByTxHash(requestId string, txHash common.Hash, signedTx string, status TxStatus) error
 	UpdateInternalById(requestId string, guid string, signedTx string, status TxStatus) error
 	UpdateInternalStatusByTxHash(requestId string, status TxStatus, internalsList []*Internals) error
 	UpdateInternalListByHash(requestId string, internalsList []*Internals) error
 	UpdateInternalListById(requestId string, internalsList []*Internals) error
+	UpdateInternalStatusById(requestId string, status TxStatus, internalsList []*Internals) error
+	UpdateInternalTxHashById(requestId string, guid string, txHash common.Hash) error
+	UpdateInternalUnSignTx(requestId string, guid string, unSignTx string) error
 }
 
 type internalsDB struct {
@@ -89,6 +109,18 @@ func (db internalsDB) QueryNotifyInternals(requestId string) ([]*Internals, erro
 	return notifyInternals, nil
 }
 
+// QueryFailedInternals 查询链上执行失败、还没有通知业务方的归集和冷热钱包划转
+func (db internalsDB) QueryFailedInternals(requestId string) ([]*Internals, error) {
+	var failedInternals []*Internals
+	result := db.gorm.Table(TableInternalsPrefix+requestId).
+		Where("status = ?", TxStatusFailed).
+		Find(&failedInternals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return failedInternals, nil
+}
+
 func (db internalsDB) QueryInternalByTxHash(requestId string, txHash common.Hash) (*Internals, error) {
 	var internals Internals
 	result := db.gorm.Table(TableInternalsPrefix+requestId).
@@ -117,10 +149,59 @@ func (db internalsDB) QueryInternalById(requestId string, guid string) (*Interna
 	return &internals, nil
 }
 
-func (db internalsDB) UnSendInternalList(requestId string) ([]*Internals, error) {
+func (db internalsDB) UnSendInternalList(requestId string, chain string) ([]*Internals, error) {
+	var internals []*Internals
+	result := db.gorm.Table(TableInternalsPrefix+requestId).
+		Where("chain = ? and status = ? and next_retry_at <= ?", chain, TxStatusSigned, time.Now().Unix()).
+		Find(&internals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return internals, nil
+}
+
+// QueryPendingInternals 查询还没有上链的内部交易，包括待签名、已签名和已广播的
+func (db internalsDB) QueryPendingInternals(requestId string, chain string) ([]*Internals, error) {
+	var internals []*Internals
+	result := db.gorm.Table(TableInternalsPrefix+requestId).
+		Where("chain = ? and status in ?", chain, []TxStatus{TxStatusCreateUnsigned, TxStatusSigned, TxStatusBoradcasted}).
+		Find(&internals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return internals, nil
+}
+
+// QueryUnsignedInternals 查询等待业务方签名的内部交易
+func (db internalsDB) QueryUnsignedInternals(requestId string, chain string) ([]*Internals, error) {
+	var internals []*Internals
+	result := db.gorm.Table(TableInternalsPrefix+requestId).
+		Where("chain = ? and status = ?", chain, TxStatusCreateUnsigned).
+		Order("timestamp asc").
+		Find(&internals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return internals, nil
+}
+
+// QueryBroadcastFailedInternals 查询多次广播失败、还没有通知业务方的内部交易
+func (db internalsDB) QueryBroadcastFailedInternals(requestId string) ([]*Internals, error) {
 	var internals []*Internals
 	result := db.gorm.Table(TableInternalsPrefix+requestId).
-		Where("status = ?", TxStatusSigned).
+		Where("status = ?", TxStatusBroadcastFailed).
+		Find(&internals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return internals, nil
+}
+
+// QueryStuckInternals 查询已广播但还没有上链、且广播区块不晚于 number 的内部交易
+func (db internalsDB) QueryStuckInternals(requestId string, chain string, number *big.Int) ([]*Internals, error) {
+	var internals []*Internals
+	result := db.gorm.Table(TableInternalsPrefix+requestId).
+		Where("chain = ? and status = ? and broadcast_block > 0 and broadcast_block <= ?", chain, TxStatusBoradcasted, number.String()).
 		Find(&internals)
 	if result.Error != nil {
 		return nil, result.Error
@@ -129,7 +210,15 @@ func (db internalsDB) UnSendInternalList(requestId string) ([]*Internals, error)
 }
 
 func (db internalsDB) StoreInternal(requestId string, internals *Internals) error {
-	return db.gorm.Create(internals).Error
+	return db.gorm.Table(TableInternalsPrefix + requestId).Create(internals).Error
+}
+
+func (db internalsDB) StoreInternals(requestId string, internalsList []*Internals) error {
+	if len(internalsList) == 0 {
+		return nil
+	}
+	return db.gorm.Table(TableInternalsPrefix+requestId).
+		CreateInBatches(internalsList, len(internalsList)).Error
 }
 
 func (db internalsDB) UpdateInternalByTxHash(requestId string, txHash common.Hash, signedTx string, status TxStatus) error {
@@ -210,6 +299,29 @@ func (db internalsDB) UpdateInternalStatusByTxHash(requestId string, status TxSt
 	})
 }
 
+// UpdateInternalStatusById 按 guid 批量更新状态，用于还没有交易哈希的内部交易
+func (db internalsDB) UpdateInternalStatusById(requestId string, status TxStatus, internalsList []*Internals) error {
+	if len(internalsList) == 0 {
+		return nil
+	}
+	var guids []uuid.UUID
+	for _, internals := range internalsList {
+		guids = append(guids, internals.GUID)
+	}
+	result := db.gorm.Table(TableInternalsPrefix+requestId).
+		Where("guid IN (?)", guids).
+		Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("batch update status failed: %w", result.Error)
+	}
+	log.Info("Batch update internals status success",
+		"requestId", requestId,
+		"count", result.RowsAffected,
+		"status", status,
+	)
+	return nil
+}
+
 func (db internalsDB) UpdateInternalListByHash(requestId string, internalsList []*Internals) error {
 	if len(internalsList) == 0 {
 		return nil
@@ -249,12 +361,20 @@ func (db internalsDB) UpdateInternalListById(requestId string, internalsList []*
 
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		for _, internals := range internalsList {
+			updates := map[string]interface{}{
+				"status":             internals.Status,
+				"amount":             internals.Amount,
+				"hash":               internals.TxHash.String(),
+				"broadcast_attempts": internals.BroadcastAttempts,
+				"last_error":         internals.LastError,
+				"next_retry_at":      internals.NextRetryAt,
+			}
+			if internals.BroadcastBlock != nil {
+				updates["broadcast_block"] = internals.BroadcastBlock
+			}
 			result := tx.Table(tableName).
 				Where("guid = ?", internals.GUID.String()).
-				Updates(map[string]interface{}{
-					"status": internals.Status,
-					"amount": internals.Amount,
-				})
+				Updates(updates)
 
 			if result.Error != nil {
 				return fmt.Errorf("update failed by id   %s : %w", internals.GUID.String(), result.Error)
@@ -269,3 +389,17 @@ func (db internalsDB) UpdateInternalListById(requestId string, internalsList []*
 		return nil
 	})
 }
+
+// UpdateInternalTxHashById 签名后保存本地计算的交易哈希；替换交易上链后改为上链的哈希，后续按哈希更新状态
+func (db internalsDB) UpdateInternalTxHashById(requestId string, guid string, txHash common.Hash) error {
+	return db.gorm.Table(TableInternalsPrefix+requestId).
+		Where("guid = ?", guid).
+		Update("hash", txHash.String()).Error
+}
+
+// UpdateInternalUnSignTx 保存构建的待签名交易，业务方查询待签名内部交易时原样返回
+func (db internalsDB) UpdateInternalUnSignTx(requestId string, guid string, unSignTx string) error {
+	return db.gorm.Table(TableInternalsPrefix+requestId).
+		Where("guid = ?", guid).
+		Update("un_sign_tx", unSignTx).Error
+}
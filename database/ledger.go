@@ -0,0 +1,309 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LedgerAccount 账本科目：available 可用余额，locked 锁定余额，external 链外的对手方（外部地址、手续费、期初余额）
+type LedgerAccount string
+
+const (
+	LedgerAccountAvailable LedgerAccount = "available"
+	LedgerAccountLocked    LedgerAccount = "locked"
+	LedgerAccountExternal  LedgerAccount = "external"
+)
+
+// LedgerDirection 地址科目借方增加、贷方减少
+type LedgerDirection string
+
+const (
+	LedgerDebit  LedgerDirection = "debit"
+	LedgerCredit LedgerDirection = "credit"
+)
+
+// LedgerSource 分录的来源
+type LedgerSource string
+
+const (
+	LedgerSourceTransfer LedgerSource = "transfer" // 链上转账入账
+	LedgerSourceFee      LedgerSource = "fee"      // 钱包发出交易的链上手续费
+	LedgerSourceLock     LedgerSource = "lock"     // 提现和内部交易广播时锁定
+	LedgerSourceUnlock   LedgerSource = "unlock"   // 链上失败时释放锁定
+	LedgerSourceReversal LedgerSource = "reversal" // 链重组撤销已入账的分录
+	LedgerSourceOpening  LedgerSource = "opening"  // 启用账本前已有的余额
+	LedgerSourceFunding  LedgerSource = "funding"  // 没有同步记账的外部转入，出账时按可用余额的差额补记
+)
+
+// 手续费的对手方地址
+const LedgerFeeAddress Address = "fee"
+
+// 没有同步记账的外部转入的对手方地址，例如直接从交易所转入热钱包、冷钱包的资金
+const LedgerFundingAddress Address = "funding"
+
+// LedgerEntries 只追加的复式记账分录，同一 PostingId 下借贷金额相等；balances 是按地址和代币汇总分录得到的投影。
+// Chain、BlockNumber 为分录生效的链和区块，按链和区块汇总分录得到历史余额
+type LedgerEntries struct {
+	GUID         uuid.UUID       `gorm:"primaryKey" json:"guid"`
+	Seq          uint64          `gorm:"column:seq;->" json:"seq"` // 写入顺序，由数据库分配
+	PostingId    uuid.UUID       `gorm:"not null" json:"posting_id"`
+	SourceGuid   string          `gorm:"type:varchar;not null" json:"source_guid"`
+	SourceType   LedgerSource    `gorm:"type:varchar;not null" json:"source_type"`
+	TxType       TransactionType `gorm:"type:varchar;not null" json:"tx_type"`
+	Chain        string          `gorm:"type:varchar;not null" json:"chain"`
+	BlockNumber  *big.Int        `gorm:"not null;serializer:u256" json:"block_number"`
+	Address      Address         `gorm:"type:varchar;not null" json:"address"`
+	AddressType  AddressType     `gorm:"type:varchar(10);not null" json:"address_type"`
+	TokenAddress common.Address  `gorm:"serializer:bytes" json:"token_address"`
+	Account      LedgerAccount   `gorm:"type:varchar;not null" json:"account"`
+	Direction    LedgerDirection `gorm:"type:varchar;not null" json:"direction"`
+	Amount       *big.Int        `gorm:"not null;serializer:u256" json:"amount"`
+	Timestamp    uint64          `gorm:"not null" json:"timestamp"`
+}
+
+type LedgerEntriesView interface {
+	QueryLedgerEntries(requestId string, address Address, tokenAddress common.Address) ([]*LedgerEntries, error)
+	QueryLedgerEntriesBySource(requestId string, sourceGuid string) ([]*LedgerEntries, error)
+}
+
+type ledgerEntriesDB struct {
+	gorm *gorm.DB
+}
+
+func NewLedgerEntriesDB(db *gorm.DB) LedgerEntriesView {
+	return &ledgerEntriesDB{gorm: db}
+}
+
+// QueryLedgerEntries 按记账顺序查询地址某个代币的全部分录
+func (db ledgerEntriesDB) QueryLedgerEntries(requestId string, address Address, tokenAddress common.Address) ([]*LedgerEntries, error) {
+	var entries []*LedgerEntries
+	err := db.gorm.Table(TableLedgerEntriesPrefix+requestId).
+		Where("address = ? and token_address = ?", address.String(), tokenAddress.String()).
+		Order("seq asc").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (db ledgerEntriesDB) QueryLedgerEntriesBySource(requestId string, sourceGuid string) ([]*LedgerEntries, error) {
+	var entries []*LedgerEntries
+	err := db.gorm.Table(TableLedgerEntriesPrefix+requestId).
+		Where("source_guid = ?", sourceGuid).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ledgerSide 一笔记账中一方的地址和科目
+type ledgerSide struct {
+	address     Address
+	addressType AddressType
+	account     LedgerAccount
+}
+
+// ledgerPosting 一笔借贷平衡的记账
+type ledgerPosting struct {
+	id         uuid.UUID
+	source     LedgerSource
+	sourceGuid string
+	txType     TransactionType
+	token      common.Address
+	chain      string
+	number     *big.Int
+	entries    []*LedgerEntries
+}
+
+func newLedgerPosting(source LedgerSource, sourceGuid uuid.UUID, txType TransactionType, token common.Address, chain string, number *big.Int) *ledgerPosting {
+	return &ledgerPosting{
+		id:         uuid.New(),
+		source:     source,
+		sourceGuid: sourceGuid.String(),
+		txType:     txType,
+		token:      token,
+		chain:      chain,
+		number:     number,
+	}
+}
+
+func (p *ledgerPosting) add(side ledgerSide, direction LedgerDirection, amount *big.Int) {
+	if amount == nil || amount.Sign() == 0 {
+		return
+	}
+	p.entries = append(p.entries, &LedgerEntries{
+		GUID:         uuid.New(),
+		PostingId:    p.id,
+		SourceGuid:   p.sourceGuid,
+		SourceType:   p.source,
+		TxType:       p.txType,
+		Address:      side.address,
+		AddressType:  side.addressType,
+		TokenAddress: p.token,
+		Chain:        p.chain,
+		BlockNumber:  blockNumberOrZero(p.number),
+		Account:      side.account,
+		Direction:    direction,
+		Amount:       new(big.Int).Set(amount),
+		Timestamp:    uint64(time.Now().Unix()),
+	})
+}
+
+func blockNumberOrZero(number *big.Int) *big.Int {
+	if number == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(number)
+}
+
+// move 贷记 from、借记 to
+func (p *ledgerPosting) move(from, to ledgerSide, amount *big.Int) {
+	p.add(from, LedgerCredit, amount)
+	p.add(to, LedgerDebit, amount)
+}
+
+func (p *ledgerPosting) balanced() bool {
+	debit, credit := new(big.Int), new(big.Int)
+	for _, entry := range p.entries {
+		if entry.Direction == LedgerDebit {
+			debit.Add(debit, entry.Amount)
+		} else {
+			credit.Add(credit, entry.Amount)
+		}
+	}
+	return debit.Cmp(credit) == 0
+}
+
+// post 写入分录，并把地址科目的变动应用到 balances 投影
+func (db balanceDB) post(tx *gorm.DB, requestId string, posting *ledgerPosting) error {
+	if len(posting.entries) == 0 {
+		return nil
+	}
+	if !posting.balanced() {
+		return fmt.Errorf("unbalanced ledger posting: source %s %s", posting.source, posting.sourceGuid)
+	}
+	if err := tx.Table(TableLedgerEntriesPrefix+requestId).CreateInBatches(posting.entries, len(posting.entries)).Error; err != nil {
+		return fmt.Errorf("store ledger entries failed: %w", err)
+	}
+
+	for _, entry := range posting.entries {
+		if entry.Account == LedgerAccountExternal {
+			continue
+		}
+		balance, err := db.projection(tx, requestId, entry.Chain, entry.Address, entry.AddressType, entry.TokenAddress)
+		if err != nil {
+			return err
+		}
+		delta := entry.Amount
+		if entry.Direction == LedgerCredit {
+			delta = new(big.Int).Neg(entry.Amount)
+		}
+		if entry.Account == LedgerAccountLocked {
+			balance.LockBalance = new(big.Int).Add(balance.LockBalance, delta)
+		} else {
+			balance.Balance = new(big.Int).Add(balance.Balance, delta)
+		}
+		balance.Timestamp = uint64(time.Now().Unix())
+		if err := tx.Table(TableBalancesPrefix + requestId).Save(balance).Error; err != nil {
+			log.Error("save balance projection failed", "requestId", requestId, "address", entry.Address, "tokenAddress", entry.TokenAddress, "err", err)
+			return fmt.Errorf("save balance failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// projection 查询地址在链上的余额投影，没有时创建
+func (db balanceDB) projection(tx *gorm.DB, requestId string, chain string, address Address, addressType AddressType, tokenAddress common.Address) (*Balances, error) {
+	var balance Balances
+	result := tx.Table(TableBalancesPrefix+requestId).
+		Where("chain = ? and address = ? and token_address = ?", chain, address.String(), tokenAddress.String()).
+		Limit(1).
+		Find(&balance)
+	if result.Error != nil {
+		return nil, fmt.Errorf("query balance failed: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return &balance, nil
+	}
+	created := &Balances{
+		GUID:         uuid.New(),
+		Chain:        chain,
+		Address:      address,
+		TokenAddress: tokenAddress,
+		AddressType:  addressType,
+		Balance:      big.NewInt(0),
+		LockBalance:  big.NewInt(0),
+		Timestamp:    uint64(time.Now().Unix()),
+	}
+	if err := tx.Table(TableBalancesPrefix + requestId).Create(created).Error; err != nil {
+		return nil, fmt.Errorf("create initial balance failed: %w", err)
+	}
+	return created, nil
+}
+
+// RebuildBalances 清空余额投影后按账本分录重新汇总
+func (db balanceDB) RebuildBalances(requestId string) error {
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		type key struct {
+			chain   string
+			address Address
+			token   common.Address
+		}
+		totals := make(map[key]*Balances)
+
+		var batch []*LedgerEntries
+		result := tx.Table(TableLedgerEntriesPrefix+requestId).
+			Where("account <> ?", LedgerAccountExternal).
+			FindInBatches(&batch, 1000, func(_ *gorm.DB, _ int) error {
+				for _, entry := range batch {
+					k := key{chain: entry.Chain, address: entry.Address, token: entry.TokenAddress}
+					total, ok := totals[k]
+					if !ok {
+						total = &Balances{Chain: entry.Chain, Address: entry.Address, TokenAddress: entry.TokenAddress, AddressType: entry.AddressType, Balance: big.NewInt(0), LockBalance: big.NewInt(0)}
+						totals[k] = total
+					}
+					amount := entry.Amount
+					if entry.Direction == LedgerCredit {
+						amount = new(big.Int).Neg(entry.Amount)
+					}
+					if entry.Account == LedgerAccountLocked {
+						total.LockBalance.Add(total.LockBalance, amount)
+					} else {
+						total.Balance.Add(total.Balance, amount)
+					}
+				}
+				return nil
+			})
+		if result.Error != nil {
+			return fmt.Errorf("query ledger entries failed: %w", result.Error)
+		}
+
+		if err := tx.Table(TableBalancesPrefix + requestId).
+			Where("1 = 1").
+			Updates(map[string]interface{}{"balance": 0, "lock_balance": 0}).Error; err != nil {
+			return fmt.Errorf("reset balances failed: %w", err)
+		}
+		for _, total := range totals {
+			balance, err := db.projection(tx, requestId, total.Chain, total.Address, total.AddressType, total.TokenAddress)
+			if err != nil {
+				return err
+			}
+			balance.Balance = total.Balance
+			balance.LockBalance = total.LockBalance
+			balance.Timestamp = uint64(time.Now().Unix())
+			if err := tx.Table(TableBalancesPrefix + requestId).Save(balance).Error; err != nil {
+				return fmt.Errorf("save balance failed: %w", err)
+			}
+		}
+		log.Info("rebuild balances from ledger", "requestId", requestId, "balances", len(totals))
+		return nil
+	})
+}
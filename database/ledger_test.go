@@ -0,0 +1,202 @@
+package database
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testLedgerHot      = AddressFormatEVM.Normalize("0x1000000000000000000000000000000000000001")
+	testLedgerUser     = AddressFormatEVM.Normalize("0x2000000000000000000000000000000000000002")
+	testLedgerExternal = AddressFormatEVM.Normalize("0x4000000000000000000000000000000000000004")
+)
+
+func TestLedgerPostingBalanced(t *testing.T) {
+	posting := newLedgerPosting(LedgerSourceTransfer, uuid.New(), TxTypeDeposit, common.Address{}, "Ethereum", big.NewInt(1))
+	external := ledgerSide{address: testLedgerExternal, addressType: AddressTypeEOA, account: LedgerAccountExternal}
+	available := ledgerSide{address: testLedgerUser, addressType: AddressTypeEOA, account: LedgerAccountAvailable}
+
+	posting.move(external, available, big.NewInt(0))
+	require.Empty(t, posting.entries)
+	require.True(t, posting.balanced())
+
+	posting.move(external, available, big.NewInt(10))
+	require.Len(t, posting.entries, 2)
+	require.True(t, posting.balanced())
+
+	posting.add(available, LedgerDebit, big.NewInt(1))
+	require.False(t, posting.balanced())
+}
+
+// requireLedgerConsistent 每笔记账借贷相等，且按分录重建的余额与当前投影一致
+func requireLedgerConsistent(t *testing.T, db *DB, requestId string) {
+	t.Helper()
+	var entries []*LedgerEntries
+	require.NoError(t, db.gorm.Table(TableLedgerEntriesPrefix+requestId).Find(&entries).Error)
+	require.NotEmpty(t, entries)
+	postings := make(map[uuid.UUID]*big.Int)
+	for _, entry := range entries {
+		total, ok := postings[entry.PostingId]
+		if !ok {
+			total = new(big.Int)
+			postings[entry.PostingId] = total
+		}
+		if entry.Direction == LedgerDebit {
+			total.Add(total, entry.Amount)
+		} else {
+			total.Sub(total, entry.Amount)
+		}
+	}
+	for postingId, total := range postings {
+		require.Zero(t, total.Sign(), "posting %s not balanced", postingId)
+	}
+
+	projection := func() map[string]string {
+		var balances []*Balances
+		require.NoError(t, db.gorm.Table(TableBalancesPrefix+requestId).Find(&balances).Error)
+		result := make(map[string]string, len(balances))
+		for _, balance := range balances {
+			result[balance.Chain+"/"+balance.Address.String()+"/"+balance.TokenAddress.String()] = balance.Balance.String() + "/" + balance.LockBalance.String()
+		}
+		return result
+	}
+	before := projection()
+	require.NoError(t, db.Balances.RebuildBalances(requestId))
+	require.Equal(t, before, projection())
+}
+
+func requireLedgerBalance(t *testing.T, db *DB, requestId string, address Address, balance, lockBalance string) {
+	t.Helper()
+	current, err := db.Balances.QueryBalance(requestId, "Ethereum", address, common.Address{})
+	require.NoError(t, err)
+	require.Equal(t, balance, current.Balance.String())
+	require.Equal(t, lockBalance, current.LockBalance.String())
+}
+
+// storeOpeningBalance 热钱包导出时已有的链上余额
+func storeOpeningBalance(t *testing.T, db *DB, requestId string, amount int64) {
+	t.Helper()
+	require.NoError(t, db.Balances.StoreBalances(requestId, []*Balances{{
+		GUID:         uuid.New(),
+		Chain:        "Ethereum",
+		Address:      testLedgerHot,
+		AddressType:  AddressTypeHot,
+		TokenAddress: common.Address{},
+		Balance:      big.NewInt(amount),
+		LockBalance:  big.NewInt(0),
+		Timestamp:    uint64(time.Now().Unix()),
+	}}))
+}
+
+func testWithdraw(sourceGuid uuid.UUID, amount, fee int64) *TokenBalance {
+	return &TokenBalance{
+		FromAddress: testLedgerHot,
+		ToAddress:   testLedgerExternal,
+		Balance:     big.NewInt(amount),
+		Fee:         big.NewInt(fee),
+		TxType:      TxTypeWithdraw,
+		SourceGuid:  sourceGuid,
+		Chain:       "Ethereum",
+		BlockNumber: big.NewInt(20),
+	}
+}
+
+func TestLedgerDepositPosting(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	require.NoError(t, db.Balances.UpdateOrCreate(requestId, []*TokenBalance{{
+		FromAddress: testLedgerExternal,
+		ToAddress:   testLedgerUser,
+		Balance:     big.NewInt(100),
+		TxType:      TxTypeDeposit,
+		SourceGuid:  uuid.New(),
+		Chain:       "Ethereum",
+		BlockNumber: big.NewInt(10),
+	}}))
+	requireLedgerBalance(t, db, requestId, testLedgerUser, "100", "0")
+	requireLedgerConsistent(t, db, requestId)
+}
+
+func TestLedgerWithdrawLockUnlockAndFee(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	storeOpeningBalance(t, db, requestId, 100)
+
+	failed := uuid.New()
+	lock := func(sourceGuid uuid.UUID, amount int64) *Balances {
+		return &Balances{Chain: "Ethereum", Address: testLedgerHot, LockBalance: big.NewInt(amount), SourceGuid: sourceGuid, BlockNumber: big.NewInt(15)}
+	}
+	require.NoError(t, db.Balances.UpdateBalanceListByTwoAddress(requestId, []*Balances{lock(failed, 30)}))
+	requireLedgerBalance(t, db, requestId, testLedgerHot, "70", "30")
+
+	// 链上失败释放锁定
+	require.NoError(t, db.Balances.ReleaseLockBalance(requestId, []*Balances{lock(failed, 30)}))
+	requireLedgerBalance(t, db, requestId, testLedgerHot, "100", "0")
+
+	// 上链后从锁定余额出账，手续费从可用余额扣除
+	mined := uuid.New()
+	require.NoError(t, db.Balances.UpdateBalanceListByTwoAddress(requestId, []*Balances{lock(mined, 40)}))
+	require.NoError(t, db.Balances.UpdateOrCreate(requestId, []*TokenBalance{testWithdraw(mined, 40, 1)}))
+	requireLedgerBalance(t, db, requestId, testLedgerHot, "59", "0")
+
+	entries, err := db.Ledger.QueryLedgerEntriesBySource(requestId, mined.String())
+	require.NoError(t, err)
+	var fee *LedgerEntries
+	for _, entry := range entries {
+		if entry.SourceType == LedgerSourceFee && entry.Address == LedgerFeeAddress {
+			fee = entry
+		}
+	}
+	require.NotNil(t, fee)
+	require.Equal(t, "1", fee.Amount.String())
+	requireLedgerConsistent(t, db, requestId)
+}
+
+func TestLedgerFundingShortfall(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	// 热钱包被直接充值，同步没有记账，可用余额为 0 时出账
+	sourceGuid := uuid.New()
+	require.NoError(t, db.Balances.UpdateOrCreate(requestId, []*TokenBalance{testWithdraw(sourceGuid, 10, 2)}))
+	requireLedgerBalance(t, db, requestId, testLedgerHot, "0", "0")
+
+	entries, err := db.Ledger.QueryLedgerEntriesBySource(requestId, sourceGuid.String())
+	require.NoError(t, err)
+	funded := new(big.Int)
+	for _, entry := range entries {
+		if entry.SourceType == LedgerSourceFunding && entry.Address == testLedgerHot {
+			funded.Add(funded, entry.Amount)
+		}
+	}
+	require.Equal(t, "12", funded.String())
+	requireLedgerConsistent(t, db, requestId)
+}
+
+func TestLedgerReversal(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	storeOpeningBalance(t, db, requestId, 100)
+	deposit := &TokenBalance{
+		FromAddress: testLedgerExternal,
+		ToAddress:   testLedgerUser,
+		Balance:     big.NewInt(100),
+		TxType:      TxTypeDeposit,
+		SourceGuid:  uuid.New(),
+		Chain:       "Ethereum",
+		BlockNumber: big.NewInt(10),
+	}
+	withdraw := testWithdraw(uuid.New(), 30, 1)
+	require.NoError(t, db.Balances.UpdateOrCreate(requestId, []*TokenBalance{deposit, withdraw}))
+	requireLedgerBalance(t, db, requestId, testLedgerUser, "100", "0")
+	requireLedgerBalance(t, db, requestId, testLedgerHot, "69", "0")
+
+	require.NoError(t, db.Balances.RollbackBalances(requestId, []*TokenBalance{deposit, withdraw}))
+	requireLedgerBalance(t, db, requestId, testLedgerUser, "0", "0")
+	requireLedgerBalance(t, db, requestId, testLedgerHot, "100", "0")
+
+	// 重复回滚不再追加反向分录
+	require.NoError(t, db.Balances.RollbackBalances(requestId, []*TokenBalance{deposit, withdraw}))
+	requireLedgerBalance(t, db, requestId, testLedgerHot, "100", "0")
+	requireLedgerConsistent(t, db, requestId)
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// NftOwnerships 地址在某条链上持有的 NFT，ERC721 的 Amount 固定为 1，ERC1155 为持有数量
+type NftOwnerships struct {
+	GUID         uuid.UUID      `gorm:"primaryKey" json:"guid"`
+	Chain        string         `gorm:"type:varchar;not null" json:"chain"`
+	Address      Address        `gorm:"type:varchar;not null" json:"address"`
+	TokenAddress common.Address `gorm:"type:varchar;not null;serializer:bytes" json:"token_address"`
+	TokenId      string         `gorm:"type:varchar;not null" json:"token_id"`
+	TokenType    TokenType      `gorm:"type:varchar;not null" json:"token_type"`
+	Amount       *big.Int       `gorm:"not null;serializer:u256" json:"amount"`
+	Timestamp    uint64         `gorm:"not null" json:"timestamp"`
+}
+
+// NftTransfer 一笔 NFT 转移，按 TxType 决定增加收款地址还是减少付款地址的持有量
+type NftTransfer struct {
+	Chain        string
+	FromAddress  Address
+	ToAddress    Address
+	TokenAddress common.Address
+	TokenId      string
+	TokenType    TokenType
+	Amount       *big.Int
+	TxType       TransactionType
+}
+
+type NftOwnershipsView interface {
+	QueryNftOwnership(requestId string, chain string, address Address, tokenAddress common.Address, tokenId string) (*NftOwnerships, error)
+	QueryNftsByAddress(requestId string, chain string, address Address) ([]*NftOwnerships, error)
+}
+
+type NftOwnershipsDB interface {
+	NftOwnershipsView
+
+	ApplyTransfers(requestId string, transfers []*NftTransfer) error
+	RollbackTransfers(requestId string, transfers []*NftTransfer) error
+}
+
+type nftOwnershipsDB struct {
+	gorm *gorm.DB
+}
+
+func NewNftOwnershipsDB(db *gorm.DB) NftOwnershipsDB {
+	return &nftOwnershipsDB{gorm: db}
+}
+
+func (db nftOwnershipsDB) QueryNftOwnership(requestId string, chain string, address Address, tokenAddress common.Address, tokenId string) (*NftOwnerships, error) {
+	var ownership NftOwnerships
+	result := db.gorm.Table(TableNftOwnershipsPrefix+requestId).
+		Where("chain = ? and address = ? and token_address = ? and token_id = ?", chain, address.String(), tokenAddress.String(), tokenId).
+		Take(&ownership)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &ownership, nil
+}
+
+func (db nftOwnershipsDB) QueryNftsByAddress(requestId string, chain string, address Address) ([]*NftOwnerships, error) {
+	var ownerships []*NftOwnerships
+	result := db.gorm.Table(TableNftOwnershipsPrefix+requestId).
+		Where("chain = ? and address = ?", chain, address.String()).
+		Find(&ownerships)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ownerships, nil
+}
+
+func (db nftOwnershipsDB) ApplyTransfers(requestId string, transfers []*NftTransfer) error {
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, transfer := range transfers {
+			if err := db.handleTransfer(tx, requestId, transfer, transfer.Amount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RollbackTransfers 链重组时按相反方向撤销 NFT 转移
+func (db nftOwnershipsDB) RollbackTransfers(requestId string, transfers []*NftTransfer) error {
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, transfer := range transfers {
+			if err := db.handleTransfer(tx, requestId, transfer, new(big.Int).Neg(transfer.Amount)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (db nftOwnershipsDB) handleTransfer(tx *gorm.DB, requestId string, transfer *NftTransfer, amount *big.Int) error {
+	switch transfer.TxType {
+	case TxTypeDeposit:
+		return db.adjust(tx, requestId, transfer.ToAddress, transfer, amount)
+	case TxTypeWithdraw:
+		return db.adjust(tx, requestId, transfer.FromAddress, transfer, new(big.Int).Neg(amount))
+	case TxTypeCollection, TxTypeHot2Cold, TxTypeCold2Hot, TxTypeInternalTransfer:
+		if err := db.adjust(tx, requestId, transfer.FromAddress, transfer, new(big.Int).Neg(amount)); err != nil {
+			return err
+		}
+		return db.adjust(tx, requestId, transfer.ToAddress, transfer, amount)
+	default:
+		return nil
+	}
+}
+
+// adjust 调整地址在转移所在链上的持有数量，数量归零时删除持有记录
+func (db nftOwnershipsDB) adjust(tx *gorm.DB, requestId string, address Address, transfer *NftTransfer, delta *big.Int) error {
+	tableName := TableNftOwnershipsPrefix + requestId
+
+	var ownership NftOwnerships
+	result := tx.Table(tableName).
+		Where("chain = ? and address = ? and token_address = ? and token_id = ?", transfer.Chain, address.String(), transfer.TokenAddress.String(), transfer.TokenId).
+		Take(&ownership)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("query nft ownership failed: %w", result.Error)
+	}
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		if delta.Sign() <= 0 {
+			log.Warn("nft ownership not found", "requestId", requestId, "chain", transfer.Chain, "address", address, "tokenAddress", transfer.TokenAddress, "tokenId", transfer.TokenId)
+			return nil
+		}
+		return tx.Table(tableName).Create(&NftOwnerships{
+			GUID:         uuid.New(),
+			Chain:        transfer.Chain,
+			Address:      address,
+			TokenAddress: transfer.TokenAddress,
+			TokenId:      transfer.TokenId,
+			TokenType:    transfer.TokenType,
+			Amount:       delta,
+			Timestamp:    uint64(time.Now().Unix()),
+		}).Error
+	}
+
+	ownership.Amount = new(big.Int).Add(ownership.Amount, delta)
+	if ownership.Amount.Sign() <= 0 {
+		return tx.Table(tableName).Where("guid = ?", ownership.GUID).Delete(&NftOwnerships{}).Error
+	}
+	ownership.Timestamp = uint64(time.Now().Unix())
+	return tx.Table(tableName).Save(&ownership).Error
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNftOwnershipsScopedByChain(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	user := AddressFormatEVM.Normalize("0x2000000000000000000000000000000000000002")
+	external := AddressFormatEVM.Normalize("0x4000000000000000000000000000000000000004")
+	// 同一个合约地址部署在两条链上
+	contract := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	transfer := func(chain string, txType TransactionType, amount int64) *NftTransfer {
+		from, to := external, user
+		if txType == TxTypeWithdraw {
+			from, to = user, external
+		}
+		return &NftTransfer{
+			Chain:        chain,
+			FromAddress:  from,
+			ToAddress:    to,
+			TokenAddress: contract,
+			TokenId:      "1",
+			TokenType:    TokenTypeERC1155,
+			Amount:       big.NewInt(amount),
+			TxType:       txType,
+		}
+	}
+	require.NoError(t, db.Nfts.ApplyTransfers(requestId, []*NftTransfer{
+		transfer("Ethereum", TxTypeDeposit, 5),
+		transfer("Polygon", TxTypeDeposit, 3),
+		transfer("Polygon", TxTypeWithdraw, 1),
+	}))
+
+	ethereum, err := db.Nfts.QueryNftOwnership(requestId, "Ethereum", user, contract, "1")
+	require.NoError(t, err)
+	require.NotNil(t, ethereum)
+	require.Equal(t, "5", ethereum.Amount.String())
+
+	polygon, err := db.Nfts.QueryNftOwnership(requestId, "Polygon", user, contract, "1")
+	require.NoError(t, err)
+	require.NotNil(t, polygon)
+	require.Equal(t, "2", polygon.Amount.String())
+
+	require.NoError(t, db.Nfts.RollbackTransfers(requestId, []*NftTransfer{transfer("Ethereum", TxTypeDeposit, 5)}))
+	ethereum, err = db.Nfts.QueryNftOwnership(requestId, "Ethereum", user, contract, "1")
+	require.NoError(t, err)
+	require.Nil(t, ethereum)
+
+	nfts, err := db.Nfts.QueryNftsByAddress(requestId, "Polygon", user)
+	require.NoError(t, err)
+	require.Len(t, nfts, 1)
+	require.Equal(t, "Polygon", nfts[0].Chain)
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// NonceStatus nonce 预留状态
+type NonceStatus string
+
+const (
+	NonceStatusReserved  NonceStatus = "reserved"  // 已分配给交易，还没有广播
+	NonceStatusBroadcast NonceStatus = "broadcast" // 交易已广播
+	NonceStatusReleased  NonceStatus = "released"  // 交易取消、构建失败或 nonce 已被链上其他交易占用，nonce 可以重新分配
+)
+
+// NonceReservations 按 (chain, address) 分配的 nonce，同一地址未释放的 nonce 不重复
+type NonceReservations struct {
+	GUID          uuid.UUID   `gorm:"primaryKey" json:"guid"`
+	Chain         string      `gorm:"type:varchar;not null" json:"chain"`
+	Address       Address     `gorm:"type:varchar;not null" json:"address"`
+	Nonce         uint64      `gorm:"not null" json:"nonce"`
+	TransactionId string      `gorm:"type:varchar;not null" json:"transaction_id"`
+	Status        NonceStatus `gorm:"type:varchar;not null" json:"status"`
+	Timestamp     uint64      `gorm:"not null" json:"timestamp"`
+}
+
+type NoncesView interface {
+	QueryNonceByTransaction(chain string, transactionId string) (*NonceReservations, error)
+}
+
+type NoncesDB interface {
+	NoncesView
+
+	ReserveNonce(chain string, address Address, transactionId string, chainNonce uint64) (uint64, error)
+	MarkNonceBroadcast(chain string, transactionIds []string) error
+	ReleaseNonce(chain string, transactionId string) error
+}
+
+type noncesDB struct {
+	gorm *gorm.DB
+}
+
+func NewNoncesDB(db *gorm.DB) NoncesDB {
+	return &noncesDB{gorm: db}
+}
+
+// QueryNonceByTransaction 查询交易未释放的 nonce 预留
+func (db noncesDB) QueryNonceByTransaction(chain string, transactionId string) (*NonceReservations, error) {
+	var reservation NonceReservations
+	result := db.gorm.Table("nonce_reservations").
+		Where("chain = ? and transaction_id = ? and status <> ?", chain, transactionId, NonceStatusReleased).
+		Take(&reservation)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &reservation, nil
+}
+
+// ReserveNonce 为交易分配 nonce，同一交易重复调用返回同一个 nonce。
+// chainNonce 为链上地址的 pending nonce，小于它的 nonce 已被链上交易占用；
+// 从 chainNonce 开始取第一个没有被预留或广播占用的 nonce，取消和失败释放的 nonce 留下的空洞优先补上。
+// 预留不会因为时间释放：业务方可能在任意时间后签名广播，只有显式释放或链上 nonce 超过它时才重新分配。
+// 同一地址的分配通过事务级 advisory lock 串行执行
+func (db noncesDB) ReserveNonce(chain string, address Address, transactionId string, chainNonce uint64) (uint64, error) {
+	var nonce uint64
+	err := db.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("select pg_advisory_xact_lock(hashtext(?))", chain+":"+address.String()).Error; err != nil {
+			return fmt.Errorf("lock nonce allocator failed: %w", err)
+		}
+
+		var existing NonceReservations
+		result := tx.Table("nonce_reservations").
+			Where("chain = ? and transaction_id = ? and status <> ?", chain, transactionId, NonceStatusReleased).
+			Limit(1).
+			Find(&existing)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			// 还没广播的交易的 nonce 已被链上其他交易占用时重新分配
+			if existing.Status == NonceStatusBroadcast || existing.Nonce >= chainNonce {
+				nonce = existing.Nonce
+				return nil
+			}
+			log.Warn("reserved nonce used by another transaction, reallocate", "chain", chain, "address", address, "nonce", existing.Nonce, "chainNonce", chainNonce)
+			if err := tx.Table("nonce_reservations").Where("guid = ?", existing.GUID).Update("status", NonceStatusReleased).Error; err != nil {
+				return err
+			}
+		}
+
+		var used []uint64
+		if err := tx.Table("nonce_reservations").
+			Where("chain = ? and address = ? and status <> ? and nonce >= ?", chain, address.String(), NonceStatusReleased, chainNonce).
+			Order("nonce asc").
+			Pluck("nonce", &used).Error; err != nil {
+			return fmt.Errorf("query reserved nonces failed: %w", err)
+		}
+		nonce = nextFreeNonce(chainNonce, used)
+
+		return tx.Table("nonce_reservations").Create(&NonceReservations{
+			GUID:          uuid.New(),
+			Chain:         chain,
+			Address:       address,
+			Nonce:         nonce,
+			TransactionId: transactionId,
+			Status:        NonceStatusReserved,
+			Timestamp:     uint64(time.Now().Unix()),
+		}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return nonce, nil
+}
+
+// nextFreeNonce used 为升序排列且不小于 chainNonce 的已占用 nonce，返回从 chainNonce 开始第一个空闲的 nonce
+func nextFreeNonce(chainNonce uint64, used []uint64) uint64 {
+	nonce := chainNonce
+	for _, reserved := range used {
+		if reserved != nonce {
+			break
+		}
+		nonce++
+	}
+	return nonce
+}
+
+func (db noncesDB) MarkNonceBroadcast(chain string, transactionIds []string) error {
+	if len(transactionIds) == 0 {
+		return nil
+	}
+	return db.gorm.Table("nonce_reservations").
+		Where("chain = ? and transaction_id in ? and status = ?", chain, transactionIds, NonceStatusReserved).
+		Update("status", NonceStatusBroadcast).Error
+}
+
+// ReleaseNonce 交易取消时释放 nonce，下次分配时补上
+func (db noncesDB) ReleaseNonce(chain string, transactionId string) error {
+	return db.gorm.Table("nonce_reservations").
+		Where("chain = ? and transaction_id = ? and status = ?", chain, transactionId, NonceStatusReserved).
+		Update("status", NonceStatusReleased).Error
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestNextFreeNonce(t *testing.T) {
+	tests := []struct {
+		name       string
+		chainNonce uint64
+		used       []uint64
+		want       uint64
+	}{
+		{name: "no reservation", chainNonce: 5, used: nil, want: 5},
+		{name: "contiguous reservations", chainNonce: 5, used: []uint64{5, 6, 7}, want: 8},
+		{name: "gap after chain nonce", chainNonce: 5, used: []uint64{6, 7}, want: 5},
+		{name: "gap in the middle", chainNonce: 5, used: []uint64{5, 6, 8, 9}, want: 7},
+		{name: "released nonce reused", chainNonce: 0, used: []uint64{0, 2}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, nextFreeNonce(tt.chainNonce, tt.used))
+		})
+	}
+}
+
+func TestReserveNonce(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	chain := requestId
+	t.Cleanup(func() {
+		db.gorm.Table("nonce_reservations").Where("chain = ?", chain).Delete(&NonceReservations{})
+	})
+	address := AddressFormatEVM.Normalize("0x1000000000000000000000000000000000000001")
+
+	first, err := db.Nonces.ReserveNonce(chain, address, "tx1", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), first)
+
+	// 同一交易重复分配返回同一个 nonce
+	again, err := db.Nonces.ReserveNonce(chain, address, "tx1", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), again)
+
+	second, err := db.Nonces.ReserveNonce(chain, address, "tx2", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), second)
+
+	// 释放的 nonce 留下的空洞优先补上
+	require.NoError(t, db.Nonces.ReleaseNonce(chain, "tx1"))
+	refilled, err := db.Nonces.ReserveNonce(chain, address, "tx3", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), refilled)
+
+	// 还没广播的预留被链上其他交易占用后重新分配，已广播的保持不变
+	require.NoError(t, db.Nonces.MarkNonceBroadcast(chain, []string{"tx2"}))
+	reallocated, err := db.Nonces.ReserveNonce(chain, address, "tx3", 7)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), reallocated)
+	broadcast, err := db.Nonces.ReserveNonce(chain, address, "tx2", 7)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), broadcast)
+
+	reservation, err := db.Nonces.QueryNonceByTransaction(chain, "tx3")
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), reservation.Nonce)
+}
+
+func TestReserveNonceConcurrent(t *testing.T) {
+	db, requestId := setupTestBusiness(t)
+	chain := requestId
+	t.Cleanup(func() {
+		db.gorm.Table("nonce_reservations").Where("chain = ?", chain).Delete(&NonceReservations{})
+	})
+	address := AddressFormatEVM.Normalize("0x1000000000000000000000000000000000000001")
+
+	// 同一地址并发分配由 advisory lock 串行执行，不会分配到相同的 nonce
+	const count = 10
+	nonces := make([]uint64, count)
+	var group errgroup.Group
+	for i := 0; i < count; i++ {
+		group.Go(func() error {
+			nonce, err := db.Nonces.ReserveNonce(chain, address, fmt.Sprintf("tx%d", i), 0)
+			nonces[i] = nonce
+			return err
+		})
+	}
+	require.NoError(t, group.Wait())
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	for i, nonce := range nonces {
+		require.Equal(t, uint64(i), nonce)
+	}
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Reconciliations 链上余额与 balances 记账余额（可用 + 锁定）不一致的对账记录，
+// Difference 为两者差额的绝对值，超过代币 ReconcileTolerance 时 Exceeded 为 true 并告警
+type Reconciliations struct {
+	GUID          uuid.UUID      `gorm:"primaryKey" json:"guid"`
+	Chain         string         `gorm:"type:varchar;not null" json:"chain"`
+	Address       Address        `gorm:"type:varchar;not null" json:"address"`
+	AddressType   AddressType    `gorm:"type:varchar(10);not null" json:"address_type"`
+	TokenAddress  common.Address `gorm:"serializer:bytes" json:"token_address"`
+	LedgerBalance *big.Int       `gorm:"not null;serializer:u256" json:"ledger_balance"`
+	ChainBalance  *big.Int       `gorm:"not null;serializer:u256" json:"chain_balance"`
+	Difference    *big.Int       `gorm:"not null;serializer:u256" json:"difference"`
+	Exceeded      bool           `gorm:"not null" json:"exceeded"`
+	Alerted       bool           `gorm:"not null" json:"alerted"`
+	Timestamp     uint64         `gorm:"not null" json:"timestamp"`
+}
+
+type ReconciliationsView interface {
+	QueryReconciliations(requestId string, chain string, limit int) ([]*Reconciliations, error)
+	QueryUnalertedReconciliations(requestId string) ([]*Reconciliations, error)
+	QueryLatestReconciliation(requestId string, chain string, address Address, tokenAddress common.Address) (*Reconciliations, error)
+}
+
+type ReconciliationsDB interface {
+	ReconciliationsView
+
+	StoreReconciliations(requestId string, reconciliations []*Reconciliations) error
+	MarkReconciliationsAlerted(requestId string, reconciliations []*Reconciliations) error
+}
+
+type reconciliationsDB struct {
+	gorm *gorm.DB
+}
+
+func NewReconciliationsDB(db *gorm.DB) ReconciliationsDB {
+	return &reconciliationsDB{gorm: db}
+}
+
+// QueryReconciliations 按时间倒序查询链上最近的对账差异
+func (db reconciliationsDB) QueryReconciliations(requestId string, chain string, limit int) ([]*Reconciliations, error) {
+	var reconciliations []*Reconciliations
+	err := db.gorm.Table(TableReconciliationsPrefix+requestId).
+		Where("chain = ?", chain).
+		Order("timestamp desc").
+		Limit(limit).
+		Find(&reconciliations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reconciliations, nil
+}
+
+// QueryUnalertedReconciliations 查询超过容差且还没有告警的差异
+func (db reconciliationsDB) QueryUnalertedReconciliations(requestId string) ([]*Reconciliations, error) {
+	var reconciliations []*Reconciliations
+	err := db.gorm.Table(TableReconciliationsPrefix+requestId).
+		Where("exceeded = ? and alerted = ?", true, false).
+		Order("timestamp asc").
+		Find(&reconciliations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reconciliations, nil
+}
+
+// QueryLatestReconciliation 查询地址某个代币最近一次的对账差异，没有时返回 nil
+func (db reconciliationsDB) QueryLatestReconciliation(requestId string, chain string, address Address, tokenAddress common.Address) (*Reconciliations, error) {
+	var reconciliation Reconciliations
+	err := db.gorm.Table(TableReconciliationsPrefix+requestId).
+		Where("chain = ? and address = ? and token_address = ?", chain, address.String(), tokenAddress.String()).
+		Order("timestamp desc").
+		Take(&reconciliation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &reconciliation, nil
+}
+
+func (db reconciliationsDB) StoreReconciliations(requestId string, reconciliations []*Reconciliations) error {
+	if len(reconciliations) == 0 {
+		return nil
+	}
+	return db.gorm.Table(TableReconciliationsPrefix+requestId).CreateInBatches(reconciliations, len(reconciliations)).Error
+}
+
+func (db reconciliationsDB) MarkReconciliationsAlerted(requestId string, reconciliations []*Reconciliations) error {
+	if len(reconciliations) == 0 {
+		return nil
+	}
+	guids := make([]uuid.UUID, 0, len(reconciliations))
+	for _, reconciliation := range reconciliations {
+		guids = append(guids, reconciliation.GUID)
+	}
+	return db.gorm.Table(TableReconciliationsPrefix+requestId).
+		Where("guid in ?", guids).
+		Update("alerted", true).Error
+}
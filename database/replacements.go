@@ -0,0 +1,203 @@
+package database
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Replacements 卡住交易的替换交易，Nonce 与原交易相同、EIP-1559 手续费更高，TransactionId 为原提现或内部交易的 guid。
+// 状态依次为 create_unsigned（等待业务方重新签名）、signed、boradcasted，
+// 原交易和替换交易中有一笔上链后，上链的替换交易为 success，其余为 failed
+type Replacements struct {
+	GUID                 uuid.UUID       `gorm:"primaryKey" json:"guid"`
+	TransactionId        string          `gorm:"type:varchar;not null" json:"transaction_id"`
+	TxType               TransactionType `gorm:"type:varchar;not null" json:"tx_type"`
+	Chain                string          `gorm:"type:varchar;not null" json:"chain"`
+	ChainId              string          `gorm:"type:varchar;not null" json:"chain_id"`
+	Nonce                uint64          `gorm:"not null" json:"nonce"`
+	FromAddress          Address         `gorm:"type:varchar;not null" json:"from_address"`
+	ToAddress            Address         `gorm:"type:varchar;not null" json:"to_address"`
+	Amount               *big.Int        `gorm:"not null;serializer:u256" json:"amount"`
+	GasLimit             uint64          `gorm:"not null" json:"gas_limit"`
+	MaxFeePerGas         string          `gorm:"not null" json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string          `gorm:"not null" json:"max_priority_fee_per_gas"`
+	TokenType            TokenType       `gorm:"type:varchar;not null" json:"token_type"`
+	TokenAddress         common.Address  `gorm:"serializer:bytes" json:"token_address"`
+	TokenId              string          `json:"token_id"`
+	UnSignTx             string          `json:"un_sign_tx"`
+	TxSignHex            string          `json:"tx_sign_hex"`
+	TxHash               common.Hash     `gorm:"serializer:bytes" json:"tx_hash"`
+	BroadcastBlock       *big.Int        `gorm:"not null;serializer:u256" json:"broadcast_block"`
+	Status               TxStatus        `gorm:"type:varchar;not null" json:"status"`
+	Notified             bool            `gorm:"not null" json:"notified"`
+	Timestamp            uint64          `gorm:"not null" json:"timestamp"`
+}
+
+type ReplacementsView interface {
+	QueryReplacementById(requestId string, guid string) (*Replacements, error)
+	QueryReplacementByTxHash(requestId string, txHash common.Hash) (*Replacements, error)
+	QueryReplacementsByTransaction(requestId string, transactionId string) ([]*Replacements, error)
+	QueryUnnotifiedReplacements(requestId string) ([]*Replacements, error)
+	QuerySignedReplacements(requestId string, chain string) ([]*Replacements, error)
+}
+
+type ReplacementsDB interface {
+	ReplacementsView
+
+	StoreReplacement(requestId string, replacement *Replacements) error
+	UpdateReplacementSigned(requestId string, guid string, signedTx string) error
+	UpdateReplacementsBroadcast(requestId string, replacements []*Replacements) error
+	MarkReplacementsNotified(requestId string, replacements []*Replacements) error
+	SettleReplacements(requestId string, transactionId string, minedHash common.Hash) error
+}
+
+type replacementsDB struct {
+	gorm *gorm.DB
+}
+
+func NewReplacementsDB(db *gorm.DB) ReplacementsDB {
+	return &replacementsDB{gorm: db}
+}
+
+func (db replacementsDB) QueryReplacementById(requestId string, guid string) (*Replacements, error) {
+	var replacement Replacements
+	result := db.gorm.Table(TableReplacementsPrefix+requestId).
+		Where("guid = ?", guid).
+		Take(&replacement)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &replacement, nil
+}
+
+// QueryReplacementByTxHash 查询已广播的替换交易，用于判断上链的交易是否为替换交易
+func (db replacementsDB) QueryReplacementByTxHash(requestId string, txHash common.Hash) (*Replacements, error) {
+	var replacement Replacements
+	result := db.gorm.Table(TableReplacementsPrefix+requestId).
+		Where("tx_hash = ?", txHash.String()).
+		Take(&replacement)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &replacement, nil
+}
+
+// QueryReplacementsByTransaction 按创建顺序查询原交易的全部替换交易
+func (db replacementsDB) QueryReplacementsByTransaction(requestId string, transactionId string) ([]*Replacements, error) {
+	var replacements []*Replacements
+	err := db.gorm.Table(TableReplacementsPrefix+requestId).
+		Where("transaction_id = ?", transactionId).
+		Order("timestamp asc").
+		Find(&replacements).Error
+	if err != nil {
+		return nil, err
+	}
+	return replacements, nil
+}
+
+// QueryUnnotifiedReplacements 查询还没有推送给业务方重新签名的替换交易
+func (db replacementsDB) QueryUnnotifiedReplacements(requestId string) ([]*Replacements, error) {
+	var replacements []*Replacements
+	err := db.gorm.Table(TableReplacementsPrefix+requestId).
+		Where("status = ? and notified = ?", TxStatusCreateUnsigned, false).
+		Order("timestamp asc").
+		Find(&replacements).Error
+	if err != nil {
+		return nil, err
+	}
+	return replacements, nil
+}
+
+// QuerySignedReplacements 查询业务方已签名、等待广播的替换交易
+func (db replacementsDB) QuerySignedReplacements(requestId string, chain string) ([]*Replacements, error) {
+	var replacements []*Replacements
+	err := db.gorm.Table(TableReplacementsPrefix+requestId).
+		Where("chain = ? and status = ?", chain, TxStatusSigned).
+		Order("timestamp asc").
+		Find(&replacements).Error
+	if err != nil {
+		return nil, err
+	}
+	return replacements, nil
+}
+
+func (db replacementsDB) StoreReplacement(requestId string, replacement *Replacements) error {
+	return db.gorm.Table(TableReplacementsPrefix + requestId).Create(replacement).Error
+}
+
+// UpdateReplacementSigned 保存业务方签名后组装的交易，只有等待签名的替换交易可以更新
+func (db replacementsDB) UpdateReplacementSigned(requestId string, guid string, signedTx string) error {
+	result := db.gorm.Table(TableReplacementsPrefix+requestId).
+		Where("guid = ? and status = ?", guid, TxStatusCreateUnsigned).
+		Updates(map[string]interface{}{
+			"tx_sign_hex": signedTx,
+			"status":      TxStatusSigned,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db replacementsDB) UpdateReplacementsBroadcast(requestId string, replacements []*Replacements) error {
+	if len(replacements) == 0 {
+		return nil
+	}
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, replacement := range replacements {
+			err := tx.Table(TableReplacementsPrefix+requestId).
+				Where("guid = ?", replacement.GUID.String()).
+				Updates(map[string]interface{}{
+					"status":          replacement.Status,
+					"tx_hash":         replacement.TxHash.String(),
+					"broadcast_block": replacement.BroadcastBlock,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (db replacementsDB) MarkReplacementsNotified(requestId string, replacements []*Replacements) error {
+	if len(replacements) == 0 {
+		return nil
+	}
+	guids := make([]uuid.UUID, 0, len(replacements))
+	for _, replacement := range replacements {
+		guids = append(guids, replacement.GUID)
+	}
+	return db.gorm.Table(TableReplacementsPrefix+requestId).
+		Where("guid in ?", guids).
+		Update("notified", true).Error
+}
+
+// SettleReplacements 原交易或某一笔替换交易上链后结束替换链：哈希为 minedHash 的替换交易标记为 success，
+// 其余还没有结束的替换交易因 nonce 已被占用标记为 failed；上链的是原交易时全部标记为 failed
+func (db replacementsDB) SettleReplacements(requestId string, transactionId string, minedHash common.Hash) error {
+	tableName := TableReplacementsPrefix + requestId
+	return db.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(tableName).
+			Where("transaction_id = ? and tx_hash = ?", transactionId, minedHash.String()).
+			Update("status", TxStatusSuccess).Error; err != nil {
+			return err
+		}
+		return tx.Table(tableName).
+			Where("transaction_id = ? and tx_hash <> ? and status not in ?", transactionId, minedHash.String(), []TxStatus{TxStatusSuccess, TxStatusFailed}).
+			Update("status", TxStatusFailed).Error
+	})
+}
@@ -3,7 +3,7 @@ package database
 import (
 	"errors"
 	"math/big"
-	
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -14,13 +14,21 @@ type Tokens struct {
 	TokenAddress  common.Address `gorm:"serializer:bytes" json:"token_address"`
 	Decimals      uint8          `json:"decimals"`
 	TokenName     string         `json:"token_name"`
+	TokenType     TokenType      `json:"token_type"`
 	CollectAmount *big.Int       `gorm:"serializer:u256" json:"collect_amount"`
 	ColdAmount    *big.Int       `gorm:"serializer:u256" json:"cold_amount"`
-	TimeStamp     uint64         `json:"time_stamp"`
+	// HotFloorAmount 热钱包余额低于该金额时发起冷转热补充
+	HotFloorAmount *big.Int `gorm:"serializer:u256" json:"hot_floor_amount"`
+	// MinDepositAmount 最小充值金额，低于该金额的充值记为 dust；零地址的记录为原生币的设置
+	MinDepositAmount *big.Int `gorm:"serializer:u256" json:"min_deposit_amount"`
+	// ReconcileTolerance 对账时链上余额与记账余额允许的差额，超过时告警
+	ReconcileTolerance *big.Int `gorm:"serializer:u256" json:"reconcile_tolerance"`
+	TimeStamp          uint64   `json:"time_stamp"`
 }
 
 type TokensView interface {
 	TokensInfoByAddress(string, string) (*Tokens, error)
+	QueryTokenList(requestId string) ([]*Tokens, error)
 }
 
 type TokensDB interface {
@@ -51,6 +59,17 @@ func (t tokensDB) TokensInfoByAddress(requestId string, tokenAddress string) (*T
 	return &tokens, nil
 }
 
+// QueryTokenList 查询业务方白名单中的全部代币
+func (t tokensDB) QueryTokenList(requestId string) ([]*Tokens, error) {
+	var tokens []*Tokens
+	err := t.gorm.Table(TableTokensPrefix + requestId).
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
 func (t tokensDB) StoreTokens(s string, tokensList []Tokens) error {
 	result := t.gorm.Table(TableTokensPrefix+s).CreateInBatches(&tokensList, len(tokensList))
 	return result.Error
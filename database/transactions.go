@@ -10,24 +10,28 @@ import (
 )
 
 type Transactions struct {
-	GUID         uuid.UUID        `gorm:"primaryKey;type:uuid"`
-	BlockHash    common.Hash      `gorm:"serializer:bytes;column:block_hash" json:"block_hash"`
-	BlockNumber  *big.Int         `gorm:"serializer:uint256" json:"block_number"`
-	Hash         common.Hash      `gorm:"serializer:bytes" json:"hash"`
-	FromAddress  common.Address   `gorm:"serializer:bytes" json:"from_address"`
-	ToAddress    common.Address   `gorm:"serializer:bytes" json:"to_address"`
-	TokenAddress common.Address   `gorm:"serializer:bytes" json:"token_address"`
-	TokenId      string           `gorm:"column:token_id" json:"token_id"`
-	TokenMeta    string           `gorm:"column:token_meta" json:"token_meta"`
-	Fee          *big.Int         `gorm:"serializer:uint256" json:"fee"`
-	Amount       *big.Int         `gorm:"serializer:uint256" json:"amount"`
-	Status       account.TxStatus `json:"status"`
-	TxType       TransactionType  `json:"tx_type"`
-	Timestamp    uint64           `json:"timestamp"`
+	GUID        uuid.UUID   `gorm:"primaryKey;type:uuid"`
+	Chain       string      `json:"chain"`
+	BlockHash   common.Hash `gorm:"serializer:bytes;column:block_hash" json:"block_hash"`
+	BlockNumber *big.Int    `gorm:"serializer:uint256" json:"block_number"`
+	Hash        common.Hash `gorm:"serializer:bytes" json:"hash"`
+	// TransferIndex 转账在交易内的序号：账户模型链为 Transfer 日志序号，UTXO 链为输出序号
+	TransferIndex uint32           `json:"transfer_index"`
+	FromAddress   Address          `json:"from_address"`
+	ToAddress     Address          `json:"to_address"`
+	TokenAddress  common.Address   `gorm:"serializer:bytes" json:"token_address"`
+	TokenId       string           `gorm:"column:token_id" json:"token_id"`
+	TokenMeta     string           `gorm:"column:token_meta" json:"token_meta"`
+	Fee           *big.Int         `gorm:"serializer:uint256" json:"fee"`
+	Amount        *big.Int         `gorm:"serializer:uint256" json:"amount"`
+	Status        account.TxStatus `json:"status"`
+	TxType        TransactionType  `json:"tx_type"`
+	Timestamp     uint64           `json:"timestamp"`
 }
 
 type TransactionsView interface {
 	QueryTransactionByHash(requestId string, hash common.Hash) (*Transactions, error)
+	QueryTransactionsAfterBlock(requestId string, chain string, blockNumber *big.Int) ([]*Transactions, error)
 }
 
 type TransactionsDB interface {
@@ -36,6 +40,7 @@ type TransactionsDB interface {
 	StoreTransactions(string, []*Transactions, uint64) error
 	UpdateTransactionsStatus(requestId string, blockNumber *big.Int) error
 	UpdateTransactionStatus(requestId string, txList []*Transactions) error
+	DeleteTransactionsAfterBlock(requestId string, chain string, blockNumber *big.Int) error
 }
 
 type transactionsDB struct {
@@ -60,6 +65,24 @@ func (db transactionsDB) QueryTransactionByHash(requestId string, hash common.Ha
 	return &transactions, nil
 }
 
+func (db transactionsDB) QueryTransactionsAfterBlock(requestId string, chain string, blockNumber *big.Int) ([]*Transactions, error) {
+	var transactions []*Transactions
+	result := db.gorm.Table(TableTransactionsPrefix+requestId).
+		Where("chain = ? and block_number > ?", chain, blockNumber.String()).
+		Find(&transactions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transactions, nil
+}
+
+func (db transactionsDB) DeleteTransactionsAfterBlock(requestId string, chain string, blockNumber *big.Int) error {
+	result := db.gorm.Table(TableTransactionsPrefix+requestId).
+		Where("chain = ? and block_number > ?", chain, blockNumber.String()).
+		Delete(&Transactions{})
+	return result.Error
+}
+
 func (db transactionsDB) StoreTransactions(requestId string, transactions []*Transactions, num uint64) error {
 	result := db.gorm.Table(TableTransactionsPrefix+requestId).
 		CreateInBatches(transactions, len(transactions))
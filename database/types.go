@@ -4,12 +4,19 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/google/uuid"
 )
 
 type TokenBalance struct {
-	FromAddress  common.Address  `json:"from_address"`
-	ToAddress    common.Address  `json:"to_address"`
+	FromAddress  Address         `json:"from_address"`
+	ToAddress    Address         `json:"to_address"`
 	TokenAddress common.Address  `json:"token_address"`
 	Balance      *big.Int        `json:"balance"`
 	TxType       TransactionType `json:"tx_type"`
+
+	// SourceGuid 记入账本的来源交易，Chain、BlockNumber 为交易所在的链和区块，Fee 为钱包发出交易的链上手续费
+	SourceGuid  uuid.UUID `json:"source_guid"`
+	Chain       string    `json:"chain"`
+	BlockNumber *big.Int  `json:"block_number"`
+	Fee         *big.Int  `json:"fee"`
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	"math/big"
+	"time"
 )
 
 type Withdraws struct {
@@ -15,6 +16,7 @@ type Withdraws struct {
 	GUID      uuid.UUID `gorm:"primaryKey;not null" json:"guid"`
 	Timestamp uint64    `gorm:"not null" json:"timestamp"`
 	Status    TxStatus  `gorm:"not null" json:"status"`
+	Chain     string    `gorm:"not null" json:"chain"`
 
 	// 区块信息
 	BlockHash   common.Hash     `gorm:"column:block_hash;serializer:bytes" json:"block_hash"`
@@ -23,9 +25,9 @@ type Withdraws struct {
 	TxType      TransactionType `gorm:"column:tx_type; not null" json:"tx_type"`
 
 	// 交易基础信息
-	FromAddress common.Address `gorm:"column:from_address;serializer:bytes" json:"from_address"`
-	ToAddress   common.Address `gorm:"column:to_address;serializer:bytes" json:"to_address"`
-	Amount      *big.Int       `gorm:"column:amount;serializer:u256" json:"amount"`
+	FromAddress Address  `gorm:"column:from_address" json:"from_address"`
+	ToAddress   Address  `gorm:"column:to_address" json:"to_address"`
+	Amount      *big.Int `gorm:"column:amount;serializer:u256" json:"amount"`
 
 	// Gas 费用
 	GasLimit             uint64 `gorm:"column:gas_limit" json:"gas_limit"`
@@ -38,15 +40,31 @@ type Withdraws struct {
 	TokenId      string         `json:"token_id"`
 	TokenMeta    string         `json:"token_meta"`
 
-	// 交易签名
+	// 业务方订单号，同一业务方内唯一
+	OrderId string `gorm:"column:order_id" json:"order_id"`
+
+	// 待签名交易和交易签名
+	UnSignTx  string `gorm:"column:un_sign_tx" json:"un_sign_tx"`
 	TxSignHex string `gorm:"column:tx_sign_hex" json:"tx_sign_hex"`
+
+	// 广播时已同步的最新区块，用于判断交易是否卡住
+	BroadcastBlock *big.Int `gorm:"column:broadcast_block;serializer:u256" json:"broadcast_block"`
+
+	// 广播重试状态
+	BroadcastAttempts uint64 `gorm:"column:broadcast_attempts" json:"broadcast_attempts"`
+	LastError         string `gorm:"column:last_error" json:"last_error"`
+	NextRetryAt       uint64 `gorm:"column:next_retry_at" json:"next_retry_at"`
 }
 
 type WithdrawView interface {
 	QueryNotifyWithdraws(requestId string) ([]*Withdraws, error)
 	QueryWithdrawsByHash(requestId string, txHash common.Hash) (*Withdraws, error)
 	QueryWithdrawsById(requestId string, guid string) (*Withdraws, error)
-	UnSendWithdrawList(requestId string) ([]*Withdraws, error)
+	QueryWithdrawByOrderId(requestId string, orderId string) (*Withdraws, error)
+	UnSendWithdrawList(requestId string, chain string) ([]*Withdraws, error)
+	QueryFailedWithdraws(requestId string) ([]*Withdraws, error)
+	QueryStuckWithdraws(requestId string, chain string, number *big.Int) ([]*Withdraws, error)
+	QueryBroadcastFailedWithdraws(requestId string) ([]*Withdraws, error)
 }
 
 type WithdrawDB interface {
@@ -59,6 +77,8 @@ type WithdrawDB interface {
 	UpdateWithdrawStatusByTxHash(requestId string, status TxStatus, withdrawList []*Withdraws) error
 	UpdateWithdrawListByTxHash(requestId string, withdrawList []*Withdraws) error
 	UpdateWithdrawListById(requestId string, withdrawList []*Withdraws) error
+	UpdateWithdrawTxHashById(requestId string, guid string, txHash common.Hash) error
+	UpdateWithdrawUnSignTx(requestId string, guid string, unSignTx string) error
 }
 
 type withdrawDB struct {
@@ -88,6 +108,18 @@ func (db withdrawDB) QueryNotifyWithdraws(requestId string) ([]*Withdraws, error
 	return notifyWithdraws, nil
 }
 
+// QueryFailedWithdraws 查询链上执行失败、还没有通知业务方的提现
+func (db withdrawDB) QueryFailedWithdraws(requestId string) ([]*Withdraws, error) {
+	var failedWithdraws []*Withdraws
+	result := db.gorm.Table(TableWithdrawsPrefix+requestId).
+		Where("status = ?", TxStatusFailed).
+		Find(&failedWithdraws)
+	if result.Error != nil {
+		return nil, fmt.Errorf("query failed withdraws failed: %v", result.Error)
+	}
+	return failedWithdraws, nil
+}
+
 func (db withdrawDB) QueryWithdrawsByHash(requestId string, txHash common.Hash) (*Withdraws, error) {
 	var withdraws Withdraws
 	result := db.gorm.Table(TableWithdrawsPrefix+requestId).
@@ -116,10 +148,25 @@ func (db withdrawDB) QueryWithdrawsById(requestId string, guid string) (*Withdra
 	return &withdraws, nil
 }
 
-func (db withdrawDB) UnSendWithdrawList(requestId string) ([]*Withdraws, error) {
+// QueryWithdrawByOrderId 按业务方订单号查询提现，没有时返回 nil
+func (db withdrawDB) QueryWithdrawByOrderId(requestId string, orderId string) (*Withdraws, error) {
+	var withdraws Withdraws
+	result := db.gorm.Table(TableWithdrawsPrefix+requestId).
+		Where("order_id = ?", orderId).
+		Take(&withdraws)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &withdraws, nil
+}
+
+func (db withdrawDB) UnSendWithdrawList(requestId string, chain string) ([]*Withdraws, error) {
 	var withdrawList []*Withdraws
 	result := db.gorm.Table(TableWithdrawsPrefix+requestId).
-		Where("status = ?", TxStatusCreateUnsigned).
+		Where("chain = ? and status = ? and next_retry_at <= ?", chain, TxStatusSigned, time.Now().Unix()).
 		Find(&withdrawList)
 	if result.Error != nil {
 		return nil, fmt.Errorf("query unsign withdraws failed: %v", result.Error)
@@ -127,8 +174,32 @@ func (db withdrawDB) UnSendWithdrawList(requestId string) ([]*Withdraws, error)
 	return withdrawList, nil
 }
 
+// QueryBroadcastFailedWithdraws 查询多次广播失败、还没有通知业务方的提现
+func (db withdrawDB) QueryBroadcastFailedWithdraws(requestId string) ([]*Withdraws, error) {
+	var withdrawList []*Withdraws
+	result := db.gorm.Table(TableWithdrawsPrefix+requestId).
+		Where("status = ?", TxStatusBroadcastFailed).
+		Find(&withdrawList)
+	if result.Error != nil {
+		return nil, fmt.Errorf("query broadcast failed withdraws failed: %v", result.Error)
+	}
+	return withdrawList, nil
+}
+
+// QueryStuckWithdraws 查询已广播但还没有上链、且广播区块不晚于 number 的提现
+func (db withdrawDB) QueryStuckWithdraws(requestId string, chain string, number *big.Int) ([]*Withdraws, error) {
+	var withdrawList []*Withdraws
+	result := db.gorm.Table(TableWithdrawsPrefix+requestId).
+		Where("chain = ? and status = ? and broadcast_block > 0 and broadcast_block <= ?", chain, TxStatusBoradcasted, number.String()).
+		Find(&withdrawList)
+	if result.Error != nil {
+		return nil, fmt.Errorf("query stuck withdraws failed: %v", result.Error)
+	}
+	return withdrawList, nil
+}
+
 func (db withdrawDB) UpdateWithdrawByTxHash(requestId string, txHash common.Hash, signedTx string, status TxStatus) error {
-	tableName := fmt.Sprintf("%s_%s", TableWithdrawsPrefix, requestId)
+	tableName := TableWithdrawsPrefix + requestId
 
 	if err := db.CheckWithdrawExistsByTxHash(tableName, txHash); err != nil {
 		return err
@@ -161,7 +232,7 @@ func (db withdrawDB) UpdateWithdrawByTxHash(requestId string, txHash common.Hash
 }
 
 func (db withdrawDB) UpdateWithdrawById(requestId string, guid string, signedTx string, status TxStatus) error {
-	tableName := fmt.Sprintf("%s_%s", TableWithdrawsPrefix, requestId)
+	tableName := TableWithdrawsPrefix + requestId
 
 	if err := db.CheckWithdrawExistsById(tableName, guid); err != nil {
 		return err
@@ -198,7 +269,7 @@ func (db withdrawDB) UpdateWithdrawStatusById(requestId string, status TxStatus,
 		return nil
 	}
 
-	tableName := fmt.Sprintf("%s_%s", TableWithdrawsPrefix, requestId)
+	tableName := TableWithdrawsPrefix + requestId
 
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		var guids []uuid.UUID
@@ -235,7 +306,7 @@ func (db withdrawDB) UpdateWithdrawStatusByTxHash(requestId string, status TxSta
 		return nil
 	}
 
-	tableName := fmt.Sprintf("%s_%s", TableWithdrawsPrefix, requestId)
+	tableName := TableWithdrawsPrefix + requestId
 
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		var txHashList []common.Hash
@@ -272,7 +343,7 @@ func (db withdrawDB) UpdateWithdrawListByTxHash(requestId string, withdrawList [
 		return nil
 	}
 
-	tableName := fmt.Sprintf("%s_%s", TableWithdrawsPrefix, requestId)
+	tableName := TableWithdrawsPrefix + requestId
 
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		for _, withdraw := range withdrawList {
@@ -307,17 +378,24 @@ func (db withdrawDB) UpdateWithdrawListById(requestId string, withdrawList []*Wi
 		return nil
 	}
 
-	tableName := fmt.Sprintf("%s_%s", TableWithdrawsPrefix, requestId)
+	tableName := TableWithdrawsPrefix + requestId
 
 	return db.gorm.Transaction(func(tx *gorm.DB) error {
 		for _, withdraw := range withdrawList {
+			updates := map[string]interface{}{
+				"status":             withdraw.Status,
+				"amount":             withdraw.Amount,
+				"tx_hash":            withdraw.TxHash.String(),
+				"broadcast_attempts": withdraw.BroadcastAttempts,
+				"last_error":         withdraw.LastError,
+				"next_retry_at":      withdraw.NextRetryAt,
+			}
+			if withdraw.BroadcastBlock != nil {
+				updates["broadcast_block"] = withdraw.BroadcastBlock
+			}
 			result := db.gorm.Table(tableName).
 				Where("guid = ?", withdraw.GUID.String()).
-				Updates(map[string]interface{}{
-					"status":  withdraw.Status,
-					"amount":  withdraw.Amount,
-					"tx_hash": withdraw.TxHash.String(),
-				})
+				Updates(updates)
 			// Check for errors in the update operation
 			if result.Error != nil {
 				return fmt.Errorf("update failed for TxHash %s: %w", withdraw.TxHash.Hex(), result.Error)
@@ -335,6 +413,20 @@ func (db withdrawDB) UpdateWithdrawListById(requestId string, withdrawList []*Wi
 	})
 }
 
+// UpdateWithdrawTxHashById 签名后保存本地计算的交易哈希；替换交易上链后改为上链的哈希，后续按哈希更新状态
+func (db withdrawDB) UpdateWithdrawTxHashById(requestId string, guid string, txHash common.Hash) error {
+	return db.gorm.Table(TableWithdrawsPrefix+requestId).
+		Where("guid = ?", guid).
+		Update("tx_hash", txHash.String()).Error
+}
+
+// UpdateWithdrawUnSignTx 保存构建的待签名交易，相同订单号的重复请求原样返回
+func (db withdrawDB) UpdateWithdrawUnSignTx(requestId string, guid string, unSignTx string) error {
+	return db.gorm.Table(TableWithdrawsPrefix+requestId).
+		Where("guid = ?", guid).
+		Update("un_sign_tx", unSignTx).Error
+}
+
 func (db withdrawDB) CheckWithdrawExistsByTxHash(tableName string, hash common.Hash) error {
 	var exist bool
 	err := db.gorm.Table(tableName).
@@ -34,6 +34,19 @@ var (
 		Required: true,
 	}
 
+	NetworkFlag = &cli.StringFlag{
+		Name:    "network",
+		Usage:   "chain network",
+		EnvVars: prefixEnvVars("NETWORK"),
+		Value:   "mainnet",
+	}
+
+	ChainsConfigFlag = &cli.StringFlag{
+		Name:    "chains-config",
+		Usage:   "path of the yaml file with one section per chain to sync",
+		EnvVars: prefixEnvVars("CHAINS_CONFIG"),
+	}
+
 	RpcUrlFlag = &cli.StringFlag{
 		Name:     "rpc-url",
 		Usage:    "HTTP provider URL for chain",
@@ -71,6 +84,95 @@ var (
 		EnvVars: prefixEnvVars("BLOCKS_STEP"),
 		Value:   500,
 	}
+	BlockFetchWorkersFlag = &cli.IntFlag{
+		Name:    "block-fetch-workers",
+		Usage:   "The max number of blocks fetched concurrently while scanning",
+		EnvVars: prefixEnvVars("BLOCK_FETCH_WORKERS"),
+		Value:   8,
+	}
+
+	UtxoFlag = &cli.BoolFlag{
+		Name:    "utxo",
+		Usage:   "whether the chain uses the UTXO model, like Bitcoin",
+		EnvVars: prefixEnvVars("UTXO"),
+	}
+	AddressFormatFlag = &cli.StringFlag{
+		Name:    "address-format",
+		Usage:   "The address format of the chain: evm, bech32 or native, defaults to bech32 for utxo chains",
+		EnvVars: prefixEnvVars("ADDRESS_FORMAT"),
+	}
+
+	CollectIntervalFlag = &cli.DurationFlag{
+		Name:    "collect-interval",
+		Usage:   "The interval of scanning user balances to create collection transactions",
+		EnvVars: prefixEnvVars("COLLECT_INTERVAL"),
+		Value:   time.Minute,
+	}
+
+	RebalanceIntervalFlag = &cli.DurationFlag{
+		Name:    "rebalance-interval",
+		Usage:   "The interval of checking hot wallet balances against cold amount and hot floor",
+		EnvVars: prefixEnvVars("REBALANCE_INTERVAL"),
+		Value:   time.Minute,
+	}
+
+	ReconcileIntervalFlag = &cli.DurationFlag{
+		Name:    "reconcile-interval",
+		Usage:   "The interval of reconciling on-chain balances against recorded balances",
+		EnvVars: prefixEnvVars("RECONCILE_INTERVAL"),
+		Value:   10 * time.Minute,
+	}
+
+	SnapshotIntervalFlag = &cli.DurationFlag{
+		Name:    "snapshot-interval",
+		Usage:   "The interval of balance snapshots, snapshots are aligned to interval boundaries in UTC",
+		EnvVars: prefixEnvVars("SNAPSHOT_INTERVAL"),
+		Value:   24 * time.Hour,
+	}
+
+	StuckBlocksFlag = &cli.Uint64Flag{
+		Name:    "stuck-blocks",
+		Usage:   "The number of blocks a broadcast transaction can stay unmined before it is replaced with bumped fees",
+		EnvVars: prefixEnvVars("STUCK_BLOCKS"),
+		Value:   50,
+	}
+
+	FeeBumpPercentFlag = &cli.Uint64Flag{
+		Name:    "fee-bump-percent",
+		Usage:   "The percentage of the previous fees used by replacement transactions, must be at least 110",
+		EnvVars: prefixEnvVars("FEE_BUMP_PERCENT"),
+		Value:   125,
+	}
+
+	MaxBroadcastAttemptsFlag = &cli.Uint64Flag{
+		Name:    "max-broadcast-attempts",
+		Usage:   "The number of failed broadcasts after which a withdraw or internal transaction is marked broadcast_failed",
+		EnvVars: prefixEnvVars("MAX_BROADCAST_ATTEMPTS"),
+		Value:   5,
+	}
+
+	// export-snapshot command flags
+	SnapshotBusinessFlag = &cli.StringFlag{
+		Name:     "business",
+		Usage:    "The business id whose balance snapshot is exported",
+		EnvVars:  prefixEnvVars("SNAPSHOT_BUSINESS"),
+		Required: true,
+	}
+	SnapshotBlockFlag = &cli.StringFlag{
+		Name:    "block",
+		Usage:   "Export the balances at this block number",
+		EnvVars: prefixEnvVars("SNAPSHOT_BLOCK"),
+	}
+	SnapshotDateFlag = &cli.StringFlag{
+		Name:    "date",
+		Usage:   "Export the end-of-day balances of this UTC date (YYYY-MM-DD), defaults to the latest synced block",
+		EnvVars: prefixEnvVars("SNAPSHOT_DATE"),
+	}
+	SnapshotOutputFlag = &cli.StringFlag{
+		Name:    "output",
+		Usage:   "The csv file to write the snapshot to, defaults to stdout",
+		EnvVars: prefixEnvVars("SNAPSHOT_OUTPUT"),
+	}
 
 	// RpcHostFlag rpc api flags
 	RpcHostFlag = &cli.StringFlag{
@@ -227,6 +329,18 @@ var requireFlags = []cli.Flag{
 }
 
 var optionalFlags = []cli.Flag{
+	NetworkFlag,
+	ChainsConfigFlag,
+	BlockFetchWorkersFlag,
+	UtxoFlag,
+	AddressFormatFlag,
+	CollectIntervalFlag,
+	RebalanceIntervalFlag,
+	ReconcileIntervalFlag,
+	SnapshotIntervalFlag,
+	StuckBlocksFlag,
+	FeeBumpPercentFlag,
+	MaxBroadcastAttemptsFlag,
 	SlaveDbHostFlag,
 	SlaveDbPortFlag,
 	SlaveDbUserFlag,
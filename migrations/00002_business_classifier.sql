@@ -0,0 +1 @@
+alter table business add column if not exists classifier varchar not null default 'default';
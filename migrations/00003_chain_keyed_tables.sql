@@ -0,0 +1,36 @@
+-- 多链同步：区块和交易记录按链区分，存量数据属于之前写死的 Ethereum
+alter table blocks add column if not exists chain varchar not null default 'Ethereum';
+alter table blocks alter column chain drop default;
+alter table blocks drop constraint if exists blocks_parent_hash_key;
+alter table blocks drop constraint if exists blocks_number_key;
+
+DO
+$$
+BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'blocks_chain_pkey') THEN
+ALTER TABLE blocks DROP CONSTRAINT IF EXISTS blocks_pkey;
+ALTER TABLE blocks ADD CONSTRAINT blocks_chain_pkey PRIMARY KEY (chain, hash);
+END IF;
+END
+$$;
+create unique index if not exists blocks_chain_number on blocks (chain, number);
+
+-- 模板表和已经注册的业务方表都需要加上 chain 字段
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name IN ('deposits', 'withdraws', 'internals', 'transactions')
+                   OR table_name LIKE 'deposits\_%'
+                   OR table_name LIKE 'withdraws\_%'
+                   OR table_name LIKE 'internals\_%'
+                   OR table_name LIKE 'transactions\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists chain varchar not null default %L', t.table_name, 'Ethereum');
+        EXECUTE format('alter table %I alter column chain drop default', t.table_name);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,34 @@
+-- 共享地址的链（XRP、Stellar、TON、Cosmos）通过 memo 区分用户，同一个地址可以对应多个 memo
+DO
+$$
+DECLARE
+    c record;
+    t record;
+BEGIN
+    FOR c IN SELECT con.conname, cls.relname FROM pg_constraint con
+             JOIN pg_class cls ON cls.oid = con.conrelid
+             JOIN pg_attribute att ON att.attrelid = cls.oid AND att.attnum = con.conkey[1]
+             WHERE con.contype = 'u'
+               AND array_length(con.conkey, 1) = 1
+               AND att.attname = 'address'
+               AND (cls.relname = 'addresses' OR cls.relname LIKE 'addresses\_%')
+    LOOP
+        EXECUTE format('alter table %I drop constraint %I', c.relname, c.conname);
+    END LOOP;
+
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'addresses' OR table_name LIKE 'addresses\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists memo varchar not null default %L', t.table_name, '');
+        EXECUTE format('create unique index if not exists %I on %I (address, memo)', t.table_name || '_address_memo', t.table_name);
+    END LOOP;
+
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'deposits' OR table_name LIKE 'deposits\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists memo varchar not null default %L', t.table_name, '');
+    END LOOP;
+END
+$$;
@@ -0,0 +1,14 @@
+-- notified_confirms 记录最近一次通知业务方时的确认数，确认数变化后由通知服务推送进度
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'deposits' OR table_name LIKE 'deposits\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists notified_confirms smallint not null default 0', t.table_name);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,25 @@
+-- 一笔交易可以包含多笔转账（批量转账、多个 ERC-20 Transfer 日志、UTXO 多输出），链上同步的记录按 (hash, transfer_index) 区分
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'transactions' OR table_name LIKE 'transactions\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists transfer_index integer not null default 0', t.table_name);
+        EXECUTE format('create unique index if not exists %I on %I (chain, hash, transfer_index)', t.table_name || '_chain_hash_transfer', t.table_name);
+    END LOOP;
+
+    -- 钱包自己创建的待签名充值还没有交易哈希，只约束链上同步到的记录
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'deposits' OR table_name LIKE 'deposits\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists transfer_index integer not null default 0', t.table_name);
+        EXECUTE format('create unique index if not exists %I on %I (chain, hash, transfer_index) where status not in (%L, %L)',
+                       t.table_name || '_chain_hash_transfer', t.table_name, 'create_unsigned', 'signed');
+    END LOOP;
+END
+$$;
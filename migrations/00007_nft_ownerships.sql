@@ -0,0 +1,41 @@
+-- 代币注册时区分 ERC20 / ERC721 / ERC1155，同步时按代币类型解析转账
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'tokens' OR table_name LIKE 'tokens\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists token_type varchar not null default %L', t.table_name, 'ERC20');
+    END LOOP;
+END
+$$;
+
+-- NFT 持有记录，ERC721 的 amount 固定为 1，ERC1155 为持有数量；NFT 不计入 balances
+create table if not exists nft_ownerships
+(
+    guid varchar primary key,
+    address varchar not null,
+    token_address varchar not null,
+    token_id varchar not null,
+    token_type varchar not null,
+    amount uint256 not null check ( amount > 0 ),
+    timestamp bigint not null check ( timestamp > 0 )
+);
+create unique index if not exists nft_ownerships_token on nft_ownerships (address, token_address, token_id);
+create index if not exists nft_ownerships_address on nft_ownerships (address);
+
+-- 已注册的业务方补建 NFT 持有表
+DO
+$$
+DECLARE
+    b record;
+BEGIN
+    FOR b IN SELECT business_uid FROM business
+    LOOP
+        EXECUTE format('create table if not exists %I (like nft_ownerships including all)', 'nft_ownerships_' || b.business_uid);
+    END LOOP;
+END
+$$;
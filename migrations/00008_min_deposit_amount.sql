@@ -0,0 +1,14 @@
+-- 代币的最小充值金额，低于该金额的充值记为 dust；token_address 为零地址的记录是原生币的设置
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'tokens' OR table_name LIKE 'tokens\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists min_deposit_amount uint256 not null default 0', t.table_name);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,15 @@
+-- 热钱包余额超过 cold_amount 时把超出部分转入冷钱包，低于 hot_floor_amount 时发起冷转热补充
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'tokens' OR table_name LIKE 'tokens\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists cold_amount uint256 not null default 0', t.table_name);
+        EXECUTE format('alter table %I add column if not exists hot_floor_amount uint256 not null default 0', t.table_name);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,61 @@
+-- 复式记账分录，只追加不修改；balances 为按地址和代币汇总分录得到的投影，可由分录重建
+-- account: available 可用余额 / locked 锁定余额 / external 外部对手方；direction: debit 增加 / credit 减少
+create table if not exists ledger_entries
+(
+    guid varchar primary key,
+    posting_id varchar not null,
+    source_guid varchar not null,
+    source_type varchar not null,
+    tx_type varchar not null,
+    address varchar not null,
+    address_type varchar(10) not null,
+    token_address varchar not null,
+    account varchar not null,
+    direction varchar not null,
+    amount uint256 not null check ( amount > 0 ),
+    timestamp bigint not null check ( timestamp > 0 )
+);
+create index if not exists ledger_entries_address on ledger_entries (address, token_address);
+create index if not exists ledger_entries_source_guid on ledger_entries (source_guid);
+create index if not exists ledger_entries_posting_id on ledger_entries (posting_id);
+
+-- 已注册的业务方补建分录表，账本为空时按现有余额记期初分录
+DO
+$$
+DECLARE
+    b record;
+    entries_table text;
+    balances_table text;
+    empty boolean;
+BEGIN
+    FOR b IN SELECT business_uid FROM business
+    LOOP
+        entries_table := 'ledger_entries_' || b.business_uid;
+        balances_table := 'balances_' || b.business_uid;
+        EXECUTE format('create table if not exists %I (like ledger_entries including all)', entries_table);
+
+        IF to_regclass(quote_ident(balances_table)) IS NULL THEN
+            CONTINUE;
+        END IF;
+        EXECUTE format('select not exists (select 1 from %I)', entries_table) INTO empty;
+        IF NOT empty THEN
+            CONTINUE;
+        END IF;
+
+        EXECUTE format(
+            'insert into %I (guid, posting_id, source_guid, source_type, tx_type, address, address_type, token_address, account, direction, amount, timestamp)
+             select gen_random_uuid(), p.posting_id, p.guid, %L, %L, p.address, p.address_type, p.token_address, s.account, s.direction, s.amount, extract(epoch from now())::bigint
+             from (select gen_random_uuid() as posting_id, * from %I) p
+             cross join lateral (values
+                 (%L, %L, p.balance),
+                 (%L, %L, p.lock_balance),
+                 (%L, %L, p.balance + p.lock_balance)
+             ) as s(account, direction, amount)
+             where s.amount > 0',
+            entries_table, 'opening', 'unknown', balances_table,
+            'available', 'debit',
+            'locked', 'debit',
+            'external', 'credit');
+    END LOOP;
+END
+$$;
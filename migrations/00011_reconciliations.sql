@@ -0,0 +1,45 @@
+-- 对账时链上余额与记账余额允许的差额，超过时告警
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'tokens' OR table_name LIKE 'tokens\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists reconcile_tolerance uint256 not null default 0', t.table_name);
+    END LOOP;
+END
+$$;
+
+-- 链上余额与 balances 记账余额（可用 + 锁定）不一致的对账记录，difference 为差额的绝对值
+create table if not exists reconciliations
+(
+    guid varchar primary key,
+    chain varchar not null,
+    address varchar not null,
+    address_type varchar(10) not null,
+    token_address varchar not null,
+    ledger_balance uint256 not null,
+    chain_balance uint256 not null,
+    difference uint256 not null check ( difference > 0 ),
+    exceeded boolean not null default false,
+    alerted boolean not null default false,
+    timestamp bigint not null check ( timestamp > 0 )
+);
+create index if not exists reconciliations_chain on reconciliations (chain, timestamp);
+create index if not exists reconciliations_alert on reconciliations (exceeded, alerted);
+
+-- 已注册的业务方补建对账表
+DO
+$$
+DECLARE
+    b record;
+BEGIN
+    FOR b IN SELECT business_uid FROM business
+    LOOP
+        EXECUTE format('create table if not exists %I (like reconciliations including all)', 'reconciliations_' || b.business_uid);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,30 @@
+-- 定期的余额快照，同一次快照的记录 snapshot_id 相同，block_number 为快照时已同步的最新区块
+create table if not exists balance_snapshots
+(
+    guid varchar primary key,
+    snapshot_id varchar not null,
+    chain varchar not null,
+    block_number uint256 not null,
+    address varchar not null,
+    address_type varchar(10) not null,
+    token_address varchar not null,
+    balance uint256 not null default 0,
+    lock_balance uint256 not null default 0,
+    timestamp bigint not null check ( timestamp > 0 )
+);
+create index if not exists balance_snapshots_chain_timestamp on balance_snapshots (chain, timestamp);
+create index if not exists balance_snapshots_chain_block on balance_snapshots (chain, block_number);
+create index if not exists balance_snapshots_address on balance_snapshots (snapshot_id, address, token_address);
+
+-- 已注册的业务方补建快照表
+DO
+$$
+DECLARE
+    b record;
+BEGIN
+    FOR b IN SELECT business_uid FROM business
+    LOOP
+        EXECUTE format('create table if not exists %I (like balance_snapshots including all)', 'balance_snapshots_' || b.business_uid);
+    END LOOP;
+END
+$$;
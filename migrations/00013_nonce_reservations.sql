@@ -0,0 +1,14 @@
+-- 钱包发出交易的 nonce 按 (chain, address) 分配，未释放的 nonce 不重复
+create table if not exists nonce_reservations
+(
+    guid varchar primary key,
+    chain varchar not null,
+    address varchar not null,
+    nonce bigint not null check ( nonce >= 0 ),
+    transaction_id varchar not null,
+    status varchar not null,
+    timestamp bigint not null check ( timestamp > 0 ),
+    CONSTRAINT check_nonce_status CHECK (status IN ('reserved', 'broadcast', 'released'))
+);
+create unique index if not exists nonce_reservations_active on nonce_reservations (chain, address, nonce) where status <> 'released';
+create unique index if not exists nonce_reservations_transaction on nonce_reservations (chain, transaction_id) where status <> 'released';
@@ -0,0 +1,58 @@
+-- broadcast_block 记录交易广播时已同步的最新区块，超过 stuck_blocks 仍未上链的交易由 watchdog 提高手续费替换
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name IN ('withdraws', 'internals') OR table_name LIKE 'withdraws\_%' OR table_name LIKE 'internals\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists broadcast_block uint256 not null default 0', t.table_name);
+    END LOOP;
+END
+$$;
+
+-- 卡住交易的替换交易，与原交易 nonce 相同、手续费更高；transaction_id 为原交易的 guid，
+-- 原交易和所有替换交易中哪一笔上链就以哪一笔为准，其余标记为 failed
+create table if not exists replacements
+(
+    guid varchar primary key,
+    transaction_id varchar not null,
+    tx_type varchar not null,
+    chain varchar not null,
+    chain_id varchar not null,
+    nonce bigint not null check ( nonce >= 0 ),
+    from_address varchar not null,
+    to_address varchar not null,
+    amount uint256 not null,
+    gas_limit bigint not null,
+    max_fee_per_gas varchar not null,
+    max_priority_fee_per_gas varchar not null,
+    token_type varchar not null,
+    token_address varchar not null,
+    token_id varchar not null default '',
+    un_sign_tx varchar not null default '',
+    tx_sign_hex varchar not null default '',
+    tx_hash varchar not null default '',
+    broadcast_block uint256 not null default 0,
+    status varchar not null,
+    notified boolean not null default false,
+    timestamp bigint not null check ( timestamp > 0 )
+);
+create index if not exists replacements_transaction on replacements (transaction_id);
+create index if not exists replacements_tx_hash on replacements (tx_hash);
+create index if not exists replacements_chain_status on replacements (chain, status);
+
+-- 已注册的业务方补建替换交易表
+DO
+$$
+DECLARE
+    b record;
+BEGIN
+    FOR b IN SELECT business_uid FROM business
+    LOOP
+        EXECUTE format('create table if not exists %I (like replacements including all)', 'replacements_' || b.business_uid);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,17 @@
+-- 广播失败的重试状态：broadcast_attempts 为已尝试广播的次数，last_error 为最近一次失败原因，
+-- next_retry_at 之前不再重试；达到最大次数后状态改为 broadcast_failed 并通知业务方
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name IN ('withdraws', 'internals') OR table_name LIKE 'withdraws\_%' OR table_name LIKE 'internals\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists broadcast_attempts integer not null default 0', t.table_name);
+        EXECUTE format('alter table %I add column if not exists last_error varchar not null default %L', t.table_name, '');
+        EXECUTE format('alter table %I add column if not exists next_retry_at bigint not null default 0', t.table_name);
+    END LOOP;
+END
+$$;
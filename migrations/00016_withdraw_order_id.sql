@@ -0,0 +1,17 @@
+-- order_id 为业务方提交提现时的订单号，同一业务方内唯一，重复提交相同订单号时返回原提现；
+-- un_sign_tx 保存构建的待签名交易，重复提交时原样返回
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'withdraws' OR table_name LIKE 'withdraws\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists order_id varchar not null default %L', t.table_name, '');
+        EXECUTE format('alter table %I add column if not exists un_sign_tx varchar not null default %L', t.table_name, '');
+        EXECUTE format('create unique index if not exists %I on %I (order_id) where order_id <> %L', t.table_name || '_order_id', t.table_name, '');
+    END LOOP;
+END
+$$;
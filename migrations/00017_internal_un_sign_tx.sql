@@ -0,0 +1,14 @@
+-- un_sign_tx 保存内部交易构建的待签名交易，业务方每次查询原样返回，nonce 被重新分配时才重新构建
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'internals' OR table_name LIKE 'internals\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists un_sign_tx varchar not null default %L', t.table_name, '');
+    END LOOP;
+END
+$$;
@@ -0,0 +1,15 @@
+-- seq 为分录的写入顺序，同一秒内写入的分录按 timestamp 无法区分先后，按地址查询分录时按 seq 排序
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'ledger_entries' OR table_name LIKE 'ledger\_entries\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists seq bigserial', t.table_name);
+        EXECUTE format('create index if not exists %I on %I (address, token_address, seq)', t.table_name || '_seq', t.table_name);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,53 @@
+-- chain、block_number 为分录生效的链和区块，历史余额按区块汇总分录得到；
+-- 存量分录按来源交易补齐，锁定记在广播时的区块，释放锁定记在交易失败的区块，找不到来源的期初分录由 00020 归到 Ethereum
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'ledger_entries' OR table_name LIKE 'ledger\_entries\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists chain varchar not null default %L', t.table_name, '');
+        EXECUTE format('alter table %I add column if not exists block_number uint256 not null default 0', t.table_name);
+        EXECUTE format('create index if not exists %I on %I (chain, address, token_address, block_number)', t.table_name || '_chain_block', t.table_name);
+    END LOOP;
+END
+$$;
+
+DO
+$$
+DECLARE
+    b record;
+    entries_table text;
+    source_table text;
+BEGIN
+    FOR b IN SELECT business_uid FROM business
+    LOOP
+        entries_table := 'ledger_entries_' || b.business_uid;
+        IF to_regclass(quote_ident(entries_table)) IS NULL THEN
+            CONTINUE;
+        END IF;
+
+        source_table := 'transactions_' || b.business_uid;
+        IF to_regclass(quote_ident(source_table)) IS NOT NULL THEN
+            EXECUTE format(
+                'update %I e set chain = s.chain, block_number = s.block_number from %I s
+                 where e.chain = %L and e.source_guid = s.guid',
+                entries_table, source_table, '');
+        END IF;
+
+        FOREACH source_table IN ARRAY ARRAY['withdraws_' || b.business_uid, 'internals_' || b.business_uid]
+        LOOP
+            IF to_regclass(quote_ident(source_table)) IS NULL THEN
+                CONTINUE;
+            END IF;
+            EXECUTE format(
+                'update %I e set chain = s.chain, block_number = case when e.source_type = %L then s.broadcast_block else s.block_number end from %I s
+                 where e.chain = %L and e.source_type in (%L, %L) and e.source_guid = s.guid',
+                entries_table, 'lock', source_table, '', 'lock', 'unlock');
+        END LOOP;
+    END LOOP;
+END
+$$;
@@ -0,0 +1,42 @@
+-- 余额和地址按链区分：不同的 EVM 链上同一个地址、同一个代币合约地址（原生币都是 0x00）是不同的资产，
+-- 存量数据属于之前写死的 Ethereum。业务方的表按模板 including all 建表，复制的唯一索引名字不固定，按索引定义删除
+DO
+$$
+DECLARE
+    t record;
+    i record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'balances' OR table_name LIKE 'balances\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists chain varchar not null default %L', t.table_name, 'Ethereum');
+        EXECUTE format('alter table %I alter column chain drop default', t.table_name);
+        EXECUTE format('create unique index if not exists %I on %I (chain, address, token_address)', t.table_name || '_chain_token', t.table_name);
+    END LOOP;
+
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'addresses' OR table_name LIKE 'addresses\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists chain varchar not null default %L', t.table_name, 'Ethereum');
+        EXECUTE format('alter table %I alter column chain drop default', t.table_name);
+        FOR i IN SELECT indexname FROM pg_indexes
+                 WHERE schemaname = current_schema()
+                   AND tablename = t.table_name
+                   AND indexdef LIKE 'CREATE UNIQUE INDEX % (address, memo)'
+        LOOP
+            EXECUTE format('drop index %I', i.indexname);
+        END LOOP;
+        EXECUTE format('create unique index if not exists %I on %I (chain, address, memo)', t.table_name || '_chain_address_memo', t.table_name);
+    END LOOP;
+
+    -- 启用账本前的期初分录没有来源交易，同样属于 Ethereum
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'ledger_entries' OR table_name LIKE 'ledger\_entries\_%')
+    LOOP
+        EXECUTE format('update %I set chain = %L where chain = %L', t.table_name, 'Ethereum', '');
+    END LOOP;
+END
+$$;
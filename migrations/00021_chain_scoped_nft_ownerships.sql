@@ -0,0 +1,25 @@
+-- NFT 持有按链区分：同一个合约（CREATE2 部署）在不同的 EVM 链上地址相同，持有记录不能合并；
+-- 存量数据属于之前写死的 Ethereum。业务方的表按模板 including all 建表，复制的唯一索引名字不固定，按索引定义删除
+DO
+$$
+DECLARE
+    t record;
+    i record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'nft_ownerships' OR table_name LIKE 'nft\_ownerships\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists chain varchar not null default %L', t.table_name, 'Ethereum');
+        EXECUTE format('alter table %I alter column chain drop default', t.table_name);
+        FOR i IN SELECT indexname FROM pg_indexes
+                 WHERE schemaname = current_schema()
+                   AND tablename = t.table_name
+                   AND indexdef LIKE 'CREATE UNIQUE INDEX % (address, token_address, token_id)'
+        LOOP
+            EXECUTE format('drop index %I', i.indexname);
+        END LOOP;
+        EXECUTE format('create unique index if not exists %I on %I (chain, address, token_address, token_id)', t.table_name || '_chain_token', t.table_name);
+    END LOOP;
+END
+$$;
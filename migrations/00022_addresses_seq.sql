@@ -0,0 +1,15 @@
+-- seq 为地址的写入顺序，内存地址索引按 seq 增量加载新地址；timestamp 由写入方填写，晚提交的地址 timestamp 可能早于已加载的地址
+DO
+$$
+DECLARE
+    t record;
+BEGIN
+    FOR t IN SELECT table_name FROM information_schema.tables
+             WHERE table_schema = current_schema()
+               AND (table_name = 'addresses' OR table_name LIKE 'addresses\_%')
+    LOOP
+        EXECUTE format('alter table %I add column if not exists seq bigserial', t.table_name);
+        EXECUTE format('create index if not exists %I on %I (chain, seq)', t.table_name || '_chain_seq', t.table_name);
+    END LOOP;
+END
+$$;
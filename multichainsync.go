@@ -2,6 +2,8 @@ package multichain_transaction_syncs
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -15,81 +17,194 @@ import (
 	"github.com/JokingLove/multichain-sync-account/worker"
 )
 
-type MultiChainSync struct {
-	Synchronizer *worker.BaseSynchronizer
+// ChainSync 单条链的充值扫块、确认数跟踪、提现和内部交易发送任务
+type ChainSync struct {
 	Deposit      *worker.Deposit
+	Confirmation *worker.ConfirmationTracker
 	Withdraw     *worker.Withdraw
 	Internal     *worker.Internal
-
-	shutdown context.CancelCauseFunc
-	stopped  atomic.Bool
+	Collection   *worker.Collection
+	Rebalancer   *worker.Rebalancer
+	Reconciler   *worker.Reconciler
+	Snapshotter  *worker.Snapshotter
+	Watchdog     *worker.Watchdog
 }
 
-func NewMultiChainSync(ctx context.Context, cfg *config.Config, shutdown context.CancelCauseFunc) (*MultiChainSync, error) {
-	db, err := database.NewDB(ctx, cfg.MasterDB)
+func NewChainSync(cfg *config.ChainNodeConfig, db *database.DB, client account.WalletAccountServiceClient, shutdown context.CancelCauseFunc) (*ChainSync, error) {
+	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, cfg.ChainName, cfg.Network)
 	if err != nil {
-		log.Error("init database failed", "err", err)
+		log.Error("new wallet account client fail", "chain", cfg.ChainName, "err", err)
 		return nil, err
 	}
 
-	log.Info("New deposit", "ChainAccountRpc", cfg.ChainAccountRpc)
-	conn, err := grpc.NewClient(cfg.ChainAccountRpc, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		log.Error("Connect to da retriever  fail", "err", err)
-		return nil, err
-	}
-	client := account.NewWalletAccountServiceClient(conn)
-	accountClient, err := rpcclient.NewWalletChainAccountClient(context.Background(), client, "Ethereum")
+	deposit, err := worker.NewDeposit(cfg, db, accountClient, shutdown)
 	if err != nil {
-		log.Error("new wallet account client fail", "err", err)
+		log.Error("new deposit fail", "chain", cfg.ChainName, "err", err)
 		return nil, err
 	}
-
-	deposit, _ := worker.NewDeposit(cfg, db, accountClient, shutdown)
+	confirmation, _ := worker.NewConfirmationTracker(cfg, db, accountClient, shutdown)
 	withdraw, _ := worker.NewWithdraw(cfg, db, accountClient, shutdown)
 	internal, _ := worker.NewInternal(cfg, db, accountClient, shutdown)
+	collection, _ := worker.NewCollection(cfg, db, accountClient, shutdown)
+	rebalancer, _ := worker.NewRebalancer(cfg, db, accountClient, shutdown)
+	reconciler, _ := worker.NewReconciler(cfg, db, accountClient, shutdown)
+	snapshotter, _ := worker.NewSnapshotter(cfg, db, accountClient, shutdown)
+	watchdog, _ := worker.NewWatchdog(cfg, db, accountClient, shutdown)
 
-	out := &MultiChainSync{
-		Deposit:  deposit,
-		Withdraw: withdraw,
-		Internal: internal,
-		shutdown: shutdown,
-	}
-	return out, nil
+	return &ChainSync{
+		Deposit:      deposit,
+		Confirmation: confirmation,
+		Withdraw:     withdraw,
+		Internal:     internal,
+		Collection:   collection,
+		Rebalancer:   rebalancer,
+		Reconciler:   reconciler,
+		Snapshotter:  snapshotter,
+		Watchdog:     watchdog,
+	}, nil
 }
 
-func (mcs *MultiChainSync) Start(ctx context.Context) error {
-	err := mcs.Deposit.Start()
+func (cs *ChainSync) Start() error {
+	err := cs.Deposit.Start()
 	if err != nil {
 		return err
 	}
-	err = mcs.Withdraw.Start()
+	err = cs.Confirmation.Start()
 	if err != nil {
 		return err
 	}
-	err = mcs.Internal.Start()
+	err = cs.Withdraw.Start()
+	if err != nil {
+		return err
+	}
+	err = cs.Internal.Start()
+	if err != nil {
+		return err
+	}
+	err = cs.Collection.Start()
+	if err != nil {
+		return err
+	}
+	err = cs.Rebalancer.Start()
+	if err != nil {
+		return err
+	}
+	err = cs.Reconciler.Start()
+	if err != nil {
+		return err
+	}
+	err = cs.Snapshotter.Start()
+	if err != nil {
+		return err
+	}
+	err = cs.Watchdog.Start()
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (mcs *MultiChainSync) Stop(ctx context.Context) error {
-	err := mcs.Deposit.Close()
+func (cs *ChainSync) Stop() error {
+	err := cs.Deposit.Close()
+	if err != nil {
+		return err
+	}
+	err = cs.Confirmation.Close()
+	if err != nil {
+		return err
+	}
+	err = cs.Withdraw.Close()
+	if err != nil {
+		return err
+	}
+	err = cs.Internal.Close()
+	if err != nil {
+		return err
+	}
+	err = cs.Collection.Close()
+	if err != nil {
+		return err
+	}
+	err = cs.Rebalancer.Close()
+	if err != nil {
+		return err
+	}
+	err = cs.Reconciler.Close()
 	if err != nil {
 		return err
 	}
-	err = mcs.Withdraw.Close()
+	err = cs.Snapshotter.Close()
 	if err != nil {
 		return err
 	}
-	err = mcs.Internal.Close()
+	err = cs.Watchdog.Close()
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// MultiChainSync 按配置为每条链启动一组同步任务，以链名称索引
+type MultiChainSync struct {
+	Chains map[string]*ChainSync
+
+	chainNames []string
+	shutdown   context.CancelCauseFunc
+	stopped    atomic.Bool
+}
+
+func NewMultiChainSync(ctx context.Context, cfg *config.Config, shutdown context.CancelCauseFunc) (*MultiChainSync, error) {
+	db, err := database.NewDB(ctx, cfg.MasterDB)
+	if err != nil {
+		log.Error("init database failed", "err", err)
+		return nil, err
+	}
+
+	log.Info("New deposit", "ChainAccountRpc", cfg.ChainAccountRpc)
+	conn, err := grpc.NewClient(cfg.ChainAccountRpc, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Error("Connect to da retriever  fail", "err", err)
+		return nil, err
+	}
+	client := account.NewWalletAccountServiceClient(conn)
+
+	out := &MultiChainSync{
+		Chains:   make(map[string]*ChainSync, len(cfg.Chains)),
+		shutdown: shutdown,
+	}
+	for i := range cfg.Chains {
+		chainCfg := &cfg.Chains[i]
+		chainSync, err := NewChainSync(chainCfg, db, client, shutdown)
+		if err != nil {
+			return nil, err
+		}
+		out.Chains[chainCfg.ChainName] = chainSync
+		out.chainNames = append(out.chainNames, chainCfg.ChainName)
+	}
+	return out, nil
+}
+
+func (mcs *MultiChainSync) Start(ctx context.Context) error {
+	for _, chainName := range mcs.chainNames {
+		log.Info("start chain sync", "chain", chainName)
+		if err := mcs.Chains[chainName].Start(); err != nil {
+			return fmt.Errorf("start %s sync fail: %w", chainName, err)
+		}
+	}
+	return nil
+}
+
+func (mcs *MultiChainSync) Stop(ctx context.Context) error {
+	var result error
+	for _, chainName := range mcs.chainNames {
+		if err := mcs.Chains[chainName].Stop(); err != nil {
+			result = errors.Join(result, fmt.Errorf("stop %s sync fail: %w", chainName, err))
+		}
+	}
+	mcs.stopped.Store(true)
+	return result
+}
+
 func (mcs *MultiChainSync) Stopped() bool {
 	return mcs.stopped.Load()
 }
@@ -57,3 +57,49 @@ func (nc *NotifyClient) BusinessNotify(notifyData *NotifyRequest) (bool, error)
 	}
 	return spt.Success, nil
 }
+
+// BusinessAlert 推送对账告警，复用通知接口的响应格式
+func (nc *NotifyClient) BusinessAlert(alertData *AlertRequest) (bool, error) {
+	body, err := json.Marshal(alertData)
+	if err != nil {
+		log.Error("failed to marshal alert data", "err", err)
+		return false, err
+	}
+
+	res, err := nc.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		SetResult(&NotifyResponse{}).Post("/dapplink/alert")
+	if err != nil {
+		log.Error("alert http request failed ", "err", err)
+		return false, err
+	}
+	spt, ok := res.Result().(*NotifyResponse)
+	if !ok {
+		return false, fmt.Errorf("alert response is not of type *NotifyResponse")
+	}
+	return spt.Success, nil
+}
+
+// BusinessResign 推送需要重新签名的替换交易，复用通知接口的响应格式
+func (nc *NotifyClient) BusinessResign(resignData *ResignRequest) (bool, error) {
+	body, err := json.Marshal(resignData)
+	if err != nil {
+		log.Error("failed to marshal resign data", "err", err)
+		return false, err
+	}
+
+	res, err := nc.client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		SetResult(&NotifyResponse{}).Post("/dapplink/resign")
+	if err != nil {
+		log.Error("resign http request failed ", "err", err)
+		return false, err
+	}
+	spt, ok := res.Result().(*NotifyResponse)
+	if !ok {
+		return false, fmt.Errorf("resign response is not of type *NotifyResponse")
+	}
+	return spt.Success, nil
+}
@@ -69,7 +69,9 @@ func (nf *Notifier) Start(ctx context.Context) error {
 		for {
 			select {
 			case <-nf.ticker.C:
-				return handleNotify(nf)
+				if err := handleNotify(nf); err != nil {
+					log.Error("handle notify failed", "err", err)
+				}
 			case <-nf.resourceCtx.Done():
 				log.Info("stop notifier in worker")
 				return nil
@@ -91,6 +93,13 @@ func handleNotify(nf *Notifier) error {
 			return err
 		}
 
+		// query deposits whose confirms changed since last notify
+		confirmChangedDeposits, err := nf.db.Deposits.QueryConfirmChangedDeposits(businessId)
+		if err != nil {
+			log.Error("query confirm changed deposits failed", "err", err)
+			return err
+		}
+
 		//  query notify withdraw
 		needNotifyWithdraws, err := nf.db.Withdraws.QueryNotifyWithdraws(businessId)
 		if err != nil {
@@ -105,8 +114,37 @@ func handleNotify(nf *Notifier) error {
 			return err
 		}
 
+		// query failed withdraw and internal
+		failedWithdraws, err := nf.db.Withdraws.QueryFailedWithdraws(businessId)
+		if err != nil {
+			log.Error("query failed withdraws failed", "err", err)
+			return err
+		}
+		failedInternals, err := nf.db.Internals.QueryFailedInternals(businessId)
+		if err != nil {
+			log.Error("query failed internals failed", "err", err)
+			return err
+		}
+
+		// query withdraw and internal given up after too many broadcast failures
+		broadcastFailedWithdraws, err := nf.db.Withdraws.QueryBroadcastFailedWithdraws(businessId)
+		if err != nil {
+			log.Error("query broadcast failed withdraws failed", "err", err)
+			return err
+		}
+		broadcastFailedInternals, err := nf.db.Internals.QueryBroadcastFailedInternals(businessId)
+		if err != nil {
+			log.Error("query broadcast failed internals failed", "err", err)
+			return err
+		}
+
 		// build notify transaction
-		notifyRequest, err := nf.BuildNotifyTransaction(needNotifyDeposits, needNotifyWithdraws, needNotifyInternals)
+		notifyDeposits := append(needNotifyDeposits, confirmChangedDeposits...)
+		notifyWithdraws := append(needNotifyWithdraws, failedWithdraws...)
+		notifyWithdraws = append(notifyWithdraws, broadcastFailedWithdraws...)
+		notifyInternals := append(needNotifyInternals, failedInternals...)
+		notifyInternals = append(notifyInternals, broadcastFailedInternals...)
+		notifyRequest, err := nf.BuildNotifyTransaction(notifyDeposits, notifyWithdraws, notifyInternals)
 		if err != nil {
 			log.Error("build notify transaction failed", "err", err)
 			return err
@@ -133,6 +171,36 @@ func handleNotify(nf *Notifier) error {
 			return err
 		}
 
+		// 失败交易通知成功后不再重复通知
+		if notify {
+			if err := nf.afterFailedNotify(businessId, failedWithdraws, failedInternals); err != nil {
+				log.Error("after failed notify update db status failed", "err", err)
+				return err
+			}
+			if err := nf.afterBroadcastFailedNotify(businessId, broadcastFailedWithdraws, broadcastFailedInternals); err != nil {
+				log.Error("after broadcast failed notify update db status failed", "err", err)
+				return err
+			}
+		}
+
+		// 确认数进度通知成功后记录已通知的确认数，失败时下次继续推送
+		if notify && len(confirmChangedDeposits) > 0 {
+			if err := nf.db.Deposits.MarkDepositsConfirmsNotified(businessId, confirmChangedDeposits); err != nil {
+				log.Error("mark deposits confirms notified failed", "err", err)
+				return err
+			}
+		}
+
+		// 告警失败不影响交易通知，下次继续推送
+		if err := nf.alertReconciliations(businessId); err != nil {
+			log.Error("alert reconciliations failed", "business", businessId, "err", err)
+		}
+
+		// 替换交易推送失败时下次继续推送
+		if err := nf.resignReplacements(businessId); err != nil {
+			log.Error("push replacements to resign failed", "business", businessId, "err", err)
+		}
+
 	}
 	return nil
 }
@@ -150,6 +218,7 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 			Value:        deposit.Amount.String(),
 			Fee:          deposit.MaxFeePerGas,
 			TxType:       deposit.TxType,
+			Status:       deposit.Status,
 			Confirms:     deposit.Confirms,
 			TokenAddress: deposit.TokenAddress.String(),
 			TokenId:      deposit.TokenId,
@@ -168,11 +237,15 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 			Value:        withdraw.Amount.String(),
 			Fee:          withdraw.MaxFeePerGas,
 			TxType:       withdraw.TxType,
+			Status:       withdraw.Status,
 			Confirms:     0,
 			TokenAddress: withdraw.TokenAddress.String(),
 			TokenId:      withdraw.TokenId,
 			TokenMeta:    withdraw.TokenMeta,
 		}
+		if withdraw.Status == database.TxStatusBroadcastFailed {
+			txItem.Error = withdraw.LastError
+		}
 		notifyTransactions = append(notifyTransactions, txItem)
 	}
 
@@ -186,11 +259,15 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 			Value:        internal.Amount.String(),
 			Fee:          internal.MaxFeePerGas,
 			TxType:       internal.TxType,
+			Status:       internal.Status,
 			Confirms:     0,
 			TokenAddress: internal.TokenAddress.String(),
 			TokenId:      internal.TokenId,
 			TokenMeta:    internal.TokenMeta,
 		}
+		if internal.Status == database.TxStatusBroadcastFailed {
+			txItem.Error = internal.LastError
+		}
 		notifyTransactions = append(notifyTransactions, txItem)
 	}
 
@@ -200,6 +277,101 @@ func (nf *Notifier) BuildNotifyTransaction(deposits []*database.Deposits, withdr
 	return notifyReq, nil
 }
 
+// alertReconciliations 推送差额超过容差的对账记录，推送成功后不再重复告警
+func (nf *Notifier) alertReconciliations(businessId string) error {
+	reconciliations, err := nf.db.Reconciles.QueryUnalertedReconciliations(businessId)
+	if err != nil {
+		return err
+	}
+	if len(reconciliations) == 0 {
+		return nil
+	}
+
+	alertRequest := &AlertRequest{}
+	for _, reconciliation := range reconciliations {
+		alertRequest.Reconciliations = append(alertRequest.Reconciliations, &Reconciliation{
+			Chain:         reconciliation.Chain,
+			Address:       reconciliation.Address.String(),
+			AddressType:   string(reconciliation.AddressType),
+			TokenAddress:  reconciliation.TokenAddress.String(),
+			LedgerBalance: reconciliation.LedgerBalance.String(),
+			ChainBalance:  reconciliation.ChainBalance.String(),
+			Difference:    reconciliation.Difference.String(),
+			Timestamp:     reconciliation.Timestamp,
+		})
+	}
+	alerted, err := nf.notifyClient[businessId].BusinessAlert(alertRequest)
+	if err != nil {
+		return err
+	}
+	if !alerted {
+		return nil
+	}
+	return nf.db.Reconciles.MarkReconciliationsAlerted(businessId, reconciliations)
+}
+
+// resignReplacements 推送等待重新签名的替换交易，推送成功后不再重复推送
+func (nf *Notifier) resignReplacements(businessId string) error {
+	replacements, err := nf.db.Replaces.QueryUnnotifiedReplacements(businessId)
+	if err != nil {
+		return err
+	}
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	resignRequest := &ResignRequest{}
+	for _, replacement := range replacements {
+		resignRequest.Replacements = append(resignRequest.Replacements, &Replacement{
+			ReplacementId:        replacement.GUID.String(),
+			TransactionId:        replacement.TransactionId,
+			TxType:               replacement.TxType,
+			Chain:                replacement.Chain,
+			Nonce:                replacement.Nonce,
+			FromAddress:          replacement.FromAddress.String(),
+			ToAddress:            replacement.ToAddress.String(),
+			Value:                replacement.Amount.String(),
+			TokenAddress:         replacement.TokenAddress.String(),
+			MaxFeePerGas:         replacement.MaxFeePerGas,
+			MaxPriorityFeePerGas: replacement.MaxPriorityFeePerGas,
+			UnSignTx:             replacement.UnSignTx,
+		})
+	}
+	pushed, err := nf.notifyClient[businessId].BusinessResign(resignRequest)
+	if err != nil {
+		return err
+	}
+	if !pushed {
+		return nil
+	}
+	return nf.db.Replaces.MarkReplacementsNotified(businessId, replacements)
+}
+
+func (nf *Notifier) afterFailedNotify(businessId string, withdraws []*database.Withdraws, internals []*database.Internals) error {
+	if len(withdraws) == 0 && len(internals) == 0 {
+		return nil
+	}
+	return nf.db.Transaction(func(tx *database.DB) error {
+		if err := tx.Withdraws.UpdateWithdrawStatusByTxHash(businessId, database.TxStatusFailedNotified, withdraws); err != nil {
+			return err
+		}
+		return tx.Internals.UpdateInternalStatusByTxHash(businessId, database.TxStatusFailedNotified, internals)
+	})
+}
+
+// afterBroadcastFailedNotify 广播失败的交易没有交易哈希，按 guid 更新状态
+func (nf *Notifier) afterBroadcastFailedNotify(businessId string, withdraws []*database.Withdraws, internals []*database.Internals) error {
+	if len(withdraws) == 0 && len(internals) == 0 {
+		return nil
+	}
+	return nf.db.Transaction(func(tx *database.DB) error {
+		if err := tx.Withdraws.UpdateWithdrawStatusById(businessId, database.TxStatusFailedNotified, withdraws); err != nil {
+			return err
+		}
+		return tx.Internals.UpdateInternalStatusById(businessId, database.TxStatusFailedNotified, internals)
+	})
+}
+
 func (nf *Notifier) Stop(ctx context.Context) error {
 	var result error
 	nf.resourceCancel()
@@ -23,8 +23,46 @@ type Transaction struct {
 	Value        string                   `json:"value"`
 	Fee          string                   `json:"fee"`
 	TxType       database.TransactionType `json:"tx_type"`
+	Status       database.TxStatus        `json:"status"`
 	Confirms     uint8                    `json:"confirms"`
 	TokenAddress string                   `json:"token_address"`
 	TokenId      string                   `json:"token_id"`
 	TokenMeta    string                   `json:"token_meta"`
+	Error        string                   `json:"error,omitempty"` // 多次广播失败时最后一次的失败原因
+}
+
+// AlertRequest 对账差额超过容差时推送给业务方的告警
+type AlertRequest struct {
+	Reconciliations []*Reconciliation `json:"reconciliations"`
+}
+
+type Reconciliation struct {
+	Chain         string `json:"chain"`
+	Address       string `json:"address"`
+	AddressType   string `json:"address_type"`
+	TokenAddress  string `json:"token_address"`
+	LedgerBalance string `json:"ledger_balance"`
+	ChainBalance  string `json:"chain_balance"`
+	Difference    string `json:"difference"`
+	Timestamp     uint64 `json:"timestamp"`
+}
+
+// ResignRequest 卡住交易的替换交易需要业务方重新签名，签名后通过 signReplacement 提交
+type ResignRequest struct {
+	Replacements []*Replacement `json:"replacements"`
+}
+
+type Replacement struct {
+	ReplacementId        string                   `json:"replacement_id"`
+	TransactionId        string                   `json:"transaction_id"`
+	TxType               database.TransactionType `json:"tx_type"`
+	Chain                string                   `json:"chain"`
+	Nonce                uint64                   `json:"nonce"`
+	FromAddress          string                   `json:"from_address"`
+	ToAddress            string                   `json:"to_address"`
+	Value                string                   `json:"value"`
+	TokenAddress         string                   `json:"token_address"`
+	MaxFeePerGas         string                   `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string                   `json:"max_priority_fee_per_gas"`
+	UnSignTx             string                   `json:"un_sign_tx"`
 }
@@ -24,6 +24,11 @@ const (
 	BusinessMiddleWireService_CreateUnSignTransaction_FullMethodName     = "/syncs.BusinessMiddleWireService/createUnSignTransaction"
 	BusinessMiddleWireService_BuildSignedTransaction_FullMethodName      = "/syncs.BusinessMiddleWireService/buildSignedTransaction"
 	BusinessMiddleWireService_SetTokenAddress_FullMethodName             = "/syncs.BusinessMiddleWireService/setTokenAddress"
+	BusinessMiddleWireService_QuerySuspenseDeposits_FullMethodName       = "/syncs.BusinessMiddleWireService/querySuspenseDeposits"
+	BusinessMiddleWireService_PromoteToken_FullMethodName                = "/syncs.BusinessMiddleWireService/promoteToken"
+	BusinessMiddleWireService_QueryUnsignedInternals_FullMethodName      = "/syncs.BusinessMiddleWireService/queryUnsignedInternals"
+	BusinessMiddleWireService_QueryHistoricalBalance_FullMethodName      = "/syncs.BusinessMiddleWireService/queryHistoricalBalance"
+	BusinessMiddleWireService_SignReplacement_FullMethodName             = "/syncs.BusinessMiddleWireService/signReplacement"
 )
 
 // BusinessMiddleWireServiceClient is the client API for BusinessMiddleWireService service.
@@ -35,6 +40,11 @@ type BusinessMiddleWireServiceClient interface {
 	CreateUnSignTransaction(ctx context.Context, in *UnSignTransactionRequest, opts ...grpc.CallOption) (*UnSignTransactionResponse, error)
 	BuildSignedTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error)
 	SetTokenAddress(ctx context.Context, in *SetTokenAddressRequest, opts ...grpc.CallOption) (*SetTokenAddressResponse, error)
+	QuerySuspenseDeposits(ctx context.Context, in *SuspenseDepositsRequest, opts ...grpc.CallOption) (*SuspenseDepositsResponse, error)
+	PromoteToken(ctx context.Context, in *PromoteTokenRequest, opts ...grpc.CallOption) (*PromoteTokenResponse, error)
+	QueryUnsignedInternals(ctx context.Context, in *UnsignedInternalsRequest, opts ...grpc.CallOption) (*UnsignedInternalsResponse, error)
+	QueryHistoricalBalance(ctx context.Context, in *HistoricalBalanceRequest, opts ...grpc.CallOption) (*HistoricalBalanceResponse, error)
+	SignReplacement(ctx context.Context, in *SignReplacementRequest, opts ...grpc.CallOption) (*SignReplacementResponse, error)
 }
 
 type businessMiddleWireServiceClient struct {
@@ -95,6 +105,56 @@ func (c *businessMiddleWireServiceClient) SetTokenAddress(ctx context.Context, i
 	return out, nil
 }
 
+func (c *businessMiddleWireServiceClient) QuerySuspenseDeposits(ctx context.Context, in *SuspenseDepositsRequest, opts ...grpc.CallOption) (*SuspenseDepositsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuspenseDepositsResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireService_QuerySuspenseDeposits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServiceClient) PromoteToken(ctx context.Context, in *PromoteTokenRequest, opts ...grpc.CallOption) (*PromoteTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PromoteTokenResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireService_PromoteToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServiceClient) QueryUnsignedInternals(ctx context.Context, in *UnsignedInternalsRequest, opts ...grpc.CallOption) (*UnsignedInternalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnsignedInternalsResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireService_QueryUnsignedInternals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServiceClient) QueryHistoricalBalance(ctx context.Context, in *HistoricalBalanceRequest, opts ...grpc.CallOption) (*HistoricalBalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoricalBalanceResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireService_QueryHistoricalBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *businessMiddleWireServiceClient) SignReplacement(ctx context.Context, in *SignReplacementRequest, opts ...grpc.CallOption) (*SignReplacementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignReplacementResponse)
+	err := c.cc.Invoke(ctx, BusinessMiddleWireService_SignReplacement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BusinessMiddleWireServiceServer is the server API for BusinessMiddleWireService service.
 // All implementations should embed UnimplementedBusinessMiddleWireServiceServer
 // for forward compatibility.
@@ -104,6 +164,11 @@ type BusinessMiddleWireServiceServer interface {
 	CreateUnSignTransaction(context.Context, *UnSignTransactionRequest) (*UnSignTransactionResponse, error)
 	BuildSignedTransaction(context.Context, *SignTransactionRequest) (*SignTransactionResponse, error)
 	SetTokenAddress(context.Context, *SetTokenAddressRequest) (*SetTokenAddressResponse, error)
+	QuerySuspenseDeposits(context.Context, *SuspenseDepositsRequest) (*SuspenseDepositsResponse, error)
+	PromoteToken(context.Context, *PromoteTokenRequest) (*PromoteTokenResponse, error)
+	QueryUnsignedInternals(context.Context, *UnsignedInternalsRequest) (*UnsignedInternalsResponse, error)
+	QueryHistoricalBalance(context.Context, *HistoricalBalanceRequest) (*HistoricalBalanceResponse, error)
+	SignReplacement(context.Context, *SignReplacementRequest) (*SignReplacementResponse, error)
 }
 
 // UnimplementedBusinessMiddleWireServiceServer should be embedded to have
@@ -128,6 +193,21 @@ func (UnimplementedBusinessMiddleWireServiceServer) BuildSignedTransaction(conte
 func (UnimplementedBusinessMiddleWireServiceServer) SetTokenAddress(context.Context, *SetTokenAddressRequest) (*SetTokenAddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetTokenAddress not implemented")
 }
+func (UnimplementedBusinessMiddleWireServiceServer) QuerySuspenseDeposits(context.Context, *SuspenseDepositsRequest) (*SuspenseDepositsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuerySuspenseDeposits not implemented")
+}
+func (UnimplementedBusinessMiddleWireServiceServer) PromoteToken(context.Context, *PromoteTokenRequest) (*PromoteTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PromoteToken not implemented")
+}
+func (UnimplementedBusinessMiddleWireServiceServer) QueryUnsignedInternals(context.Context, *UnsignedInternalsRequest) (*UnsignedInternalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryUnsignedInternals not implemented")
+}
+func (UnimplementedBusinessMiddleWireServiceServer) QueryHistoricalBalance(context.Context, *HistoricalBalanceRequest) (*HistoricalBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryHistoricalBalance not implemented")
+}
+func (UnimplementedBusinessMiddleWireServiceServer) SignReplacement(context.Context, *SignReplacementRequest) (*SignReplacementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignReplacement not implemented")
+}
 func (UnimplementedBusinessMiddleWireServiceServer) testEmbeddedByValue() {}
 
 // UnsafeBusinessMiddleWireServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -238,6 +318,96 @@ func _BusinessMiddleWireService_SetTokenAddress_Handler(srv interface{}, ctx con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BusinessMiddleWireService_QuerySuspenseDeposits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuspenseDepositsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServiceServer).QuerySuspenseDeposits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireService_QuerySuspenseDeposits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServiceServer).QuerySuspenseDeposits(ctx, req.(*SuspenseDepositsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireService_PromoteToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServiceServer).PromoteToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireService_PromoteToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServiceServer).PromoteToken(ctx, req.(*PromoteTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireService_QueryUnsignedInternals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsignedInternalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServiceServer).QueryUnsignedInternals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireService_QueryUnsignedInternals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServiceServer).QueryUnsignedInternals(ctx, req.(*UnsignedInternalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireService_QueryHistoricalBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoricalBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServiceServer).QueryHistoricalBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireService_QueryHistoricalBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServiceServer).QueryHistoricalBalance(ctx, req.(*HistoricalBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BusinessMiddleWireService_SignReplacement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignReplacementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BusinessMiddleWireServiceServer).SignReplacement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BusinessMiddleWireService_SignReplacement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BusinessMiddleWireServiceServer).SignReplacement(ctx, req.(*SignReplacementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BusinessMiddleWireService_ServiceDesc is the grpc.ServiceDesc for BusinessMiddleWireService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -265,6 +435,26 @@ var BusinessMiddleWireService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "setTokenAddress",
 			Handler:    _BusinessMiddleWireService_SetTokenAddress_Handler,
 		},
+		{
+			MethodName: "querySuspenseDeposits",
+			Handler:    _BusinessMiddleWireService_QuerySuspenseDeposits_Handler,
+		},
+		{
+			MethodName: "promoteToken",
+			Handler:    _BusinessMiddleWireService_PromoteToken_Handler,
+		},
+		{
+			MethodName: "queryUnsignedInternals",
+			Handler:    _BusinessMiddleWireService_QueryUnsignedInternals_Handler,
+		},
+		{
+			MethodName: "queryHistoricalBalance",
+			Handler:    _BusinessMiddleWireService_QueryHistoricalBalance_Handler,
+		},
+		{
+			MethodName: "signReplacement",
+			Handler:    _BusinessMiddleWireService_SignReplacement_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "protobuf/dapplink-wallet.proto",
@@ -21,12 +21,18 @@ type BatchBlock struct {
 	lastTraversedHeader *BlockHeader
 
 	blockConfirmationDepth *big.Int
+
+	// 链账户服务不支持按区间拉取区块头时，退回逐块拉取
+	rangeUnsupported bool
 }
 
+// NewBatchBlock 以 fromHeader 作为已遍历的最后一个区块，NextHeaders 从 fromHeader 的下一个高度开始拉取；
+// fromHeader 为 nil 时从创世区块开始
 func NewBatchBlock(rpcClient *WalletChainAccountClient, fromHeader *BlockHeader, confDepth *big.Int) *BatchBlock {
 	return &BatchBlock{
 		rpcClient:              rpcClient,
 		latestHeader:           fromHeader,
+		lastTraversedHeader:    fromHeader,
 		blockConfirmationDepth: confDepth,
 	}
 }
@@ -39,6 +45,11 @@ func (f *BatchBlock) LastTraversedHeader() *BlockHeader {
 	return f.lastTraversedHeader
 }
 
+// Reset 链重组回滚后把已遍历的最后一个区块退回到 header，NextHeaders 从 header 的下一个高度继续拉取
+func (f *BatchBlock) Reset(header *BlockHeader) {
+	f.lastTraversedHeader = header
+}
+
 func (f *BatchBlock) NextHeaders(maxSize uint64) ([]BlockHeader, error) {
 	latestHeader, err := f.rpcClient.GetBlockHeader(nil)
 	if err != nil {
@@ -66,10 +77,38 @@ func (f *BatchBlock) NextHeaders(maxSize uint64) ([]BlockHeader, error) {
 		nextHeight = new(big.Int).Add(f.lastTraversedHeader.Number, bigint.One)
 	}
 	endHeight = bigint.Clamp(nextHeight, endHeight, maxSize)
-	count := new(big.Int).Sub(endHeight, nextHeight).Uint64() + 1
+	headers, err := f.headersByRange(nextHeight, endHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	numHeaders := len(headers)
+	if numHeaders == 0 {
+		return nil, nil
+	}
+
+	f.lastTraversedHeader = &headers[numHeaders-1]
+	return headers, nil
+}
+
+func (f *BatchBlock) headersByRange(start, end *big.Int) ([]BlockHeader, error) {
+	count := new(big.Int).Sub(end, start).Uint64() + 1
+	if !f.rangeUnsupported {
+		headers, err := f.rpcClient.GetBlockHeadersByRange(start, end)
+		if err == nil && isContinuousRange(headers, start, count) {
+			return headers, nil
+		}
+		if errors.Is(err, ErrBlockHeaderByRangeUnsupported) {
+			log.Warn("block header by range unsupported, fall back to fetch block by block")
+			f.rangeUnsupported = true
+		} else {
+			log.Warn("get block header by range fail, fall back to fetch block by block", "start", start, "end", end, "err", err)
+		}
+	}
+
 	headers := make([]BlockHeader, count)
 	for i := uint64(0); i < count; i++ {
-		height := new(big.Int).Add(nextHeight, new(big.Int).SetUint64(i))
+		height := new(big.Int).Add(start, new(big.Int).SetUint64(i))
 		blockHeader, err := f.rpcClient.GetBlockHeader(height)
 		if err != nil {
 			log.Error("get block info fail", "err", err)
@@ -77,12 +116,19 @@ func (f *BatchBlock) NextHeaders(maxSize uint64) ([]BlockHeader, error) {
 		}
 		headers[i] = *blockHeader
 	}
+	return headers, nil
+}
 
-	numHeaders := len(headers)
-	if numHeaders == 0 {
-		return nil, nil
+// isContinuousRange 校验区间接口返回的区块头数量和高度是否与请求区间一致
+func isContinuousRange(headers []BlockHeader, start *big.Int, count uint64) bool {
+	if uint64(len(headers)) != count {
+		return false
 	}
-
-	f.lastTraversedHeader = &headers[numHeaders-1]
-	return headers, nil
+	for i := range headers {
+		expected := new(big.Int).Add(start, new(big.Int).SetUint64(uint64(i)))
+		if headers[i].Number == nil || headers[i].Number.Cmp(expected) != 0 {
+			return false
+		}
+	}
+	return true
 }
@@ -2,25 +2,36 @@ package rpcclient
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"math/big"
 	"strconv"
 
 	common2 "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
-	
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/JokingLove/multichain-sync-account/common/json2"
 	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
 	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/common"
 )
 
+var (
+	ErrBlockHeaderByRangeUnsupported = errors.New("get block header by range is not supported by chain account")
+)
+
 type WalletChainAccountClient struct {
 	Ctx              context.Context
 	ChainName        string
+	Network          string
 	AccountRpcClient account.WalletAccountServiceClient
 }
 
-func NewWalletChainAccountClient(ctx context.Context, rcp account.WalletAccountServiceClient, chainName string) (*WalletChainAccountClient, error) {
-	log.Info("New account chain rpc client", "chainName", chainName)
-	return &WalletChainAccountClient{Ctx: ctx, ChainName: chainName, AccountRpcClient: rcp}, nil
+func NewWalletChainAccountClient(ctx context.Context, rcp account.WalletAccountServiceClient, chainName string, network string) (*WalletChainAccountClient, error) {
+	log.Info("New account chain rpc client", "chainName", chainName, "network", network)
+	return &WalletChainAccountClient{Ctx: ctx, ChainName: chainName, Network: network, AccountRpcClient: rcp}, nil
 }
 
 func (wac *WalletChainAccountClient) ExportAddressByPubKey(typeOrVersion, publicKey string) string {
@@ -52,7 +63,7 @@ func (wac *WalletChainAccountClient) GetBlockHeader(number *big.Int) (*BlockHead
 
 	req := &account.BlockHeaderNumberRequest{
 		Chain:   wac.ChainName,
-		Network: "mainnet",
+		Network: wac.Network,
 		Height:  height,
 	}
 
@@ -74,6 +85,70 @@ func (wac *WalletChainAccountClient) GetBlockHeader(number *big.Int) (*BlockHead
 	}, nil
 }
 
+func (wac *WalletChainAccountClient) GetBlockHeaderByHash(hash common2.Hash) (*BlockHeader, error) {
+	req := &account.BlockHeaderHashRequest{
+		Chain:   wac.ChainName,
+		Network: wac.Network,
+		Hash:    hash.String(),
+	}
+
+	blockHeader, err := wac.AccountRpcClient.GetBlockHeaderByHash(wac.Ctx, req)
+	if err != nil {
+		log.Error("get block header GetBlockHeaderByHash failed", "hash", hash, "err", err)
+		return nil, err
+	}
+	if blockHeader.Code == common.ReturnCode_ERROR || blockHeader.BlockHeader == nil {
+		log.Error("get block header by hash fail", "hash", hash, "msg", blockHeader.Msg)
+		return nil, fmt.Errorf("get block header by hash fail: %s", blockHeader.Msg)
+	}
+	blockNumber, _ := new(big.Int).SetString(blockHeader.BlockHeader.Number, 10)
+	return &BlockHeader{
+		Hash:       common2.HexToHash(blockHeader.BlockHeader.Hash),
+		ParentHash: common2.HexToHash(blockHeader.BlockHeader.ParentHash),
+		Number:     blockNumber,
+		Timestamp:  blockHeader.BlockHeader.Time,
+	}, nil
+}
+
+// GetBlockHeadersByRange 一次请求拉取 [start, end] 区间内的区块头，
+// 链账户服务未实现该接口时返回 ErrBlockHeaderByRangeUnsupported
+func (wac *WalletChainAccountClient) GetBlockHeadersByRange(start, end *big.Int) ([]BlockHeader, error) {
+	req := &account.BlockByRangeRequest{
+		Chain:   wac.ChainName,
+		Network: wac.Network,
+		Start:   start.String(),
+		End:     end.String(),
+	}
+
+	blockHeaders, err := wac.AccountRpcClient.GetBlockHeaderByRange(wac.Ctx, req)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil, ErrBlockHeaderByRangeUnsupported
+		}
+		log.Error("get block header GetBlockHeaderByRange failed", "start", start, "end", end, "err", err)
+		return nil, err
+	}
+	if blockHeaders.Code == common.ReturnCode_ERROR {
+		log.Error("get block header by range fail", "start", start, "end", end, "msg", blockHeaders.Msg)
+		return nil, fmt.Errorf("get block header by range fail: %s", blockHeaders.Msg)
+	}
+
+	headers := make([]BlockHeader, 0, len(blockHeaders.BlockHeader))
+	for _, blockHeader := range blockHeaders.BlockHeader {
+		blockNumber, ok := new(big.Int).SetString(blockHeader.Number, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid block number: %s", blockHeader.Number)
+		}
+		headers = append(headers, BlockHeader{
+			Hash:       common2.HexToHash(blockHeader.Hash),
+			ParentHash: common2.HexToHash(blockHeader.ParentHash),
+			Number:     blockNumber,
+			Timestamp:  blockHeader.Time,
+		})
+	}
+	return headers, nil
+}
+
 func (wac *WalletChainAccountClient) GetBlockInfo(blockNumber *big.Int) ([]*account.BlockInfoTransactionList, error) {
 	req := &account.BlockNumberRequest{
 		Chain:  wac.ChainName,
@@ -86,8 +161,8 @@ func (wac *WalletChainAccountClient) GetBlockInfo(blockNumber *big.Int) ([]*acco
 		return nil, err
 	}
 	if blockInfo.Code == common.ReturnCode_ERROR {
-		log.Error("get latest block fail", "err", err)
-		return nil, err
+		log.Error("get block info fail", "height", blockNumber, "msg", blockInfo.Msg)
+		return nil, fmt.Errorf("get block info fail: %s", blockInfo.Msg)
 	}
 
 	return blockInfo.Transactions, nil
@@ -97,7 +172,7 @@ func (wac *WalletChainAccountClient) GetTransactionByHash(hash string) (*account
 	req := &account.TxHashRequest{
 		Chain:   wac.ChainName,
 		Hash:    hash,
-		Network: "mainnet",
+		Network: wac.Network,
 	}
 	tx, err := wac.AccountRpcClient.GetTxByHash(wac.Ctx, req)
 	if err != nil {
@@ -115,7 +190,7 @@ func (wac *WalletChainAccountClient) GetAccountAccountNumber(address string) (in
 	req := &account.AccountRequest{
 		Chain:   wac.ChainName,
 		Address: address,
-		Network: "mainnet",
+		Network: wac.Network,
 	}
 	account, err := wac.AccountRpcClient.GetAccount(wac.Ctx, req)
 	if err != nil {
@@ -129,42 +204,50 @@ func (wac *WalletChainAccountClient) GetAccountAccountNumber(address string) (in
 	return strconv.Atoi(account.AccountNumber)
 }
 
-func (wac *WalletChainAccountClient) GetAccount(address string) (int, int, int) {
+// AccountInfo 链上账户信息，Balance 按最小单位计，wei 等金额超出 int 范围，使用 big.Int
+type AccountInfo struct {
+	AccountNumber uint64
+	Sequence      uint64
+	Balance       *big.Int
+}
+
+// GetAccount 查询地址的账户信息，contractAddress 为 "0x00" 时 Balance 为原生币余额，否则为该代币余额
+func (wac *WalletChainAccountClient) GetAccount(address string, contractAddress string) (*AccountInfo, error) {
 	req := &account.AccountRequest{
 		Chain:           wac.ChainName,
 		Address:         address,
-		Network:         "mainnet",
-		ContractAddress: "0x00",
+		Network:         wac.Network,
+		ContractAddress: contractAddress,
 	}
 	account, err := wac.AccountRpcClient.GetAccount(wac.Ctx, req)
 	if err != nil {
-		log.Info("get  account info GetAccountAccount failed", "err", err)
-		return 0, 0, 0
+		log.Error("get account info GetAccount failed", "address", address, "contractAddress", contractAddress, "err", err)
+		return nil, err
 	}
-
 	if account.Code == common.ReturnCode_ERROR {
-		log.Info("get account info fail", "err", err)
-		return 0, 0, 0
+		log.Error("get account info fail", "address", address, "contractAddress", contractAddress, "msg", account.Msg)
+		return nil, fmt.Errorf("get account info fail: %s", account.Msg)
 	}
 
-	accountNumber, err := strconv.Atoi(account.AccountNumber)
-	if err != nil {
-		log.Error("convert account account number to int failed", "err", err)
-		return 0, 0, 0
+	info := &AccountInfo{Balance: big.NewInt(0)}
+	if account.AccountNumber != "" {
+		if info.AccountNumber, err = strconv.ParseUint(account.AccountNumber, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid account number %s: %w", account.AccountNumber, err)
+		}
 	}
-
-	sequence, err := strconv.Atoi(account.Sequence)
-	if err != nil {
-		log.Error("convert account account sequence to int failed", "err", err)
-		return 0, 0, 0
+	if account.Sequence != "" {
+		if info.Sequence, err = strconv.ParseUint(account.Sequence, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid account sequence %s: %w", account.Sequence, err)
+		}
 	}
-
-	balance, err := strconv.Atoi(account.Balance)
-	if err != nil {
-		log.Error("convert account account balance to int failed", "err", err)
-		return 0, 0, 0
+	if account.Balance != "" {
+		balance, ok := new(big.Int).SetString(account.Balance, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid account balance: %s", account.Balance)
+		}
+		info.Balance = balance
 	}
-	return accountNumber, sequence, balance
+	return info, nil
 }
 
 func (wac *WalletChainAccountClient) SendTx(rawTx string) (string, error) {
@@ -172,7 +255,7 @@ func (wac *WalletChainAccountClient) SendTx(rawTx string) (string, error) {
 	req := &account.SendTxRequest{
 		Chain:   wac.ChainName,
 		RawTx:   rawTx,
-		Network: "mainnet",
+		Network: wac.Network,
 	}
 
 	txInfo, err := wac.AccountRpcClient.SendTx(wac.Ctx, req)
@@ -182,11 +265,69 @@ func (wac *WalletChainAccountClient) SendTx(rawTx string) (string, error) {
 	}
 	if txInfo == nil {
 		log.Error("send tx failed , txInfo is null", "err", err)
-		return "", err
+		return "", errors.New("send tx fail: empty response")
 	}
 	if txInfo.Code == common.ReturnCode_ERROR {
-		log.Error("send tx failed", "err", err)
-		return "", err
+		log.Error("send tx failed", "msg", txInfo.Msg)
+		return "", fmt.Errorf("send tx fail: %s", txInfo.Msg)
 	}
 	return txInfo.TxHash, nil
 }
+
+// CreateUnSignTransaction 由链账户服务构建待签名交易，返回需要业务方签名的数据
+func (wac *WalletChainAccountClient) CreateUnSignTransaction(tx *Eip1559DynamicFeeTx) (string, error) {
+	req := &account.UnSignTransactionRequest{
+		Chain:    wac.ChainName,
+		Network:  wac.Network,
+		Base64Tx: base64.StdEncoding.EncodeToString(json2.ToJSON(tx)),
+	}
+	unSignTx, err := wac.AccountRpcClient.CreateUnSignTransaction(wac.Ctx, req)
+	if err != nil {
+		log.Error("create un sign transaction CreateUnSignTransaction failed", "err", err)
+		return "", err
+	}
+	if unSignTx.Code == common.ReturnCode_ERROR {
+		log.Error("create un sign transaction fail", "msg", unSignTx.Msg)
+		return "", fmt.Errorf("create un sign transaction fail: %s", unSignTx.Msg)
+	}
+	return unSignTx.UnSignTx, nil
+}
+
+// BuildSignedTransaction 用业务方的签名组装可以广播的交易，tx 必须与构建待签名交易时一致
+func (wac *WalletChainAccountClient) BuildSignedTransaction(tx *Eip1559DynamicFeeTx, signature string) (string, error) {
+	req := &account.SignedTransactionRequest{
+		Chain:     wac.ChainName,
+		Network:   wac.Network,
+		Signature: signature,
+		Base64Tx:  base64.StdEncoding.EncodeToString(json2.ToJSON(tx)),
+	}
+	signedTx, err := wac.AccountRpcClient.BuildSignedTransaction(wac.Ctx, req)
+	if err != nil {
+		log.Error("build signed transaction BuildSignedTransaction failed", "err", err)
+		return "", err
+	}
+	if signedTx.Code == common.ReturnCode_ERROR {
+		log.Error("build signed transaction fail", "msg", signedTx.Msg)
+		return "", fmt.Errorf("build signed transaction fail: %s", signedTx.Msg)
+	}
+	return signedTx.SignedTx, nil
+}
+
+// ValidAddress 由链账户服务按链的规则校验地址，地址格式不合法时返回 false
+func (wac *WalletChainAccountClient) ValidAddress(address string) (bool, error) {
+	req := &account.ValidAddressRequest{
+		Chain:   wac.ChainName,
+		Network: wac.Network,
+		Address: address,
+	}
+	valid, err := wac.AccountRpcClient.ValidAddress(wac.Ctx, req)
+	if err != nil {
+		log.Error("valid address ValidAddress failed", "address", address, "err", err)
+		return false, err
+	}
+	if valid.Code == common.ReturnCode_ERROR {
+		log.Error("valid address fail", "address", address, "msg", valid.Msg)
+		return false, fmt.Errorf("valid address fail: %s", valid.Msg)
+	}
+	return valid.Valid, nil
+}
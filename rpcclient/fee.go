@@ -0,0 +1,101 @@
+package rpcclient
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/common"
+)
+
+var (
+	EthGasLimit   uint64 = 60000
+	TokenGasLimit uint64 = 120000
+)
+
+// FeeInfo 结构体用于存储解析后的费用信息
+type FeeInfo struct {
+	GasPrice       *big.Int // 基础 gas 价格
+	GasTipCap      *big.Int // 小费上限
+	Multiplier     int64    // 小费 * 倍数
+	MultipliedTip  *big.Int // 小费 * 倍数
+	MaxPriorityFee *big.Int // 小费 * 倍数 * 2 （最大上限）
+}
+
+// ParseFastFee 解析 FastFee 字符串并计算相关费用
+func ParseFastFee(fee string) (*FeeInfo, error) {
+	// 1. 按 “|" 分隔字符串
+	parts := strings.Split(fee, "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid fee format: %s", fee)
+	}
+	// 2. 解析 GasPrice （baseFee)
+	gasPrice := new(big.Int)
+	if _, ok := gasPrice.SetString(parts[0], 10); !ok {
+		return nil, fmt.Errorf("invalid gas price: %s", parts[0])
+	}
+
+	// 3. 解析  GasTipCap
+	gasTipCap := new(big.Int)
+	if _, ok := gasTipCap.SetString(parts[1], 10); !ok {
+		return nil, fmt.Errorf("invalid gas tip cap: %s", parts[1])
+	}
+
+	// 4. 解析倍数 （去掉 * 前缀）
+	multiplierStr := strings.TrimPrefix(parts[2], "*")
+	multiplier, err := strconv.ParseInt(multiplierStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid multiplier: %s", parts[2])
+	}
+
+	// 5.计算 MultipliedTip (小费 * 倍数）
+	multipliedTip := new(big.Int).Mul(gasTipCap, big.NewInt(multiplier))
+
+	// 设置最小小费阀值 （1 Gwei）
+	//minTipCap := big.NewInt(int64(Min1Gwei))
+	//if multipliedTip.Cmp(minTipCap) < 0 {
+	//	multipliedTip = minTipCap
+	//}
+
+	// 6. 计算 MaxPriorityFee (baseFee + 小费 * 倍数 * 2）
+	maxPriorityFee := new(big.Int).Mul(
+		multipliedTip,
+		big.NewInt(2),
+	)
+
+	// 加上 baseFee
+	maxPriorityFee.Add(gasPrice, maxPriorityFee)
+
+	return &FeeInfo{
+		GasPrice:       gasPrice,
+		GasTipCap:      gasTipCap,
+		Multiplier:     multiplier,
+		MultipliedTip:  multipliedTip,
+		MaxPriorityFee: maxPriorityFee,
+	}, nil
+
+}
+
+// GetFee 查询链上的手续费并解析
+func (wac *WalletChainAccountClient) GetFee(address string) (*FeeInfo, error) {
+	req := &account.FeeRequest{
+		Chain:   wac.ChainName,
+		Network: wac.Network,
+		Address: address,
+		RawTx:   "",
+	}
+	fee, err := wac.AccountRpcClient.GetFee(wac.Ctx, req)
+	if err != nil {
+		log.Error("get fee GetFee failed", "address", address, "err", err)
+		return nil, err
+	}
+	if fee.Code == common.ReturnCode_ERROR {
+		log.Error("get fee fail", "address", address, "msg", fee.Msg)
+		return nil, fmt.Errorf("get fee fail: %s", fee.Msg)
+	}
+	return ParseFastFee(fee.FastFee)
+}
@@ -0,0 +1,62 @@
+package rpcclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/pkg/errors"
+)
+
+// NodeClient EVM 链节点的 json-rpc 客户端，链账户服务不返回交易回执，也不能按区块查询余额：
+// NFT 转账的 token id 和数量需要从回执的 Transfer 日志中解析，对账需要查询已同步区块上的余额
+type NodeClient struct {
+	Ctx    context.Context
+	client *ethclient.Client
+}
+
+func NewNodeClient(ctx context.Context, rpcUrl string) (*NodeClient, error) {
+	log.Info("New chain node rpc client", "rpcUrl", rpcUrl)
+	client, err := ethclient.DialContext(ctx, rpcUrl)
+	if err != nil {
+		return nil, errors.Wrap(err, "dial chain node fail")
+	}
+	return &NodeClient{Ctx: ctx, client: client}, nil
+}
+
+// TransactionLogs 交易回执中的全部日志，交易不存在或还没有回执时返回错误
+func (nc *NodeClient) TransactionLogs(hash string) ([]*types.Log, error) {
+	receipt, err := nc.client.TransactionReceipt(nc.Ctx, common.HexToHash(hash))
+	if err != nil {
+		log.Error("get transaction receipt fail", "txHash", hash, "err", err)
+		return nil, err
+	}
+	return receipt.Logs, nil
+}
+
+// erc20BalanceOf balanceOf(address) 的函数选择器
+var erc20BalanceOf = common.FromHex("0x70a08231")
+
+// BalanceAtHash 地址在 blockHash 区块上的余额，tokenAddress 为零地址时查询原生币
+func (nc *NodeClient) BalanceAtHash(address common.Address, tokenAddress common.Address, blockHash common.Hash) (*big.Int, error) {
+	if tokenAddress == (common.Address{}) {
+		return nc.client.BalanceAtHash(nc.Ctx, address, blockHash)
+	}
+	data := append(common.CopyBytes(erc20BalanceOf), common.LeftPadBytes(address.Bytes(), 32)...)
+	result, err := nc.client.CallContractAtHash(nc.Ctx, ethereum.CallMsg{To: &tokenAddress, Data: data}, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 32 {
+		return nil, errors.Errorf("invalid balanceOf result length: %d", len(result))
+	}
+	return new(big.Int).SetBytes(result), nil
+}
+
+func (nc *NodeClient) Close() {
+	nc.client.Close()
+}
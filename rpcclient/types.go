@@ -2,8 +2,9 @@ package rpcclient
 
 import (
 	"math/big"
-	
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type BlockHeader struct {
@@ -12,3 +13,32 @@ type BlockHeader struct {
 	Number     *big.Int
 	Timestamp  uint64
 }
+
+// Eip1559DynamicFeeTx 链账户服务构建待签名交易和组装签名交易的参数，按 base64(json) 传递
+type Eip1559DynamicFeeTx struct {
+	ChainId              string `json:"chain_id"`
+	Nonce                uint64 `json:"nonce"`
+	FromAddress          string `json:"from_address"`
+	ToAddress            string `json:"to_address"`
+	GasLimit             uint64 `json:"gas_limit"`
+	MaxFeePerGas         string `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas"`
+
+	// eth/erc20 amount
+	Amount string `json:"amount"`
+	// erc20 erc721 erc1155 contract_address
+	ContractAddress string `json:"contract_address"`
+	// erc20 erc721 erc1155
+	TokenType string `json:"token_type"`
+	// erc721 erc1155 token id
+	TokenId string `json:"token_id"`
+}
+
+// SignedTxHash 由签名后的原始交易计算交易哈希；广播前保存，广播结果不确定的交易上链后也能按哈希匹配
+func SignedTxHash(signedTx string) (common.Hash, error) {
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(common.FromHex(signedTx)); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
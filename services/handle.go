@@ -5,8 +5,6 @@ import (
 	"encoding/base64"
 	"fmt"
 	"math/big"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -18,18 +16,12 @@ import (
 	"github.com/JokingLove/multichain-sync-account/database"
 	"github.com/JokingLove/multichain-sync-account/database/dynamic"
 	"github.com/JokingLove/multichain-sync-account/protobuf/da-wallet-go"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
 	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
 )
 
-const (
-	ChainName = "Ethereum"
-	Network   = "mainnet"
-)
-
 var (
-	EthGasLimit   uint64 = 60000
-	TokenGasLimit uint64 = 120000
-	Min2Gwei      uint64 = 1000000000
+	Min2Gwei uint64 = 1000000000
 )
 
 func (bws *BusinessMiddleWireServices) BusinessRegister(ctx context.Context, request *da_wallet_go.BusinessRegisterRequest) (*da_wallet_go.BusinessRegisterResponse, error) {
@@ -44,6 +36,7 @@ func (bws *BusinessMiddleWireServices) BusinessRegister(ctx context.Context, req
 		GUID:        uuid.New(),
 		BusinessUid: request.RequestId,
 		NotifyUrl:   request.NotifyUrl,
+		Classifier:  request.Classifier,
 		Timestamp:   uint64(time.Now().Unix()),
 	}
 
@@ -70,11 +63,35 @@ func (bws *BusinessMiddleWireServices) ExportAddressesByPublicKeys(ctx context.C
 		balances     []*database.Balances
 	)
 
+	accountClient, err := bws.accountClient(request.Chain)
+	if err != nil {
+		return &da_wallet_go.ExportAddressesResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  err.Error(),
+		}, nil
+	}
+
+	// 导出时的链上余额记为期初分录，生效区块取已同步的最新区块
+	openingBlock := big.NewInt(0)
+	if header, err := bws.db.Blocks.LatestBlocks(accountClient.ChainName); err != nil {
+		log.Warn("query latest synced block fail, record opening balance at block 0", "chain", accountClient.ChainName, "err", err)
+	} else if header != nil {
+		openingBlock = header.Number
+	}
+
 	for _, value := range request.PublicKeys {
-		address := bws.accountClient.ExportAddressByPubKey("", value.PublicKey)
+		address, err := bws.normalizeAddress(accountClient, accountClient.ExportAddressByPubKey("", value.PublicKey))
+		if err != nil {
+			log.Error("export address fail", "publicKey", value.PublicKey, "err", err)
+			return &da_wallet_go.ExportAddressesResponse{
+				Code: da_wallet_go.ReturnCode_ERROR,
+				Msg:  fmt.Sprintf("export address fail: %s", err),
+			}, nil
+		}
 		item := &da_wallet_go.Address{
 			Type:    value.Type,
-			Address: address,
+			Address: address.String(),
+			Memo:    value.Memo,
 		}
 		parseAddressType, err := database.ParseAddressType(value.Type)
 		if err != nil {
@@ -82,43 +99,58 @@ func (bws *BusinessMiddleWireServices) ExportAddressesByPublicKeys(ctx context.C
 			return nil, err
 		}
 
-		_, _, balance := bws.accountClient.GetAccount(address)
+		balance := big.NewInt(0)
+		if accountInfo, err := accountClient.GetAccount(address.String(), "0x00"); err != nil {
+			log.Warn("query address balance fail, init balance with 0", "address", address, "err", err)
+		} else {
+			balance = accountInfo.Balance
+		}
 		dbAddress := &database.Addresses{
 			GUID:        uuid.New(),
-			Address:     common.HexToAddress(address),
+			Chain:       accountClient.ChainName,
+			Address:     address,
 			AddressType: parseAddressType,
 			PublicKey:   value.PublicKey,
+			Memo:        value.Memo,
 			Timestamp:   uint64(time.Now().Unix()),
 		}
 		dbAddresses = append(dbAddresses, dbAddress)
+		retAddresses = append(retAddresses, item)
+
+		// 共享地址的多个 memo 共用同一条余额记录，只在导出不带 memo 的地址时初始化
+		if value.Memo != "" {
+			continue
+		}
 
 		balanceItem := &database.Balances{
 			GUID:         uuid.New(),
-			Address:      common.HexToAddress(address),
+			Address:      address,
 			AddressType:  parseAddressType,
 			TokenAddress: common.Address{},
-			Balance:      big.NewInt(int64(balance)),
+			Balance:      balance,
 			LockBalance:  big.NewInt(0),
 			Timestamp:    uint64(time.Now().Unix()),
+			Chain:        accountClient.ChainName,
+			BlockNumber:  openingBlock,
 		}
 
 		balances = append(balances, balanceItem)
-
-		retAddresses = append(retAddresses, item)
 	}
-	err := bws.db.Addresses.StoreAddresses(request.RequestId, dbAddresses)
+	err = bws.db.Addresses.StoreAddresses(request.RequestId, dbAddresses)
 	if err != nil {
 		return &da_wallet_go.ExportAddressesResponse{
 			Code: da_wallet_go.ReturnCode_ERROR,
 			Msg:  "store address  to db fail",
 		}, nil
 	}
-	err = bws.db.Balances.StoreBalances(request.RequestId, balances)
-	if err != nil {
-		return &da_wallet_go.ExportAddressesResponse{
-			Code: da_wallet_go.ReturnCode_ERROR,
-			Msg:  "store balance  to db fail",
-		}, nil
+	if len(balances) > 0 {
+		err = bws.db.Balances.StoreBalances(request.RequestId, balances)
+		if err != nil {
+			return &da_wallet_go.ExportAddressesResponse{
+				Code: da_wallet_go.ReturnCode_ERROR,
+				Msg:  "store balance  to db fail",
+			}, nil
+		}
 	}
 
 	return &da_wallet_go.ExportAddressesResponse{
@@ -137,6 +169,22 @@ func (bws *BusinessMiddleWireServices) CreateUnSignTransaction(ctx context.Conte
 		return nil, fmt.Errorf("invalid request : %w", err)
 	}
 
+	accountClient, err := bws.accountClient(request.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request chain: %w", err)
+	}
+
+	fromAddress, err := bws.normalizeAddress(accountClient, request.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	toAddress, err := bws.normalizeAddress(accountClient, request.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to address: %w", err)
+	}
+	request.From = fromAddress.String()
+	request.To = toAddress.String()
+
 	// transactionType
 	transactionType, err := database.ParseTransactionType(request.TxType)
 	if err != nil {
@@ -149,14 +197,31 @@ func (bws *BusinessMiddleWireServices) CreateUnSignTransaction(ctx context.Conte
 		return nil, fmt.Errorf("invalid amount value: %s", request.Value)
 	}
 
-	guid := uuid.New()
-
-	nonce, err := bws.getAccountNonce(ctx, request.From)
+	tokenType, err := bws.determineTokenType(request.RequestId, request.ContractAddress)
 	if err != nil {
-		return nil, fmt.Errorf("get account nonce fail: %w", err)
+		return nil, fmt.Errorf("query token type fail: %w", err)
 	}
 
-	feeInfo, err := bws.getFeeInfo(ctx, request.From)
+	// 相同订单号的提现已经创建过时返回原提现，不再重复创建
+	if transactionType == database.TxTypeWithdraw && request.OrderId != "" {
+		withdraw, err := bws.db.Withdraws.QueryWithdrawByOrderId(request.RequestId, request.OrderId)
+		if err != nil {
+			return nil, fmt.Errorf("query withdraw by order id fail: %w", err)
+		}
+		if withdraw != nil {
+			return bws.replayWithdrawOrder(ctx, accountClient, request, withdraw, amountBig, transactionType)
+		}
+	}
+
+	if tokenType.IsNft() {
+		if err := bws.validateNftTransfer(request, accountClient.ChainName, tokenType, amountBig); err != nil {
+			return nil, fmt.Errorf("invalid nft transfer: %w", err)
+		}
+	}
+
+	guid := uuid.New()
+
+	feeInfo, err := bws.getFeeInfo(ctx, accountClient, request.From)
 	if err != nil {
 		return nil, fmt.Errorf("get fee info failed: %w", err)
 	}
@@ -165,16 +230,28 @@ func (bws *BusinessMiddleWireServices) CreateUnSignTransaction(ctx context.Conte
 
 	switch transactionType {
 	case database.TxTypeDeposit:
-		err := bws.StoreDeposits(ctx, request, guid, amountBig, gasLimit, feeInfo, transactionType)
+		err := bws.StoreDeposits(ctx, accountClient.ChainName, request, guid, amountBig, gasLimit, feeInfo, transactionType, tokenType)
 		if err != nil {
 			return nil, fmt.Errorf("store deposits fail: %w", err)
 		}
 		break
 	case database.TxTypeWithdraw:
-	//if err := bws.StoreWithdraw(ctx, request, guid, amountBig, gasLimit, feeInfo, transactionType); err != nil {
-	//
-	//}
-	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot:
+		if err := bws.storeWithdraw(accountClient.ChainName, request, guid, amountBig, gasLimit, feeInfo, transactionType, tokenType); err != nil {
+			// 并发的相同订单号请求先写入时违反唯一约束，按重复请求处理
+			if request.OrderId != "" {
+				withdraw, queryErr := bws.db.Withdraws.QueryWithdrawByOrderId(request.RequestId, request.OrderId)
+				if queryErr == nil && withdraw != nil {
+					return bws.replayWithdrawOrder(ctx, accountClient, request, withdraw, amountBig, transactionType)
+				}
+			}
+			return nil, fmt.Errorf("store withdraw fail: %w", err)
+		}
+		break
+	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot, database.TxTypeGasTopUp:
+		// 先保存内部交易，nonce 预留在它的 guid 上，签名时按同一个 guid 查回
+		if err := bws.storeInternal(accountClient.ChainName, request, guid, amountBig, gasLimit, feeInfo, transactionType, tokenType); err != nil {
+			return nil, fmt.Errorf("store internal fail: %w", err)
+		}
 		break
 	default:
 		response.Msg = "Unsupported transaction type"
@@ -182,9 +259,14 @@ func (bws *BusinessMiddleWireServices) CreateUnSignTransaction(ctx context.Conte
 		return response, nil
 	}
 
-	dynamicFeeTxReq := Eip1559DynamicFeeTx{
+	nonce, err := bws.reserveNonce(ctx, accountClient, fromAddress, guid.String())
+	if err != nil {
+		return nil, fmt.Errorf("reserve nonce fail: %w", err)
+	}
+
+	dynamicFeeTxReq := rpcclient.Eip1559DynamicFeeTx{
 		ChainId:              request.ChainId,
-		Nonce:                uint64(nonce),
+		Nonce:                nonce,
 		FromAddress:          request.From,
 		ToAddress:            request.To,
 		GasLimit:             gasLimit,
@@ -192,23 +274,38 @@ func (bws *BusinessMiddleWireServices) CreateUnSignTransaction(ctx context.Conte
 		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
 		Amount:               request.Value,
 		ContractAddress:      contractAddress,
+		TokenType:            string(tokenType),
+		TokenId:              request.TokenId,
 	}
 
 	data := json2.ToJSON(dynamicFeeTxReq)
 	log.Info("BusinessMiddleWireServices CreateUnSignTransaction dynamicFeeTxReq", json2.ToJSONString(dynamicFeeTxReq))
 	base64Str := base64.StdEncoding.EncodeToString(data)
 	unsignTx := &account.UnSignTransactionRequest{
-		Chain:    ChainName,
-		Network:  Network,
+		Chain:    accountClient.ChainName,
+		Network:  accountClient.Network,
 		Base64Tx: base64Str,
 	}
 	log.Info("BusinessMiddleWireServices CreateUnSignTransaction unsignTx", json2.ToJSONString(unsignTx))
-	returnTx, err := bws.accountClient.AccountRpcClient.CreateUnSignTransaction(ctx, unsignTx)
+	returnTx, err := accountClient.AccountRpcClient.CreateUnSignTransaction(ctx, unsignTx)
 	log.Info("BusinessMiddleWireServices CreateUnSignTransaction returnTx", json2.ToJSONString(returnTx))
 	if err != nil {
 		log.Error("create un sign transaction fail: %w", err)
+		if releaseErr := bws.db.Nonces.ReleaseNonce(accountClient.ChainName, guid.String()); releaseErr != nil {
+			log.Error("release nonce fail", "transactionId", guid, "err", releaseErr)
+		}
 		return nil, fmt.Errorf("create un sign transaction fail: %w", err)
 	}
+	switch transactionType {
+	case database.TxTypeWithdraw:
+		if err := bws.db.Withdraws.UpdateWithdrawUnSignTx(request.RequestId, guid.String(), returnTx.UnSignTx); err != nil {
+			log.Error("store withdraw un sign tx fail", "transactionId", guid, "err", err)
+		}
+	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot, database.TxTypeGasTopUp:
+		if err := bws.db.Internals.UpdateInternalUnSignTx(request.RequestId, guid.String(), returnTx.UnSignTx); err != nil {
+			log.Error("store internal un sign tx fail", "transactionId", guid, "err", err)
+		}
+	}
 
 	response.Code = da_wallet_go.ReturnCode_SUCCESS
 	response.Msg = "submit withdraw and build un sign transaction success"
@@ -222,10 +319,13 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 	}
 	// 1. Get transaction from database based on type
 	var (
-		fromAddress          string
-		toAddress            string
+		chain                string
+		fromAddress          database.Address
+		toAddress            database.Address
 		amount               string
 		tokenAddress         string
+		tokenType            database.TokenType
+		tokenId              string
 		gasLimit             uint64
 		maxFeePerGas         string
 		maxPriorityFeePerGas string
@@ -246,10 +346,13 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 			response.Msg = "Deposit transaction not found"
 			return response, nil
 		}
-		fromAddress = tx.FromAddress.String()
-		toAddress = tx.ToAddress.String()
+		chain = tx.Chain
+		fromAddress = tx.FromAddress
+		toAddress = tx.ToAddress
 		amount = tx.Amount.String()
-		tokenAddress = tx.TokenAddress.String()
+		tokenAddress = contractAddressOf(tx.TokenAddress)
+		tokenType = tx.TokenType
+		tokenId = tx.TokenId
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeeGas
@@ -262,15 +365,18 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 			response.Msg = "Withdraw transaction not found"
 			return response, nil
 		}
-		fromAddress = tx.FromAddress.String()
-		toAddress = tx.ToAddress.String()
+		chain = tx.Chain
+		fromAddress = tx.FromAddress
+		toAddress = tx.ToAddress
 		amount = tx.Amount.String()
-		tokenAddress = tx.TokenAddress.String()
+		tokenAddress = contractAddressOf(tx.TokenAddress)
+		tokenType = tx.TokenType
+		tokenId = tx.TokenId
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeePerGas
 
-	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot:
+	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot, database.TxTypeGasTopUp:
 		tx, err := bws.db.Internals.QueryInternalById(request.RequestId, request.TransactionId)
 		if err != nil {
 			return nil, fmt.Errorf("query internal fail: %w", err)
@@ -279,10 +385,13 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 			response.Msg = "Internal transaction not found"
 			return response, nil
 		}
-		fromAddress = tx.FromAddress.String()
-		toAddress = tx.ToAddress.String()
+		chain = tx.Chain
+		fromAddress = tx.FromAddress
+		toAddress = tx.ToAddress
 		amount = tx.Amount.String()
-		tokenAddress = tx.TokenAddress.String()
+		tokenAddress = contractAddressOf(tx.TokenAddress)
+		tokenType = tx.TokenType
+		tokenId = tx.TokenId
 		gasLimit = tx.GasLimit
 		maxFeePerGas = tx.MaxFeePerGas
 		maxPriorityFeePerGas = tx.MaxPriorityFeePerGas
@@ -293,37 +402,43 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 		return response, nil
 	}
 
-	// 2.Get current nonce
-	nonce, err := bws.getAccountNonce(ctx, fromAddress)
+	// 2.Get the nonce reserved when the unsigned transaction was built
+	accountClient, err := bws.accountClient(chain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction chain: %w", err)
+	}
+	nonce, err := bws.reserveNonce(ctx, accountClient, fromAddress, request.TransactionId)
 	if err != nil {
-		return nil, fmt.Errorf("get account nonce fail: %w", err)
+		return nil, fmt.Errorf("reserve nonce fail: %w", err)
 	}
 
 	// 3. Build EIP-1559 transaction
-	dynamicFeeTx := Eip1559DynamicFeeTx{
+	dynamicFeeTx := rpcclient.Eip1559DynamicFeeTx{
 		ChainId:              request.ChainId,
-		Nonce:                uint64(nonce),
-		FromAddress:          fromAddress,
-		ToAddress:            toAddress,
+		Nonce:                nonce,
+		FromAddress:          fromAddress.String(),
+		ToAddress:            toAddress.String(),
 		GasLimit:             gasLimit,
 		MaxFeePerGas:         maxFeePerGas,
 		MaxPriorityFeePerGas: maxPriorityFeePerGas,
 		Amount:               amount,
 		ContractAddress:      tokenAddress,
+		TokenType:            string(tokenType),
+		TokenId:              tokenId,
 	}
 
 	// 4. Build signed transaction
 	data := json2.ToJSON(dynamicFeeTx)
 	base64Str := base64.StdEncoding.EncodeToString(data)
 	signedTxReq := &account.SignedTransactionRequest{
-		Chain:     ChainName,
-		Network:   Network,
+		Chain:     accountClient.ChainName,
+		Network:   accountClient.Network,
 		Signature: request.Signature,
 		Base64Tx:  base64Str,
 	}
 
 	log.Info("BuildSignedTransaction request ", "dynamicFeeTx", json2.ToJSONString(signedTxReq))
-	returnTx, err := bws.accountClient.AccountRpcClient.BuildSignedTransaction(ctx, signedTxReq)
+	returnTx, err := accountClient.AccountRpcClient.BuildSignedTransaction(ctx, signedTxReq)
 	log.Info("BuildSignedTransaction returnTx", json2.ToJSONString(returnTx))
 	if err != nil {
 		return nil, fmt.Errorf("build signed transaction fail: %w", err)
@@ -336,7 +451,7 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 		updateErr = bws.db.Deposits.UpdateDepositById(request.RequestId, request.TransactionId, returnTx.SignedTx, database.TxStatusSigned)
 	case database.TxTypeWithdraw:
 		updateErr = bws.db.Withdraws.UpdateWithdrawById(request.RequestId, request.TransactionId, returnTx.SignedTx, database.TxStatusSigned)
-	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot:
+	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot, database.TxTypeGasTopUp:
 		updateErr = bws.db.Internals.UpdateInternalById(request.RequestId, request.TransactionId, returnTx.SignedTx, database.TxStatusSigned)
 	default:
 		response.Msg = "unsupported transaction type"
@@ -348,6 +463,21 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 		return nil, fmt.Errorf("update transaction status failed: %w", updateErr)
 	}
 
+	// 6. 保存本地计算的交易哈希，广播结果不确定时同步器也能按哈希匹配上链的交易
+	if txHash, err := rpcclient.SignedTxHash(returnTx.SignedTx); err != nil {
+		log.Warn("compute signed tx hash fail", "transactionId", request.TransactionId, "err", err)
+	} else {
+		switch transactionType {
+		case database.TxTypeWithdraw:
+			updateErr = bws.db.Withdraws.UpdateWithdrawTxHashById(request.RequestId, request.TransactionId, txHash)
+		case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot, database.TxTypeGasTopUp:
+			updateErr = bws.db.Internals.UpdateInternalTxHashById(request.RequestId, request.TransactionId, txHash)
+		}
+		if updateErr != nil {
+			return nil, fmt.Errorf("update transaction hash failed: %w", updateErr)
+		}
+	}
+
 	response.Code = da_wallet_go.ReturnCode_SUCCESS
 	response.Msg = "build signed transaction success"
 	response.SignTx = returnTx.SignedTx
@@ -355,127 +485,488 @@ func (bws *BusinessMiddleWireServices) BuildSignedTransaction(ctx context.Contex
 }
 func (bws *BusinessMiddleWireServices) getGasAndContractInfo(contractAddress string) (uint64, string) {
 	if contractAddress == "0x00" {
-		return EthGasLimit, "0x00"
+		return rpcclient.EthGasLimit, "0x00"
 	}
-	return TokenGasLimit, contractAddress
+	return rpcclient.TokenGasLimit, contractAddress
 }
 
-func (bws *BusinessMiddleWireServices) getAccountNonce(ctx context.Context, address string) (int, error) {
-	accountReq := &account.AccountRequest{
-		Chain:           ChainName,
-		Network:         Network,
-		Address:         address,
-		ContractAddress: "0x00",
-	}
-
-	accountInfo, err := bws.accountClient.AccountRpcClient.GetAccount(ctx, accountReq)
+// getAccountNonce 查询地址当前 nonce，nonce 超出 int 范围时也能正确解析
+func (bws *BusinessMiddleWireServices) getAccountNonce(ctx context.Context, accountClient *rpcclient.WalletChainAccountClient, address string) (uint64, error) {
+	accountInfo, err := accountClient.GetAccount(address, "0x00")
 	if err != nil {
 		return 0, fmt.Errorf("get account info fail: %w", err)
 	}
+	return accountInfo.Sequence, nil
+}
 
-	return strconv.Atoi(accountInfo.Sequence)
+// reserveNonce 以链上 pending nonce 为起点为交易分配 nonce，同一交易构建待签名交易和组装签名交易时使用同一个 nonce
+func (bws *BusinessMiddleWireServices) reserveNonce(ctx context.Context, accountClient *rpcclient.WalletChainAccountClient, address database.Address, transactionId string) (uint64, error) {
+	chainNonce, err := bws.getAccountNonce(ctx, accountClient, address.String())
+	if err != nil {
+		return 0, fmt.Errorf("get account nonce fail: %w", err)
+	}
+	return bws.db.Nonces.ReserveNonce(accountClient.ChainName, address, transactionId, chainNonce)
 }
 
-func (bws *BusinessMiddleWireServices) getFeeInfo(ctx context.Context, address string) (*FeeInfo, error) {
+func (bws *BusinessMiddleWireServices) getFeeInfo(ctx context.Context, accountClient *rpcclient.WalletChainAccountClient, address string) (*rpcclient.FeeInfo, error) {
 	feeReq := &account.FeeRequest{
-		Chain:   ChainName,
-		Network: Network,
+		Chain:   accountClient.ChainName,
+		Network: accountClient.Network,
 		Address: address,
 		RawTx:   "",
 	}
 
-	feeResponse, err := bws.accountClient.AccountRpcClient.GetFee(ctx, feeReq)
+	feeResponse, err := accountClient.AccountRpcClient.GetFee(ctx, feeReq)
 	if err != nil {
 		return nil, fmt.Errorf("get fee info fail: %w", err)
 	}
 
-	return ParseFastFee(feeResponse.FastFee)
+	return rpcclient.ParseFastFee(feeResponse.FastFee)
 }
 
-// ParseFastFee 解析 FastFee 字符串并计算相关费用
-func ParseFastFee(fee string) (*FeeInfo, error) {
-	// 1. 按 “|" 分隔字符串
-	parts := strings.Split(fee, "|")
-	if len(parts) != 3 {
-		return nil, fmt.Errorf("invalid fee format: %s", fee)
+func (bws *BusinessMiddleWireServices) SetTokenAddress(ctx context.Context, request *da_wallet_go.SetTokenAddressRequest) (*da_wallet_go.SetTokenAddressResponse, error) {
+	var (
+		tokenList []database.Tokens
+	)
+	for _, value := range request.TokenList {
+		token, err := buildToken(value)
+		if err != nil {
+			return &da_wallet_go.SetTokenAddressResponse{
+				Code: da_wallet_go.ReturnCode_ERROR,
+				Msg:  err.Error(),
+			}, nil
+		}
+		tokenList = append(tokenList, token)
 	}
-	// 2. 解析 GasPrice （baseFee)
-	gasPrice := new(big.Int)
-	if _, ok := gasPrice.SetString(parts[0], 10); !ok {
-		return nil, fmt.Errorf("invalid gas price: %s", parts[0])
+
+	err := bws.db.Tokens.StoreTokens(request.RequestId, tokenList)
+	if err != nil {
+		log.Error("set token address fail", "err", err)
+		return nil, err
 	}
+	return &da_wallet_go.SetTokenAddressResponse{
+		Code: da_wallet_go.ReturnCode_SUCCESS,
+		Msg:  "set token address success",
+	}, nil
+}
 
-	// 3. 解析  GasTipCap
-	gasTipCap := new(big.Int)
-	if _, ok := gasTipCap.SetString(parts[1], 10); !ok {
-		return nil, fmt.Errorf("invalid gas tip cap: %s", parts[1])
+func (bws *BusinessMiddleWireServices) QuerySuspenseDeposits(ctx context.Context, request *da_wallet_go.SuspenseDepositsRequest) (*da_wallet_go.SuspenseDepositsResponse, error) {
+	if request.RequestId == "" {
+		return &da_wallet_go.SuspenseDepositsResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "invalid params",
+		}, nil
 	}
 
-	// 4. 解析倍数 （去掉 * 前缀）
-	multiplierStr := strings.TrimPrefix(parts[2], "*")
-	multiplier, err := strconv.ParseInt(multiplierStr, 10, 64)
+	deposits, err := bws.db.Deposits.QuerySuspenseDeposits(request.RequestId)
 	if err != nil {
-		return nil, fmt.Errorf("invalid multiplier: %s", parts[2])
+		log.Error("query suspense deposits fail", "err", err)
+		return &da_wallet_go.SuspenseDepositsResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "query suspense deposits fail",
+		}, nil
 	}
 
-	// 5.计算 MultipliedTip (小费 * 倍数）
-	multipliedTip := new(big.Int).Mul(gasTipCap, big.NewInt(multiplier))
+	var suspenseDeposits []*da_wallet_go.SuspenseDeposit
+	for _, deposit := range deposits {
+		suspenseDeposits = append(suspenseDeposits, &da_wallet_go.SuspenseDeposit{
+			TransactionId: deposit.GUID.String(),
+			Chain:         deposit.Chain,
+			TxHash:        deposit.TxHash.String(),
+			BlockNumber:   deposit.BlockNumber.String(),
+			From:          deposit.FromAddress.String(),
+			To:            deposit.ToAddress.String(),
+			TokenAddress:  deposit.TokenAddress.String(),
+			Amount:        deposit.Amount.String(),
+			Memo:          deposit.Memo,
+		})
+	}
+	return &da_wallet_go.SuspenseDepositsResponse{
+		Code:     da_wallet_go.ReturnCode_SUCCESS,
+		Msg:      "query suspense deposits success",
+		Deposits: suspenseDeposits,
+	}, nil
+}
 
-	// 设置最小小费阀值 （1 Gwei）
-	//minTipCap := big.NewInt(int64(Min1Gwei))
-	//if multipliedTip.Cmp(minTipCap) < 0 {
-	//	multipliedTip = minTipCap
-	//}
+// QueryUnsignedInternals 返回归集调度生成、等待业务方签名的内部交易及其待签名数据，
+// 签名后通过 BuildSignedTransaction 提交
+func (bws *BusinessMiddleWireServices) QueryUnsignedInternals(ctx context.Context, request *da_wallet_go.UnsignedInternalsRequest) (*da_wallet_go.UnsignedInternalsResponse, error) {
+	if request.RequestId == "" || request.Chain == "" {
+		return &da_wallet_go.UnsignedInternalsResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "invalid params",
+		}, nil
+	}
+	accountClient, err := bws.accountClient(request.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request chain: %w", err)
+	}
 
-	// 6. 计算 MaxPriorityFee (baseFee + 小费 * 倍数 * 2）
-	maxPriorityFee := new(big.Int).Mul(
-		multipliedTip,
-		big.NewInt(2),
-	)
+	internals, err := bws.db.Internals.QueryUnsignedInternals(request.RequestId, accountClient.ChainName)
+	if err != nil {
+		log.Error("query unsigned internals fail", "err", err)
+		return &da_wallet_go.UnsignedInternalsResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "query unsigned internals fail",
+		}, nil
+	}
+
+	var unsignedInternals []*da_wallet_go.UnsignedInternal
+	for _, internal := range internals {
+		// 单笔构建失败不影响其他内部交易，下次查询时重试
+		unSignTx, err := bws.unsignedInternalTx(ctx, accountClient, request.ChainId, request.RequestId, internal)
+		if err != nil {
+			log.Error("build unsigned internal transaction fail", "transactionId", internal.GUID, "err", err)
+			continue
+		}
+		unsignedInternals = append(unsignedInternals, &da_wallet_go.UnsignedInternal{
+			TransactionId: internal.GUID.String(),
+			TxType:        string(internal.TxType),
+			From:          internal.FromAddress.String(),
+			To:            internal.ToAddress.String(),
+			TokenAddress:  internal.TokenAddress.String(),
+			Amount:        internal.Amount.String(),
+			UnSignTx:      unSignTx,
+		})
+	}
+	return &da_wallet_go.UnsignedInternalsResponse{
+		Code:      da_wallet_go.ReturnCode_SUCCESS,
+		Msg:       "query unsigned internals success",
+		Internals: unsignedInternals,
+	}, nil
+}
+
+// unsignedInternalTx 内部交易的待签名交易构建后保存在内部交易上，之后的查询原样返回；
+// 预留的 nonce 被链上其他交易占用而重新分配时才重新构建
+func (bws *BusinessMiddleWireServices) unsignedInternalTx(ctx context.Context, accountClient *rpcclient.WalletChainAccountClient, chainId string, requestId string, internal *database.Internals) (string, error) {
+	reserved, err := bws.db.Nonces.QueryNonceByTransaction(accountClient.ChainName, internal.GUID.String())
+	if err != nil {
+		return "", fmt.Errorf("query reserved nonce fail: %w", err)
+	}
+	nonce, err := bws.reserveNonce(ctx, accountClient, internal.FromAddress, internal.GUID.String())
+	if err != nil {
+		return "", fmt.Errorf("reserve nonce fail: %w", err)
+	}
+	if internal.UnSignTx != "" && reserved != nil && reserved.Nonce == nonce {
+		return internal.UnSignTx, nil
+	}
 
-	// 加上 baseFee
-	maxPriorityFee.Add(gasPrice, maxPriorityFee)
+	dynamicFeeTx := rpcclient.Eip1559DynamicFeeTx{
+		ChainId:              chainId,
+		Nonce:                nonce,
+		FromAddress:          internal.FromAddress.String(),
+		ToAddress:            internal.ToAddress.String(),
+		GasLimit:             internal.GasLimit,
+		MaxFeePerGas:         internal.MaxFeePerGas,
+		MaxPriorityFeePerGas: internal.MaxPriorityFeePerGas,
+		Amount:               internal.Amount.String(),
+		ContractAddress:      contractAddressOf(internal.TokenAddress),
+		TokenType:            string(internal.TokenType),
+		TokenId:              internal.TokenId,
+	}
+	unSignTx, err := accountClient.CreateUnSignTransaction(&dynamicFeeTx)
+	if err != nil {
+		if releaseErr := bws.db.Nonces.ReleaseNonce(accountClient.ChainName, internal.GUID.String()); releaseErr != nil {
+			log.Error("release nonce fail", "transactionId", internal.GUID, "err", releaseErr)
+		}
+		return "", err
+	}
+	if err := bws.db.Internals.UpdateInternalUnSignTx(requestId, internal.GUID.String(), unSignTx); err != nil {
+		return "", fmt.Errorf("store internal un sign tx fail: %w", err)
+	}
+	return unSignTx, nil
+}
 
-	return &FeeInfo{
-		GasPrice:       gasPrice,
-		GasTipCap:      gasTipCap,
-		Multiplier:     multiplier,
-		MultipliedTip:  multipliedTip,
-		MaxPriorityFee: maxPriorityFee,
+// contractAddressOf 原生币的合约地址为 0x00
+func contractAddressOf(tokenAddress common.Address) string {
+	if tokenAddress == (common.Address{}) {
+		return "0x00"
+	}
+	return tokenAddress.String()
+}
+
+// QueryHistoricalBalance 按区块高度或时间从余额快照中查询地址的历史余额
+func (bws *BusinessMiddleWireServices) QueryHistoricalBalance(ctx context.Context, request *da_wallet_go.HistoricalBalanceRequest) (*da_wallet_go.HistoricalBalanceResponse, error) {
+	if request.RequestId == "" || request.Chain == "" || request.Address == "" || (request.BlockNumber == "" && request.Timestamp == 0) {
+		return &da_wallet_go.HistoricalBalanceResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "invalid params",
+		}, nil
+	}
+	accountClient, err := bws.accountClient(request.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request chain: %w", err)
+	}
+	address, err := bws.normalizeAddress(accountClient, request.Address)
+	if err != nil {
+		return &da_wallet_go.HistoricalBalanceResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  fmt.Sprintf("invalid address: %s", err),
+		}, nil
+	}
+	tokenAddress := common.Address{}
+	if request.TokenAddress != "" && request.TokenAddress != "0x00" {
+		tokenAddress = common.HexToAddress(request.TokenAddress)
+	}
+
+	var balance *database.BalanceSnapshots
+	if request.BlockNumber != "" {
+		number, ok := new(big.Int).SetString(request.BlockNumber, 10)
+		if !ok {
+			return &da_wallet_go.HistoricalBalanceResponse{
+				Code: da_wallet_go.ReturnCode_ERROR,
+				Msg:  "invalid block number",
+			}, nil
+		}
+		balance, err = bws.db.Snapshots.QueryBalanceAtBlock(request.RequestId, accountClient.ChainName, number, address, tokenAddress)
+	} else {
+		balance, err = bws.db.Snapshots.QueryBalanceAtTime(request.RequestId, accountClient.ChainName, request.Timestamp, address, tokenAddress)
+	}
+	if err != nil {
+		log.Error("query historical balance fail", "err", err)
+		return &da_wallet_go.HistoricalBalanceResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "query historical balance fail",
+		}, nil
+	}
+	if balance == nil {
+		return &da_wallet_go.HistoricalBalanceResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "requested block or time not synced",
+		}, nil
+	}
+	return &da_wallet_go.HistoricalBalanceResponse{
+		Code:        da_wallet_go.ReturnCode_SUCCESS,
+		Msg:         "query historical balance success",
+		Balance:     balance.Balance.String(),
+		LockBalance: balance.LockBalance.String(),
+		BlockNumber: balance.BlockNumber.String(),
+		Timestamp:   balance.Timestamp,
 	}, nil
+}
+
+// SignReplacement 业务方对卡住交易的替换交易重新签名后提交签名，组装后的交易由 watchdog 广播
+func (bws *BusinessMiddleWireServices) SignReplacement(ctx context.Context, request *da_wallet_go.SignReplacementRequest) (*da_wallet_go.SignReplacementResponse, error) {
+	if request.RequestId == "" || request.ReplacementId == "" || request.Signature == "" {
+		return &da_wallet_go.SignReplacementResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "invalid params",
+		}, nil
+	}
+	replacement, err := bws.db.Replaces.QueryReplacementById(request.RequestId, request.ReplacementId)
+	if err != nil {
+		return nil, fmt.Errorf("query replacement fail: %w", err)
+	}
+	if replacement == nil || replacement.Status != database.TxStatusCreateUnsigned {
+		return &da_wallet_go.SignReplacementResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "replacement not found or not waiting for signature",
+		}, nil
+	}
+	accountClient, err := bws.accountClient(replacement.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid replacement chain: %w", err)
+	}
 
+	// 与 watchdog 构建待签名交易时的参数一致
+	dynamicFeeTx := rpcclient.Eip1559DynamicFeeTx{
+		ChainId:              replacement.ChainId,
+		Nonce:                replacement.Nonce,
+		FromAddress:          replacement.FromAddress.String(),
+		ToAddress:            replacement.ToAddress.String(),
+		GasLimit:             replacement.GasLimit,
+		MaxFeePerGas:         replacement.MaxFeePerGas,
+		MaxPriorityFeePerGas: replacement.MaxPriorityFeePerGas,
+		Amount:               replacement.Amount.String(),
+		ContractAddress:      contractAddressOf(replacement.TokenAddress),
+		TokenType:            string(replacement.TokenType),
+		TokenId:              replacement.TokenId,
+	}
+	signedTx, err := accountClient.BuildSignedTransaction(&dynamicFeeTx, request.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("build signed transaction fail: %w", err)
+	}
+	if err := bws.db.Replaces.UpdateReplacementSigned(request.RequestId, request.ReplacementId, signedTx); err != nil {
+		return nil, fmt.Errorf("update replacement status failed: %w", err)
+	}
+
+	return &da_wallet_go.SignReplacementResponse{
+		Code:   da_wallet_go.ReturnCode_SUCCESS,
+		Msg:    "build signed replacement transaction success",
+		SignTx: signedTx,
+	}, nil
 }
 
-func (bws *BusinessMiddleWireServices) SetTokenAddress(ctx context.Context, request *da_wallet_go.SetTokenAddressRequest) (*da_wallet_go.SetTokenAddressResponse, error) {
-	var (
-		tokenList []database.Tokens
-	)
-	for _, value := range request.TokenList {
-		collectAmountBigInt, _ := new(big.Int).SetString(value.CollectAmount, 10)
-		coldAmountBigInt, _ := new(big.Int).SetString(value.ColdAmount, 10)
-		token := database.Tokens{
-			GUID:          uuid.New(),
-			TokenAddress:  common.HexToAddress(value.Address),
-			Decimals:      uint8(value.Decimals),
-			TokenName:     value.TokenName,
-			CollectAmount: collectAmountBigInt,
-			ColdAmount:    coldAmountBigInt,
-			TimeStamp:     uint64(time.Now().Unix()),
+// PromoteToken 把代币加入白名单，并重放该代币被隔离的充值：入账、记流水，之后按确认数正常通知
+func (bws *BusinessMiddleWireServices) PromoteToken(ctx context.Context, request *da_wallet_go.PromoteTokenRequest) (*da_wallet_go.PromoteTokenResponse, error) {
+	if request.RequestId == "" || request.Token == nil {
+		return &da_wallet_go.PromoteTokenResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "invalid params",
+		}, nil
+	}
+
+	token, err := buildToken(request.Token)
+	if err != nil {
+		return &da_wallet_go.PromoteTokenResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  err.Error(),
+		}, nil
+	}
+	// 隔离时按同质化代币记录，NFT 的 token id 无法从充值单恢复
+	if token.TokenType.IsNft() {
+		return &da_wallet_go.PromoteTokenResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "nft deposits can not be replayed, register nft token with setTokenAddress",
+		}, nil
+	}
+
+	var replayed int
+	err = bws.db.Transaction(func(tx *database.DB) error {
+		exist, err := tx.Tokens.TokensInfoByAddress(request.RequestId, token.TokenAddress.String())
+		if err != nil {
+			return err
 		}
-		tokenList = append(tokenList, token)
+		if exist == nil {
+			if err := tx.Tokens.StoreTokens(request.RequestId, []database.Tokens{token}); err != nil {
+				return err
+			}
+		}
+
+		deposits, err := tx.Deposits.QueryQuarantineDeposits(request.RequestId, token.TokenAddress)
+		if err != nil {
+			return err
+		}
+		if len(deposits) == 0 {
+			return nil
+		}
+
+		var (
+			balances     []*database.TokenBalance
+			transactions []*database.Transactions
+			credited     []*database.Deposits
+			dust         []*database.Deposits
+		)
+		for _, deposit := range deposits {
+			if token.MinDepositAmount.Sign() > 0 && deposit.Amount.Cmp(token.MinDepositAmount) < 0 {
+				dust = append(dust, deposit)
+				continue
+			}
+			credited = append(credited, deposit)
+			transactionGuid := uuid.New()
+			balances = append(balances, &database.TokenBalance{
+				FromAddress:  deposit.FromAddress,
+				ToAddress:    deposit.ToAddress,
+				TokenAddress: deposit.TokenAddress,
+				Balance:      deposit.Amount,
+				TxType:       database.TxTypeDeposit,
+				SourceGuid:   transactionGuid,
+				Chain:        deposit.Chain,
+				BlockNumber:  deposit.BlockNumber,
+			})
+			fee, ok := new(big.Int).SetString(deposit.MaxFeePerGas, 10)
+			if !ok {
+				fee = big.NewInt(0)
+			}
+			transactions = append(transactions, &database.Transactions{
+				GUID:          transactionGuid,
+				Chain:         deposit.Chain,
+				BlockHash:     deposit.BlockHash,
+				BlockNumber:   deposit.BlockNumber,
+				Hash:          deposit.TxHash,
+				TransferIndex: deposit.TransferIndex,
+				FromAddress:   deposit.FromAddress,
+				ToAddress:     deposit.ToAddress,
+				TokenAddress:  deposit.TokenAddress,
+				TokenId:       deposit.TokenId,
+				TokenMeta:     deposit.TokenMeta,
+				Fee:           fee,
+				Amount:        deposit.Amount,
+				Status:        account.TxStatus_Success,
+				TxType:        database.TxTypeDeposit,
+				Timestamp:     uint64(time.Now().Unix()),
+			})
+		}
+
+		if err := tx.Balances.UpdateOrCreate(request.RequestId, balances); err != nil {
+			return err
+		}
+		if len(transactions) > 0 {
+			if err := tx.Trasactions.StoreTransactions(request.RequestId, transactions, uint64(len(transactions))); err != nil {
+				return err
+			}
+		}
+		if err := tx.Deposits.UpdateDepositsStatusById(request.RequestId, database.TxStatusBoradcasted, credited); err != nil {
+			return err
+		}
+		if err := tx.Deposits.UpdateDepositsStatusById(request.RequestId, database.TxStatusDust, dust); err != nil {
+			return err
+		}
+		replayed = len(credited)
+		return nil
+	})
+	if err != nil {
+		log.Error("promote token fail", "tokenAddress", token.TokenAddress, "err", err)
+		return &da_wallet_go.PromoteTokenResponse{
+			Code: da_wallet_go.ReturnCode_ERROR,
+			Msg:  "promote token fail",
+		}, nil
 	}
 
-	err := bws.db.Tokens.StoreTokens(request.RequestId, tokenList)
+	log.Info("promote token success", "requestId", request.RequestId, "tokenAddress", token.TokenAddress, "replayed", replayed)
+	return &da_wallet_go.PromoteTokenResponse{
+		Code:     da_wallet_go.ReturnCode_SUCCESS,
+		Msg:      "promote token success",
+		Replayed: uint64(replayed),
+	}, nil
+}
+
+func buildToken(value *da_wallet_go.Token) (database.Tokens, error) {
+	tokenType, err := database.ParseTokenType(value.TokenType)
 	if err != nil {
-		log.Error("set token address fail", "err", err)
-		return nil, err
+		return database.Tokens{}, err
 	}
-	return &da_wallet_go.SetTokenAddressResponse{
-		Code: da_wallet_go.ReturnCode_SUCCESS,
-		Msg:  "set token address success",
+	collectAmountBigInt, _ := new(big.Int).SetString(value.CollectAmount, 10)
+	coldAmountBigInt, _ := new(big.Int).SetString(value.ColdAmount, 10)
+	minDepositAmount, err := parseOptionalAmount(value.MinDepositAmount)
+	if err != nil {
+		return database.Tokens{}, fmt.Errorf("invalid min deposit amount: %w", err)
+	}
+	hotFloorAmount, err := parseOptionalAmount(value.HotFloorAmount)
+	if err != nil {
+		return database.Tokens{}, fmt.Errorf("invalid hot floor amount: %w", err)
+	}
+	reconcileTolerance, err := parseOptionalAmount(value.ReconcileTolerance)
+	if err != nil {
+		return database.Tokens{}, fmt.Errorf("invalid reconcile tolerance: %w", err)
+	}
+	return database.Tokens{
+		GUID:               uuid.New(),
+		TokenAddress:       common.HexToAddress(value.Address),
+		Decimals:           uint8(value.Decimals),
+		TokenName:          value.TokenName,
+		TokenType:          tokenType,
+		CollectAmount:      collectAmountBigInt,
+		ColdAmount:         coldAmountBigInt,
+		MinDepositAmount:   minDepositAmount,
+		HotFloorAmount:     hotFloorAmount,
+		ReconcileTolerance: reconcileTolerance,
+		TimeStamp:          uint64(time.Now().Unix()),
 	}, nil
 }
 
+// parseOptionalAmount 未填写时为 0
+func parseOptionalAmount(value string) (*big.Int, error) {
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount: %s", value)
+	}
+	return amount, nil
+}
+
 func validateRequest(request *da_wallet_go.UnSignTransactionRequest) error {
 	if request == nil {
 		return errors.New("request cannot be nil")
@@ -494,29 +985,32 @@ func validateRequest(request *da_wallet_go.UnSignTransactionRequest) error {
 
 func (bws *BusinessMiddleWireServices) StoreDeposits(
 	ctx context.Context,
+	chain string,
 	depositsRequest *da_wallet_go.UnSignTransactionRequest,
 	transactionId uuid.UUID,
 	amountBig *big.Int,
 	gasLimit uint64,
-	feeInfo *FeeInfo,
+	feeInfo *rpcclient.FeeInfo,
 	transactionType database.TransactionType,
+	tokenType database.TokenType,
 ) error {
 	dbDeposit := &database.Deposits{
 		GUID:              transactionId,
 		Timestamp:         uint64(time.Now().Unix()),
 		Status:            database.TxStatusCreateUnsigned,
+		Chain:             chain,
 		Confirms:          0,
 		BlockHash:         common.Hash{},
 		BlockNumber:       big.NewInt(1),
 		TxHash:            common.Hash{},
 		TxType:            transactionType,
-		FromAddress:       common.HexToAddress(depositsRequest.From),
-		ToAddress:         common.HexToAddress(depositsRequest.To),
+		FromAddress:       database.Address(depositsRequest.From),
+		ToAddress:         database.Address(depositsRequest.To),
 		Amount:            amountBig,
 		GasLimit:          gasLimit,
 		MaxFeePerGas:      feeInfo.MaxPriorityFee.String(),
 		MaxPriorityFeeGas: feeInfo.MultipliedTip.String(),
-		TokenType:         determineTokenType(depositsRequest.ContractAddress),
+		TokenType:         tokenType,
 		TokenAddress:      common.HexToAddress(depositsRequest.ContractAddress),
 		TokenId:           depositsRequest.TokenId,
 		TokenMeta:         depositsRequest.TokenMeta,
@@ -526,41 +1020,187 @@ func (bws *BusinessMiddleWireServices) StoreDeposits(
 }
 
 func (bws *BusinessMiddleWireServices) storeWithdraw(
+	chain string,
 	request *da_wallet_go.UnSignTransactionRequest,
 	transactionId uuid.UUID,
 	amountBig *big.Int,
 	gasLimit uint64,
-	feeInfo *FeeInfo,
+	feeInfo *rpcclient.FeeInfo,
 	transactionType database.TransactionType,
+	tokenType database.TokenType,
 ) error {
 
 	withdraw := &database.Withdraws{
 		GUID:                 transactionId,
 		Timestamp:            uint64(time.Now().Unix()),
 		Status:               database.TxStatusCreateUnsigned,
+		Chain:                chain,
 		BlockHash:            common.Hash{},
 		BlockNumber:          big.NewInt(1),
 		TxHash:               common.Hash{},
 		TxType:               transactionType,
-		FromAddress:          common.HexToAddress(request.From),
-		ToAddress:            common.HexToAddress(request.To),
+		FromAddress:          database.Address(request.From),
+		ToAddress:            database.Address(request.To),
 		Amount:               amountBig,
 		GasLimit:             gasLimit,
 		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
 		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
-		TokenType:            determineTokenType(request.ContractAddress),
+		TokenType:            tokenType,
 		TokenAddress:         common.HexToAddress(request.ContractAddress),
 		TokenId:              request.TokenId,
 		TokenMeta:            request.TokenMeta,
+		OrderId:              request.OrderId,
 		TxSignHex:            "",
 	}
 	return bws.db.Withdraws.StoreWithdraw(request.RequestId, withdraw)
 }
 
-func determineTokenType(contractAddress string) database.TokenType {
-	if contractAddress == "0x00" {
-		return database.TokenTypeETH
+func (bws *BusinessMiddleWireServices) storeInternal(
+	chain string,
+	request *da_wallet_go.UnSignTransactionRequest,
+	transactionId uuid.UUID,
+	amountBig *big.Int,
+	gasLimit uint64,
+	feeInfo *rpcclient.FeeInfo,
+	transactionType database.TransactionType,
+	tokenType database.TokenType,
+) error {
+
+	internal := &database.Internals{
+		GUID:                 transactionId,
+		Timestamp:            uint64(time.Now().Unix()),
+		Status:               database.TxStatusCreateUnsigned,
+		Chain:                chain,
+		BlockHash:            common.Hash{},
+		BlockNumber:          big.NewInt(1),
+		TxHash:               common.Hash{},
+		TxType:               transactionType,
+		FromAddress:          database.Address(request.From),
+		ToAddress:            database.Address(request.To),
+		Amount:               amountBig,
+		GasLimit:             gasLimit,
+		MaxFeePerGas:         feeInfo.MaxPriorityFee.String(),
+		MaxPriorityFeePerGas: feeInfo.MultipliedTip.String(),
+		TokenType:            tokenType,
+		TokenAddress:         common.HexToAddress(request.ContractAddress),
+		TokenId:              request.TokenId,
+		TokenMeta:            request.TokenMeta,
+		TxSignHex:            "",
+		BroadcastBlock:       big.NewInt(0),
+	}
+	return bws.db.Internals.StoreInternal(request.RequestId, internal)
+}
+
+// replayWithdrawOrder 相同订单号的重复请求：参数一致时返回原提现的交易 id 和待签名交易，
+// 之前构建待签名交易失败的按原提现的参数重新构建；参数不一致时拒绝
+func (bws *BusinessMiddleWireServices) replayWithdrawOrder(
+	ctx context.Context,
+	accountClient *rpcclient.WalletChainAccountClient,
+	request *da_wallet_go.UnSignTransactionRequest,
+	withdraw *database.Withdraws,
+	amountBig *big.Int,
+	transactionType database.TransactionType,
+) (*da_wallet_go.UnSignTransactionResponse, error) {
+	if !sameWithdrawOrder(withdraw, accountClient.ChainName, request, amountBig, transactionType) {
+		return &da_wallet_go.UnSignTransactionResponse{
+			Code:     da_wallet_go.ReturnCode_ERROR,
+			Msg:      fmt.Sprintf("order id %s already used by transaction %s with different parameters", request.OrderId, withdraw.GUID),
+			UnSignTx: "0x00",
+		}, nil
+	}
+
+	unSignTx := withdraw.UnSignTx
+	if unSignTx == "" {
+		nonce, err := bws.reserveNonce(ctx, accountClient, withdraw.FromAddress, withdraw.GUID.String())
+		if err != nil {
+			return nil, fmt.Errorf("reserve nonce fail: %w", err)
+		}
+		unSignTx, err = accountClient.CreateUnSignTransaction(&rpcclient.Eip1559DynamicFeeTx{
+			ChainId:              request.ChainId,
+			Nonce:                nonce,
+			FromAddress:          withdraw.FromAddress.String(),
+			ToAddress:            withdraw.ToAddress.String(),
+			GasLimit:             withdraw.GasLimit,
+			MaxFeePerGas:         withdraw.MaxFeePerGas,
+			MaxPriorityFeePerGas: withdraw.MaxPriorityFeePerGas,
+			Amount:               withdraw.Amount.String(),
+			ContractAddress:      contractAddressOf(withdraw.TokenAddress),
+			TokenType:            string(withdraw.TokenType),
+			TokenId:              withdraw.TokenId,
+		})
+		if err != nil {
+			if releaseErr := bws.db.Nonces.ReleaseNonce(accountClient.ChainName, withdraw.GUID.String()); releaseErr != nil {
+				log.Error("release nonce fail", "transactionId", withdraw.GUID, "err", releaseErr)
+			}
+			return nil, fmt.Errorf("create un sign transaction fail: %w", err)
+		}
+		if err := bws.db.Withdraws.UpdateWithdrawUnSignTx(request.RequestId, withdraw.GUID.String(), unSignTx); err != nil {
+			log.Error("store withdraw un sign tx fail", "transactionId", withdraw.GUID, "err", err)
+		}
 	}
-	// 这里可以添加更多的 token 类型判断逻辑
-	return database.TokenTypeERC20
+
+	log.Info("withdraw order already created, return original transaction", "orderId", request.OrderId, "transactionId", withdraw.GUID)
+	return &da_wallet_go.UnSignTransactionResponse{
+		Code:          da_wallet_go.ReturnCode_SUCCESS,
+		Msg:           "withdraw of order id already created",
+		TransactionId: withdraw.GUID.String(),
+		UnSignTx:      unSignTx,
+	}, nil
+}
+
+// sameWithdrawOrder 重复请求的链、类型、地址、金额和代币与原提现一致；NFT 的 token id 按十进制比较
+func sameWithdrawOrder(withdraw *database.Withdraws, chain string, request *da_wallet_go.UnSignTransactionRequest, amountBig *big.Int, transactionType database.TransactionType) bool {
+	tokenId := request.TokenId
+	if withdraw.TokenType.IsNft() {
+		if id, ok := new(big.Int).SetString(request.TokenId, 0); ok {
+			tokenId = id.String()
+		}
+	}
+	return withdraw.Chain == chain &&
+		withdraw.TxType == transactionType &&
+		withdraw.FromAddress == database.Address(request.From) &&
+		withdraw.ToAddress == database.Address(request.To) &&
+		withdraw.Amount.Cmp(amountBig) == 0 &&
+		withdraw.TokenAddress == common.HexToAddress(request.ContractAddress) &&
+		withdraw.TokenId == tokenId
+}
+
+// determineTokenType 按业务方注册的代币确定代币类型，未注册的合约按 ERC20 处理
+func (bws *BusinessMiddleWireServices) determineTokenType(requestId string, contractAddress string) (database.TokenType, error) {
+	if contractAddress == "" || contractAddress == "0x00" {
+		return database.TokenTypeETH, nil
+	}
+	token, err := bws.db.Tokens.TokensInfoByAddress(requestId, common.HexToAddress(contractAddress).String())
+	if err != nil {
+		return "", err
+	}
+	if token == nil || token.TokenType == "" {
+		return database.TokenTypeERC20, nil
+	}
+	return token.TokenType, nil
+}
+
+// validateNftTransfer NFT 转账必须指定 token id，ERC721 数量只能为 1，且 from 地址在交易所在链上持有的数量足够；
+// token id 统一转换为十进制，与链上解析记录的格式一致
+func (bws *BusinessMiddleWireServices) validateNftTransfer(request *da_wallet_go.UnSignTransactionRequest, chain string, tokenType database.TokenType, amount *big.Int) error {
+	tokenId, ok := new(big.Int).SetString(request.TokenId, 0)
+	if !ok || tokenId.Sign() < 0 {
+		return fmt.Errorf("invalid token id: %s", request.TokenId)
+	}
+	if amount.Sign() <= 0 {
+		return fmt.Errorf("invalid nft amount: %s", amount)
+	}
+	if tokenType == database.TokenTypeERC721 && amount.Cmp(big.NewInt(1)) != 0 {
+		return fmt.Errorf("erc721 amount must be 1: %s", amount)
+	}
+	request.TokenId = tokenId.String()
+
+	ownership, err := bws.db.Nfts.QueryNftOwnership(request.RequestId, chain, database.Address(request.From), common.HexToAddress(request.ContractAddress), request.TokenId)
+	if err != nil {
+		return fmt.Errorf("query nft ownership fail: %w", err)
+	}
+	if ownership == nil || ownership.Amount.Cmp(amount) < 0 {
+		return fmt.Errorf("insufficient nft %s token id %s of address %s", request.ContractAddress, request.TokenId, request.From)
+	}
+	return nil
 }
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/protobuf/da-wallet-go"
+)
+
+func TestSameWithdrawOrder(t *testing.T) {
+	const (
+		chain    = "Ethereum"
+		from     = "0x1000000000000000000000000000000000000001"
+		to       = "0x2000000000000000000000000000000000000002"
+		contract = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	)
+	withdraw := func(tokenType database.TokenType, tokenId string) *database.Withdraws {
+		return &database.Withdraws{
+			Chain:        chain,
+			TxType:       database.TxTypeWithdraw,
+			FromAddress:  from,
+			ToAddress:    to,
+			Amount:       big.NewInt(100),
+			TokenAddress: common.HexToAddress(contract),
+			TokenType:    tokenType,
+			TokenId:      tokenId,
+		}
+	}
+	request := func(modify func(*da_wallet_go.UnSignTransactionRequest)) *da_wallet_go.UnSignTransactionRequest {
+		request := &da_wallet_go.UnSignTransactionRequest{
+			OrderId:         "order-1",
+			From:            from,
+			To:              to,
+			ContractAddress: contract,
+		}
+		if modify != nil {
+			modify(request)
+		}
+		return request
+	}
+
+	tests := []struct {
+		name     string
+		withdraw *database.Withdraws
+		chain    string
+		request  *da_wallet_go.UnSignTransactionRequest
+		amount   int64
+		txType   database.TransactionType
+		want     bool
+	}{
+		{
+			name:     "same order",
+			withdraw: withdraw(database.TokenTypeERC20, ""),
+			chain:    chain,
+			request:  request(nil),
+			amount:   100,
+			txType:   database.TxTypeWithdraw,
+			want:     true,
+		},
+		{
+			name:     "lower case contract address",
+			withdraw: withdraw(database.TokenTypeERC20, ""),
+			chain:    chain,
+			request: request(func(r *da_wallet_go.UnSignTransactionRequest) {
+				r.ContractAddress = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+			}),
+			amount: 100,
+			txType: database.TxTypeWithdraw,
+			want:   true,
+		},
+		{
+			name:     "different chain",
+			withdraw: withdraw(database.TokenTypeERC20, ""),
+			chain:    "Polygon",
+			request:  request(nil),
+			amount:   100,
+			txType:   database.TxTypeWithdraw,
+			want:     false,
+		},
+		{
+			name:     "different amount",
+			withdraw: withdraw(database.TokenTypeERC20, ""),
+			chain:    chain,
+			request:  request(nil),
+			amount:   101,
+			txType:   database.TxTypeWithdraw,
+			want:     false,
+		},
+		{
+			name:     "different transaction type",
+			withdraw: withdraw(database.TokenTypeERC20, ""),
+			chain:    chain,
+			request:  request(nil),
+			amount:   100,
+			txType:   database.TxTypeHot2Cold,
+			want:     false,
+		},
+		{
+			name:     "different to address",
+			withdraw: withdraw(database.TokenTypeERC20, ""),
+			chain:    chain,
+			request: request(func(r *da_wallet_go.UnSignTransactionRequest) {
+				r.To = "0x3000000000000000000000000000000000000003"
+			}),
+			amount: 100,
+			txType: database.TxTypeWithdraw,
+			want:   false,
+		},
+		{
+			name:     "different from address",
+			withdraw: withdraw(database.TokenTypeERC20, ""),
+			chain:    chain,
+			request: request(func(r *da_wallet_go.UnSignTransactionRequest) {
+				r.From = "0x3000000000000000000000000000000000000003"
+			}),
+			amount: 100,
+			txType: database.TxTypeWithdraw,
+			want:   false,
+		},
+		{
+			name:     "different token",
+			withdraw: withdraw(database.TokenTypeERC20, ""),
+			chain:    chain,
+			request: request(func(r *da_wallet_go.UnSignTransactionRequest) {
+				r.ContractAddress = "0x00"
+			}),
+			amount: 100,
+			txType: database.TxTypeWithdraw,
+			want:   false,
+		},
+		{
+			name:     "nft token id in hex",
+			withdraw: withdraw(database.TokenTypeERC721, "255"),
+			chain:    chain,
+			request: request(func(r *da_wallet_go.UnSignTransactionRequest) {
+				r.TokenId = "0xff"
+			}),
+			amount: 100,
+			txType: database.TxTypeWithdraw,
+			want:   true,
+		},
+		{
+			name:     "nft different token id",
+			withdraw: withdraw(database.TokenTypeERC1155, "255"),
+			chain:    chain,
+			request: request(func(r *da_wallet_go.UnSignTransactionRequest) {
+				r.TokenId = "256"
+			}),
+			amount: 100,
+			txType: database.TxTypeWithdraw,
+			want:   false,
+		},
+		{
+			name:     "fungible token id compared as is",
+			withdraw: withdraw(database.TokenTypeERC20, "255"),
+			chain:    chain,
+			request: request(func(r *da_wallet_go.UnSignTransactionRequest) {
+				r.TokenId = "0xff"
+			}),
+			amount: 100,
+			txType: database.TxTypeWithdraw,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, sameWithdrawOrder(tt.withdraw, tt.chain, tt.request, big.NewInt(tt.amount), tt.txType))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sync/atomic"
@@ -18,26 +19,68 @@ import (
 const MaxRecvMessageSize = 1024 * 1024 * 300
 
 type BusinessMiddleConfig struct {
-	GrpcHostName string
-	GrpcPort     int
+	GrpcHostName   string
+	GrpcPort       int
+	AddressFormats map[string]database.AddressFormat
 }
 
 type BusinessMiddleWireServices struct {
 	*BusinessMiddleConfig
 	da_wallet_go.UnimplementedBusinessMiddleWireServiceServer
-	accountClient *rpcclient.WalletChainAccountClient
-	db            *database.DB
-	stopped       atomic.Bool
+	accountClients map[string]*rpcclient.WalletChainAccountClient
+	defaultChain   string
+	db             *database.DB
+	stopped        atomic.Bool
 }
 
-func NewBusinessMiddleWireServices(db *database.DB, config *BusinessMiddleConfig, accountClient *rpcclient.WalletChainAccountClient) (*BusinessMiddleWireServices, error) {
+// NewBusinessMiddleWireServices accountClients 为每条链一个客户端，请求中没有指定链时使用第一条链
+func NewBusinessMiddleWireServices(db *database.DB, config *BusinessMiddleConfig, accountClients []*rpcclient.WalletChainAccountClient) (*BusinessMiddleWireServices, error) {
+	if len(accountClients) == 0 {
+		return nil, errors.New("no chain account client configured")
+	}
+	clients := make(map[string]*rpcclient.WalletChainAccountClient, len(accountClients))
+	for _, accountClient := range accountClients {
+		clients[accountClient.ChainName] = accountClient
+	}
 	return &BusinessMiddleWireServices{
 		BusinessMiddleConfig: config,
-		accountClient:        accountClient,
+		accountClients:       clients,
+		defaultChain:         accountClients[0].ChainName,
 		db:                   db,
 	}, nil
 }
 
+func (bws *BusinessMiddleWireServices) accountClient(chain string) (*rpcclient.WalletChainAccountClient, error) {
+	if chain == "" {
+		chain = bws.defaultChain
+	}
+	accountClient, ok := bws.accountClients[chain]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chain: %s", chain)
+	}
+	return accountClient, nil
+}
+
+// normalizeAddress 按链的地址格式规范化，并由链账户服务校验地址是否合法
+func (bws *BusinessMiddleWireServices) normalizeAddress(accountClient *rpcclient.WalletChainAccountClient, address string) (database.Address, error) {
+	addressFormat, ok := bws.AddressFormats[accountClient.ChainName]
+	if !ok {
+		addressFormat = database.AddressFormatEVM
+	}
+	normalized := addressFormat.Normalize(address)
+	if normalized == "" {
+		return "", errors.New("address is empty")
+	}
+	valid, err := accountClient.ValidAddress(normalized.String())
+	if err != nil {
+		return "", err
+	}
+	if !valid {
+		return "", fmt.Errorf("invalid %s address: %s", accountClient.ChainName, address)
+	}
+	return normalized, nil
+}
+
 func (bws *BusinessMiddleWireServices) Stop(ctx context.Context) error {
 	bws.stopped.Store(true)
 	return nil
@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/database"
+)
+
+// AddressIndex 一条链上每个业务方的地址集合的内存索引，交易分类时直接查内存，不再逐笔查询数据库。
+// 每个链的同步器各持有一个索引，只加载该链的地址；首次加载全部地址，之后按 seq 增量加载 ExportAddressesByPublicKeys 新生成的地址
+type AddressIndex struct {
+	db    *database.DB
+	chain string
+
+	mu         sync.RWMutex
+	addresses  map[string]map[database.Address]database.AddressType
+	memos      map[string]map[database.Address]map[string]struct{}
+	watermarks map[string]*addressWatermark
+}
+
+// addressSettleWindow seq 在插入时分配、提交时才可见，晚提交的地址 seq 可能小于已加载的最大 seq；
+// 加载到的最大 seq 经过这个时间后才作为下次增量加载的起点，窗口内的地址每次刷新都会重新加载
+const addressSettleWindow = 2 * time.Minute
+
+type addressMark struct {
+	seq uint64
+	at  time.Time
+}
+
+// addressWatermark 增量加载的起点，settled 之前的地址都已提交并加载
+type addressWatermark struct {
+	settled uint64
+	marks   []addressMark
+}
+
+// latest 已加载的最大 seq
+func (w *addressWatermark) latest() uint64 {
+	if n := len(w.marks); n > 0 {
+		return w.marks[n-1].seq
+	}
+	return w.settled
+}
+
+// advance 记录本次加载到的最大 seq，并把超过 addressSettleWindow 的记录推进为 settled
+func (w *addressWatermark) advance(seq uint64, now time.Time) {
+	if seq > w.latest() {
+		w.marks = append(w.marks, addressMark{seq: seq, at: now})
+	}
+	for len(w.marks) > 0 && now.Sub(w.marks[0].at) >= addressSettleWindow {
+		w.settled = w.marks[0].seq
+		w.marks = w.marks[1:]
+	}
+}
+
+func NewAddressIndex(db *database.DB, chain string) *AddressIndex {
+	return &AddressIndex{
+		db:         db,
+		chain:      chain,
+		addresses:  make(map[string]map[database.Address]database.AddressType),
+		memos:      make(map[string]map[database.Address]map[string]struct{}),
+		watermarks: make(map[string]*addressWatermark),
+	}
+}
+
+func (idx *AddressIndex) Refresh(businessId string) error {
+	idx.mu.RLock()
+	var settled uint64
+	if watermark, ok := idx.watermarks[businessId]; ok {
+		settled = watermark.settled
+	}
+	idx.mu.RUnlock()
+
+	addressList, err := idx.db.Addresses.QueryAddressesAfter(businessId, idx.chain, settled)
+	if err != nil {
+		log.Error("load business addresses failed", "chain", idx.chain, "businessId", businessId, "err", err)
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	addresses, ok := idx.addresses[businessId]
+	if !ok {
+		addresses = make(map[database.Address]database.AddressType, len(addressList))
+		idx.addresses[businessId] = addresses
+	}
+	memos, ok := idx.memos[businessId]
+	if !ok {
+		memos = make(map[database.Address]map[string]struct{})
+		idx.memos[businessId] = memos
+	}
+	watermark, ok := idx.watermarks[businessId]
+	if !ok {
+		watermark = &addressWatermark{}
+		idx.watermarks[businessId] = watermark
+	}
+	latest := watermark.latest()
+	var loadedSeq uint64
+	for _, address := range addressList {
+		addresses[address.Address] = address.AddressType
+		if address.Memo != "" {
+			if memos[address.Address] == nil {
+				memos[address.Address] = make(map[string]struct{})
+			}
+			memos[address.Address][address.Memo] = struct{}{}
+		}
+		if address.Seq > loadedSeq {
+			loadedSeq = address.Seq
+		}
+	}
+	watermark.advance(loadedSeq, time.Now())
+
+	if loadedSeq > latest {
+		log.Info("refresh business address index", "chain", idx.chain, "businessId", businessId, "loaded", len(addressList), "total", len(addresses))
+	}
+	return nil
+}
+
+// Lookup address 需要先按所属链的 AddressFormat 规范化
+func (idx *AddressIndex) Lookup(businessId string, address database.Address) (bool, database.AddressType) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	addressType, ok := idx.addresses[businessId][address]
+	if !ok {
+		return false, database.AddressTypeEOA
+	}
+	return true, addressType
+}
+
+// SharedAddress 地址是否分配过 memo，共享地址的充值需要按 memo 归属用户
+func (idx *AddressIndex) SharedAddress(businessId string, address database.Address) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.memos[businessId][address]) > 0
+}
+
+func (idx *AddressIndex) LookupMemo(businessId string, address database.Address, memo string) bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	_, ok := idx.memos[businessId][address][memo]
+	return ok
+}
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressWatermarkSettlesAfterWindow(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	w := &addressWatermark{}
+
+	w.advance(10, start)
+	require.Equal(t, uint64(0), w.settled)
+	require.Equal(t, uint64(10), w.latest())
+
+	// 窗口内 seq 小于 10 的地址晚提交，下次刷新仍从 0 开始加载
+	w.advance(10, start.Add(time.Minute))
+	require.Equal(t, uint64(0), w.settled)
+
+	w.advance(15, start.Add(90*time.Second))
+	w.advance(0, start.Add(addressSettleWindow))
+	require.Equal(t, uint64(10), w.settled)
+	require.Equal(t, uint64(15), w.latest())
+
+	w.advance(0, start.Add(90*time.Second+addressSettleWindow))
+	require.Equal(t, uint64(15), w.settled)
+	require.Empty(t, w.marks)
+
+	// 没有新地址时 settled 不回退
+	w.advance(0, start.Add(time.Hour))
+	require.Equal(t, uint64(15), w.settled)
+}
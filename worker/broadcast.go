@@ -0,0 +1,22 @@
+package worker
+
+import "time"
+
+const (
+	// broadcastRetryMin 第一次广播失败后到重试的等待时间，之后每失败一次翻倍
+	broadcastRetryMin = 10 * time.Second
+	// broadcastRetryMax 广播重试等待时间的上限
+	broadcastRetryMax = 10 * time.Minute
+)
+
+// broadcastBackoff 第 attempts 次广播失败后到下一次重试的等待时间
+func broadcastBackoff(attempts uint64) time.Duration {
+	backoff := broadcastRetryMin
+	for i := uint64(1); i < attempts && backoff < broadcastRetryMax; i++ {
+		backoff *= 2
+	}
+	if backoff > broadcastRetryMax {
+		backoff = broadcastRetryMax
+	}
+	return backoff
+}
@@ -0,0 +1,28 @@
+package worker
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastBackoff(t *testing.T) {
+	tests := []struct {
+		attempts uint64
+		want     time.Duration
+	}{
+		{attempts: 0, want: broadcastRetryMin},
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 2, want: 20 * time.Second},
+		{attempts: 3, want: 40 * time.Second},
+		{attempts: 6, want: 320 * time.Second},
+		{attempts: 7, want: broadcastRetryMax},
+		{attempts: 100, want: broadcastRetryMax},
+		{attempts: math.MaxUint64, want: broadcastRetryMax},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, broadcastBackoff(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+const (
+	ClassifierDefault          = "default"
+	ClassifierStrict           = "strict"
+	ClassifierInternalTransfer = "internal_transfer"
+)
+
+// AddressRole 交易一端的地址在业务方地址库中的角色，Exists 为 false 表示外部地址
+type AddressRole struct {
+	Exists bool
+	Type   database.AddressType
+}
+
+// Classifier 根据交易双方的地址角色判断交易类型，返回 false 表示该交易不需要转发给业务方
+type Classifier interface {
+	Classify(from, to AddressRole, tx *account.BlockInfoTransactionList) (database.TransactionType, bool)
+}
+
+// RuleClassifier 默认的分类规则：
+//   - 外部地址 -> 用户地址：充值
+//   - 热钱包 -> 外部地址：提现
+//   - 用户地址 -> 热钱包：归集
+//   - 热钱包 -> 冷钱包：热转冷
+//   - 冷钱包 -> 热钱包：冷转热
+//   - 热钱包 -> 用户地址：补充手续费
+//
+// EOATransferAsInternal 把用户地址之间的转账记为内部转账，DropUnknown 丢弃无法识别的交易
+type RuleClassifier struct {
+	EOATransferAsInternal bool
+	DropUnknown           bool
+}
+
+func (c RuleClassifier) Classify(from, to AddressRole, tx *account.BlockInfoTransactionList) (database.TransactionType, bool) {
+	txType := database.TxTypeUnknown
+	switch {
+	case !from.Exists && to.Exists && to.Type == database.AddressTypeEOA: // 充值
+		txType = database.TxTypeDeposit
+	case from.Exists && from.Type == database.AddressTypeHot && !to.Exists: // 提现
+		txType = database.TxTypeWithdraw
+	case from.Exists && from.Type == database.AddressTypeEOA && to.Exists && to.Type == database.AddressTypeHot: // 归集
+		txType = database.TxTypeCollection
+	case from.Exists && from.Type == database.AddressTypeHot && to.Exists && to.Type == database.AddressTypeCold: // 热转冷
+		txType = database.TxTypeHot2Cold
+	case from.Exists && from.Type == database.AddressTypeCold && to.Exists && to.Type == database.AddressTypeHot: // 冷转热
+		txType = database.TxTypeCold2Hot
+	case from.Exists && from.Type == database.AddressTypeHot && to.Exists && to.Type == database.AddressTypeEOA: // 补充手续费
+		txType = database.TxTypeGasTopUp
+	case c.EOATransferAsInternal && from.Exists && from.Type == database.AddressTypeEOA && to.Exists && to.Type == database.AddressTypeEOA: // 用户间转账
+		txType = database.TxTypeInternalTransfer
+	}
+
+	if txType == database.TxTypeUnknown && c.DropUnknown {
+		return txType, false
+	}
+	return txType, true
+}
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   = map[string]Classifier{
+		ClassifierDefault:          RuleClassifier{},
+		ClassifierStrict:           RuleClassifier{DropUnknown: true},
+		ClassifierInternalTransfer: RuleClassifier{EOATransferAsInternal: true, DropUnknown: true},
+	}
+)
+
+// RegisterClassifier 注册自定义的分类规则，业务方注册时通过名称选择
+func RegisterClassifier(name string, classifier Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers[name] = classifier
+}
+
+// ClassifierByName 返回业务方选择的分类规则，未配置或未注册时使用默认规则
+func ClassifierByName(name string) Classifier {
+	classifiersMu.RLock()
+	defer classifiersMu.RUnlock()
+	if name == "" {
+		return classifiers[ClassifierDefault]
+	}
+	classifier, ok := classifiers[name]
+	if !ok {
+		log.Warn("unknown transaction classifier, use default", "classifier", name)
+		return classifiers[ClassifierDefault]
+	}
+	return classifier
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/JokingLove/multichain-sync-account/database"
+)
+
+func TestRuleClassifierClassify(t *testing.T) {
+	var (
+		external = AddressRole{}
+		user     = AddressRole{Exists: true, Type: database.AddressTypeEOA}
+		hot      = AddressRole{Exists: true, Type: database.AddressTypeHot}
+		cold     = AddressRole{Exists: true, Type: database.AddressTypeCold}
+	)
+	tests := []struct {
+		name       string
+		classifier RuleClassifier
+		from, to   AddressRole
+		txType     database.TransactionType
+		forward    bool
+	}{
+		{name: "deposit", from: external, to: user, txType: database.TxTypeDeposit, forward: true},
+		{name: "withdraw", from: hot, to: external, txType: database.TxTypeWithdraw, forward: true},
+		{name: "collection", from: user, to: hot, txType: database.TxTypeCollection, forward: true},
+		{name: "hot to cold", from: hot, to: cold, txType: database.TxTypeHot2Cold, forward: true},
+		{name: "cold to hot", from: cold, to: hot, txType: database.TxTypeCold2Hot, forward: true},
+		{name: "gas top up", from: hot, to: user, txType: database.TxTypeGasTopUp, forward: true},
+		{name: "external to hot is unknown", from: external, to: hot, txType: database.TxTypeUnknown, forward: true},
+		{name: "user to external is unknown", from: user, to: external, txType: database.TxTypeUnknown, forward: true},
+		{name: "user to user is unknown by default", from: user, to: user, txType: database.TxTypeUnknown, forward: true},
+		{
+			name:       "user to user as internal transfer",
+			classifier: RuleClassifier{EOATransferAsInternal: true},
+			from:       user, to: user,
+			txType:  database.TxTypeInternalTransfer,
+			forward: true,
+		},
+		{
+			name:       "drop unknown",
+			classifier: RuleClassifier{DropUnknown: true},
+			from:       user, to: external,
+			txType:  database.TxTypeUnknown,
+			forward: false,
+		},
+		{
+			name:       "drop unknown keeps known types",
+			classifier: RuleClassifier{DropUnknown: true},
+			from:       external, to: user,
+			txType:  database.TxTypeDeposit,
+			forward: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txType, forward := tt.classifier.Classify(tt.from, tt.to, nil)
+			require.Equal(t, tt.txType, txType)
+			require.Equal(t, tt.forward, forward)
+		})
+	}
+}
+
+func TestClassifierByName(t *testing.T) {
+	require.Equal(t, RuleClassifier{}, ClassifierByName(""))
+	require.Equal(t, RuleClassifier{DropUnknown: true}, ClassifierByName(ClassifierStrict))
+	require.Equal(t, RuleClassifier{}, ClassifierByName("missing"))
+}
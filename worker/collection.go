@@ -0,0 +1,218 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/JokingLove/multichain-sync-account/common/retry"
+	"github.com/JokingLove/multichain-sync-account/common/tasks"
+	"github.com/JokingLove/multichain-sync-account/config"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+)
+
+// Collection 定时扫描用户地址余额，达到代币 CollectAmount 的地址生成归集到热钱包的交易；
+// ERC20 归集时用户地址的原生币不够手续费，先由热钱包补充手续费。
+// 生成的交易状态为 create_unsigned，由业务方通过接口取出签名后交给 Internal 发送
+type Collection struct {
+	rpcClient      *rpcclient.WalletChainAccountClient
+	db             *database.DB
+	utxo           bool
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+}
+
+func NewCollection(cfg *config.ChainNodeConfig, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Collection, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Collection{
+		rpcClient:      rpcClient,
+		db:             db,
+		utxo:           cfg.Utxo,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in %s collection: %w", rpcClient.ChainName, err))
+		}},
+		ticker: time.NewTicker(cfg.CollectInterval),
+	}, nil
+}
+
+func (c *Collection) Close() error {
+	var result error
+	c.resourceCancel()
+	c.ticker.Stop()
+	log.Info("stop collection......", "chain", c.rpcClient.ChainName)
+	if err := c.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await collection: %w", err))
+		return result
+	}
+	log.Info("stop collection success", "chain", c.rpcClient.ChainName)
+	return nil
+}
+
+func (c *Collection) Start() error {
+	// UTXO 链没有账户余额归集
+	if c.utxo {
+		log.Info("skip collection for utxo chain", "chain", c.rpcClient.ChainName)
+		return nil
+	}
+	log.Info("starting collection...", "chain", c.rpcClient.ChainName)
+	c.tasks.Go(func() error {
+		for {
+			select {
+			case <-c.ticker.C:
+				businessList, err := c.db.Business.QueryBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				for _, business := range businessList {
+					if err := c.collect(business.BusinessUid); err != nil {
+						log.Error("create collection fail", "business", business.BusinessUid, "err", err)
+					}
+				}
+			case <-c.resourceCtx.Done():
+				log.Info("stop collection in worker", "chain", c.rpcClient.ChainName)
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// collect 为一个业务方生成本链的归集和补充手续费交易，余额、热钱包和未上链的内部交易都按链查询，
+// 多条链的归集任务不会重复归集同一笔余额；已有未上链的归集或补充手续费的地址本轮跳过
+func (c *Collection) collect(businessId string) error {
+	hotWallet, err := c.db.Addresses.QueryHotWalletInfo(businessId, c.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	if hotWallet == nil {
+		log.Debug("business has no hot wallet, skip collection", "business", businessId)
+		return nil
+	}
+
+	tokens, err := c.db.Tokens.QueryTokenList(businessId)
+	if err != nil {
+		return err
+	}
+
+	pendingInternals, err := c.db.Internals.QueryPendingInternals(businessId, c.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	pending := make(map[string]struct{}, len(pendingInternals))
+	for _, internal := range pendingInternals {
+		pending[collectKey(internal.TxType, internal.FromAddress, internal.ToAddress, internal.TokenAddress)] = struct{}{}
+	}
+
+	var fee *rpcclient.FeeInfo
+	var internals []*database.Internals
+	for _, token := range tokens {
+		if token.CollectAmount == nil || token.CollectAmount.Sign() <= 0 || token.TokenType.IsNft() {
+			continue
+		}
+		balances, err := c.db.Balances.QueryCollectBalances(businessId, c.rpcClient.ChainName, token.TokenAddress, token.CollectAmount)
+		if err != nil {
+			return err
+		}
+		if len(balances) == 0 {
+			continue
+		}
+		if fee == nil {
+			if fee, err = c.rpcClient.GetFee(hotWallet.Address.String()); err != nil {
+				return err
+			}
+		}
+
+		native := token.TokenAddress == (common.Address{})
+		for _, balance := range balances {
+			if _, ok := pending[collectKey(database.TxTypeCollection, balance.Address, hotWallet.Address, token.TokenAddress)]; ok {
+				continue
+			}
+
+			if native {
+				// 原生币归集从归集金额中扣除手续费
+				amount := new(big.Int).Sub(balance.Balance, gasCost(rpcclient.EthGasLimit, fee))
+				if amount.Sign() <= 0 {
+					continue
+				}
+				internals = append(internals, newInternal(c.rpcClient.ChainName, database.TxTypeCollection, balance.Address, hotWallet.Address, token, amount, rpcclient.EthGasLimit, fee))
+				continue
+			}
+
+			tokenGas := gasCost(rpcclient.TokenGasLimit, fee)
+			nativeBalance, err := c.db.Balances.QueryBalance(businessId, c.rpcClient.ChainName, balance.Address, common.Address{})
+			if err != nil {
+				return err
+			}
+			if nativeBalance.Balance.Cmp(tokenGas) < 0 {
+				if _, ok := pending[collectKey(database.TxTypeGasTopUp, hotWallet.Address, balance.Address, common.Address{})]; ok {
+					continue
+				}
+				topUp := new(big.Int).Sub(tokenGas, nativeBalance.Balance)
+				log.Info("top up gas before collection", "business", businessId, "address", balance.Address, "token", token.TokenAddress, "amount", topUp)
+				internals = append(internals, newInternal(c.rpcClient.ChainName, database.TxTypeGasTopUp, hotWallet.Address, balance.Address, nil, topUp, rpcclient.EthGasLimit, fee))
+				pending[collectKey(database.TxTypeGasTopUp, hotWallet.Address, balance.Address, common.Address{})] = struct{}{}
+				continue
+			}
+			internals = append(internals, newInternal(c.rpcClient.ChainName, database.TxTypeCollection, balance.Address, hotWallet.Address, token, balance.Balance, rpcclient.TokenGasLimit, fee))
+		}
+	}
+
+	if len(internals) == 0 {
+		return nil
+	}
+	log.Info("create collection internals", "business", businessId, "count", len(internals))
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	_, err = retry.Do[interface{}](c.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		return nil, c.db.Internals.StoreInternals(businessId, internals)
+	})
+	return err
+}
+
+// newInternal 生成待签名的内部交易，token 为 nil 时是原生币转账
+func newInternal(chain string, txType database.TransactionType, from, to database.Address, token *database.Tokens, amount *big.Int, gasLimit uint64, fee *rpcclient.FeeInfo) *database.Internals {
+	internal := &database.Internals{
+		GUID:                 uuid.New(),
+		Timestamp:            uint64(time.Now().Unix()),
+		Status:               database.TxStatusCreateUnsigned,
+		Chain:                chain,
+		BlockHash:            common.Hash{},
+		BlockNumber:          big.NewInt(1),
+		TxHash:               common.Hash{},
+		TxType:               txType,
+		FromAddress:          from,
+		ToAddress:            to,
+		Amount:               amount,
+		GasLimit:             gasLimit,
+		MaxFeePerGas:         fee.MaxPriorityFee.String(),
+		MaxPriorityFeePerGas: fee.MultipliedTip.String(),
+		TokenType:            database.TokenTypeETH,
+		TokenAddress:         common.Address{},
+		TokenId:              "0x00",
+		TokenMeta:            "0x00",
+	}
+	if token != nil && token.TokenAddress != (common.Address{}) {
+		internal.TokenType = database.TokenTypeERC20
+		internal.TokenAddress = token.TokenAddress
+	}
+	return internal
+}
+
+func gasCost(gasLimit uint64, fee *rpcclient.FeeInfo) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), fee.MaxPriorityFee)
+}
+
+func collectKey(txType database.TransactionType, from, to database.Address, tokenAddress common.Address) string {
+	return fmt.Sprintf("%s|%s|%s|%s", txType, from, to, tokenAddress)
+}
@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/common/retry"
+	"github.com/JokingLove/multichain-sync-account/common/tasks"
+	"github.com/JokingLove/multichain-sync-account/config"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+)
+
+// ConfirmationTracker 每同步到新的区块就刷新所有业务方待确认充值的确认数，
+// 不依赖新区块里是否有该业务方的充值；确认数变化由通知服务按 notified_confirms 推送给业务方
+type ConfirmationTracker struct {
+	rpcClient      *rpcclient.WalletChainAccountClient
+	db             *database.DB
+	confirms       uint64
+	lastHead       uint64
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+}
+
+func NewConfirmationTracker(cfg *config.ChainNodeConfig, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*ConfirmationTracker, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &ConfirmationTracker{
+		rpcClient:      rpcClient,
+		db:             db,
+		confirms:       uint64(cfg.Confirmations),
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in %s confirmation tracker: %w", rpcClient.ChainName, err))
+		}},
+		ticker: time.NewTicker(cfg.SynchronizerInterval),
+	}, nil
+}
+
+func (ct *ConfirmationTracker) Close() error {
+	var result error
+	ct.resourceCancel()
+	ct.ticker.Stop()
+	log.Info("stop confirmation tracker ......", "chain", ct.rpcClient.ChainName)
+	if err := ct.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await confirmation tracker: %w", err))
+		return result
+	}
+	log.Info("stop confirmation tracker successfully", "chain", ct.rpcClient.ChainName)
+	return nil
+}
+
+func (ct *ConfirmationTracker) Start() error {
+	log.Info("start confirmation tracker ......", "chain", ct.rpcClient.ChainName)
+	ct.tasks.Go(func() error {
+		for {
+			select {
+			case <-ct.ticker.C:
+				if err := ct.onNewHead(); err != nil {
+					log.Error("update deposit confirms fail", "chain", ct.rpcClient.ChainName, "err", err)
+				}
+			case <-ct.resourceCtx.Done():
+				log.Info("stop confirmation tracker in worker", "chain", ct.rpcClient.ChainName)
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// onNewHead 以已落库的最新区块作为链头，链头没有前进时不做处理
+func (ct *ConfirmationTracker) onNewHead() error {
+	head, err := ct.db.Blocks.LatestBlocks(ct.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	if head == nil || head.Number.Uint64() == ct.lastHead {
+		return nil
+	}
+
+	businessList, err := ct.db.Business.QueryBusinessList()
+	if err != nil {
+		log.Error("query business list failed", "err", err)
+		return err
+	}
+
+	headNumber := head.Number.Uint64()
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	if _, err := retry.Do[interface{}](ct.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		if err := ct.db.Transaction(func(tx *database.DB) error {
+			for _, business := range businessList {
+				if err := tx.Deposits.UpdateDepositsConfirms(business.BusinessUid, ct.rpcClient.ChainName, headNumber, ct.confirms); err != nil {
+					log.Error("update deposits confirms fail", "businessId", business.BusinessUid, "err", err)
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}); err != nil {
+		return err
+	}
+
+	log.Info("update deposit confirms success", "chain", ct.rpcClient.ChainName, "head", headNumber, "businesses", len(businessList))
+	ct.lastHead = headNumber
+	return nil
+}
@@ -22,15 +22,23 @@ import (
 type Deposit struct {
 	BaseSynchronizer
 
-	confirms       uint8
+	// node 读取交易回执，没有配置节点时为 nil，NFT 转账只能从 calldata 解析
+	node *rpcclient.NodeClient
+
 	latestHeader   rpcclient.BlockHeader
 	resourceCtx    context.Context
 	resourceCancel context.CancelFunc
 	tasks          tasks.Group
 }
 
-func NewDeposit(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Deposit, error) {
-	dbLatestBlockHeader, err := db.Blocks.LatestBlocks()
+func NewDeposit(cfg *config.ChainNodeConfig, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Deposit, error) {
+	addressFormat, err := database.ParseAddressFormat(cfg.AddressFormat)
+	if err != nil {
+		log.Error("parse address format fail", "chain", cfg.ChainName, "err", err)
+		return nil, err
+	}
+
+	dbLatestBlockHeader, err := db.Blocks.LatestBlocks(rpcClient.ChainName)
 	if err != nil {
 		log.Error("get latest block from database fail")
 		return nil, err
@@ -38,15 +46,14 @@ func NewDeposit(cfg *config.Config, db *database.DB, rpcClient *rpcclient.Wallet
 	var fromHeader *rpcclient.BlockHeader
 
 	if dbLatestBlockHeader != nil {
-		log.Info("sync block", "number", dbLatestBlockHeader.Number, "hash", dbLatestBlockHeader.Hash)
+		log.Info("sync block", "chain", rpcClient.ChainName, "number", dbLatestBlockHeader.Number, "hash", dbLatestBlockHeader.Hash)
 		fromHeader = dbLatestBlockHeader
-	} else if cfg.ChainNode.StartingHeight > 0 {
-		chainLatestBlockHeader, err := rpcClient.GetBlockHeader(big.NewInt(int64(cfg.ChainNode.StartingHeight)))
-		if err != nil {
-			log.Error("get block from chain account fail", "err", err)
-			return nil, err
+	} else if cfg.StartingHeight > 0 {
+		// 只需要高度作为游标，下一批从 StartingHeight 开始同步
+		log.Info("sync block from starting height", "chain", rpcClient.ChainName, "number", cfg.StartingHeight)
+		fromHeader = &rpcclient.BlockHeader{
+			Number: big.NewInt(int64(cfg.StartingHeight) - 1),
 		}
-		fromHeader = chainLatestBlockHeader
 	} else {
 		chainLatestBlockHeader, err := rpcClient.GetBlockHeader(nil)
 		if err != nil {
@@ -56,27 +63,40 @@ func NewDeposit(cfg *config.Config, db *database.DB, rpcClient *rpcclient.Wallet
 		fromHeader = chainLatestBlockHeader
 	}
 
-	businessTxChannel := make(chan map[string]*TransactionChannel)
+	businessTxChannel := make(chan *BusinessBatch)
 
 	baseSyncer := BaseSynchronizer{
-		loopInterval:     cfg.ChainNode.SynchronizerInterval,
-		headerBufferSize: cfg.ChainNode.BlocksStep,
+		loopInterval:     cfg.SynchronizerInterval,
+		headerBufferSize: cfg.BlocksStep,
+		fetchWorkers:     cfg.BlockFetchWorkers,
+		utxo:             cfg.Utxo,
+		addressFormat:    addressFormat,
 		businessChannels: businessTxChannel,
 		rpcClient:        rpcClient,
-		blockBatch:       rpcclient.NewBatchBlock(rpcClient, fromHeader, big.NewInt(int64(cfg.ChainNode.BlocksStep))),
+		blockBatch:       rpcclient.NewBatchBlock(rpcClient, fromHeader, big.NewInt(int64(cfg.BlocksStep))),
 		database:         db,
+		addresses:        NewAddressIndex(db, rpcClient.ChainName),
+	}
+
+	var node *rpcclient.NodeClient
+	if !cfg.Utxo && cfg.RpcUrl != "" {
+		node, err = rpcclient.NewNodeClient(context.Background(), cfg.RpcUrl)
+		if err != nil {
+			log.Error("new chain node client fail", "chain", cfg.ChainName, "err", err)
+			return nil, err
+		}
 	}
 
 	resCtx, resCancel := context.WithCancel(context.Background())
 
 	return &Deposit{
 		BaseSynchronizer: baseSyncer,
-		confirms:         uint8(cfg.ChainNode.Confirmations),
+		node:             node,
 		resourceCtx:      resCtx,
 		resourceCancel:   resCancel,
 		tasks: tasks.Group{
 			HandleCrit: func(err error) {
-				shutdown(fmt.Errorf("critical error in deposit: %w", err))
+				shutdown(fmt.Errorf("critical error in %s deposit: %w", rpcClient.ChainName, err))
 			},
 		},
 	}, nil
@@ -91,18 +111,21 @@ func (d *Deposit) Close() error {
 	if err := d.tasks.Wait(); err != nil {
 		result = errors.Join(result, fmt.Errorf("failed to wait deposit batch handler completion: %w", err))
 	}
+	if d.node != nil {
+		d.node.Close()
+	}
 	return result
 }
 
 func (d *Deposit) Start() error {
-	log.Info("deposit starting")
+	log.Info("deposit starting", "chain", d.rpcClient.ChainName)
 	if err := d.BaseSynchronizer.Start(); err != nil {
 		return fmt.Errorf("failed to start deposit database: %w", err)
 	}
 	d.tasks.Go(func() error {
 		log.Info("handle deposit task start")
 		for batch := range d.businessChannels {
-			log.Info("deposit business channel", "batch length", len(batch))
+			log.Info("deposit business channel", "blocks", len(batch.Blocks), "batch length", len(batch.BusinessChannels))
 			if err := d.handleBatch(batch); err != nil {
 				log.Info("failed to handle deposit batch, stopping L2 synchronizer", "err", err)
 				return fmt.Errorf("failed to handle batch, stopping L2 Synchronizer: %w", err)
@@ -113,182 +136,485 @@ func (d *Deposit) Start() error {
 	return nil
 }
 
-func (d *Deposit) handleBatch(batch map[string]*TransactionChannel) error {
+type businessFlow struct {
+	blockHeight         uint64
+	transactionFlowList []*database.Transactions
+	depositList         []*database.Deposits
+	withdrawList        []*database.Withdraws
+	internals           []*database.Internals
+	balances            []*database.TokenBalance
+	nftTransfers        []*database.NftTransfer
+
+	// 链上执行失败的提现和内部交易，需要释放发送时锁定的余额
+	failedWithdraws []*database.Withdraws
+	failedInternals []*database.Internals
+}
+
+func (d *Deposit) handleBatch(batch *BusinessBatch) error {
 	businessList, err := d.database.Business.QueryBusinessList()
 	if err != nil {
 		log.Error("query business list fail", "err", err)
 		return err
 	}
 
-	if businessList == nil || len(businessList) <= 0 {
-		err := fmt.Errorf("QueryBusinessList businessList is nil")
-		return err
-	}
-
+	flows := make(map[string]*businessFlow)
 	for _, business := range businessList {
-		_, exists := batch[business.BusinessUid]
+		channel, exists := batch.BusinessChannels[business.BusinessUid]
 		if !exists {
 			continue
 		}
 
-		var (
-			transactionFlowList []*database.Transactions
-			depositList         []*database.Deposits
-			withdrawList        []*database.Withdraws
-			internals           []*database.Internals
-			balances            []*database.TokenBalance
-		)
+		flow, err := d.buildBusinessFlow(business.BusinessUid, channel)
+		if err != nil {
+			return err
+		}
+		flows[business.BusinessUid] = flow
+	}
+
+	// 区块头和所有业务方的流水在同一个事务中提交，保证重启后从最新区块继续同步时不会漏块或重复入账
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	if _, err := retry.Do[interface{}](d.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		if err := d.database.Transaction(func(tx *database.DB) error {
+			for businessId, flow := range flows {
+				if err := d.storeBusinessFlow(tx, businessId, flow); err != nil {
+					return err
+				}
+			}
 
-		log.Info("handle business flow",
-			"businessId", business.BusinessUid,
-			"chainLatestBlock", batch[business.BusinessUid].BlockHeight,
-			"txn", len(batch[business.BusinessUid].Transactions))
-		for _, tx := range batch[business.BusinessUid].Transactions {
+			if len(batch.Blocks) > 0 {
+				log.Info("Store block headers", "totalBlockHeader", len(batch.Blocks))
+				if err := tx.Blocks.StoreBlocks(batch.Blocks); err != nil {
+					log.Error("store block headers fail", "err", err)
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			log.Error("unable to persist batch", "err", err)
+			return nil, err
+		}
+		return nil, nil
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *Deposit) buildBusinessFlow(businessId string, channel *TransactionChannel) (*businessFlow, error) {
+	flow := &businessFlow{blockHeight: channel.BlockHeight}
+	tokens := make(map[string]*database.Tokens)
+	receipts := make(map[string]*nftReceipt)
+
+	log.Info("handle business flow",
+		"businessId", businessId,
+		"chainLatestBlock", channel.BlockHeight,
+		"txn", len(channel.Transactions))
+	for _, tx := range channel.Transactions {
+		txItem := tx.TxMsg
+		if txItem == nil {
 			log.Info("Request transaction from chain account", "txHash", tx.Hash, "fromAddress", tx.FromAddress)
-			txItem, err := d.rpcClient.GetTransactionByHash(tx.Hash)
+			var err error
+			txItem, err = d.rpcClient.GetTransactionByHash(tx.Hash)
 			if err != nil {
 				log.Info("get transaction by hash fail", "err", err)
-				return err
+				return nil, err
 			}
 
 			if txItem == nil {
 				err := fmt.Errorf("GetTransactionByHash txItem is nil ; txHash :  %s", tx.Hash)
-				return err
+				return nil, err
 			}
+		}
+
+		token, err := d.tokenInfo(businessId, tx.TokenAddress, tokens)
+		if err != nil {
+			return nil, err
+		}
+		// 只有白名单中的代币充值才入账，原生币不受限制
+		if tx.TxType == database.TxTypeDeposit && token == nil && !isNativeToken(tx.TokenAddress) {
+			log.Warn("deposit of token not in whitelist, move to quarantine", "businessId", businessId, "txHash", tx.Hash, "tokenAddress", tx.TokenAddress)
+			tx.Quarantine = true
+		}
 
-			amountBigInt, _ := new(big.Int).SetString(txItem.Values[0].Value, 10)
-			log.Info("Transaction amount", "amount", amountBigInt, "fromAddress", tx.FromAddress, "toAddress", tx.ToAddress, "TokenAddress", tx.TokenAddress)
-			balances = append(balances, &database.TokenBalance{
-				FromAddress:  common.HexToAddress(tx.FromAddress),
-				ToAddress:    common.HexToAddress(tx.ToAddress),
+		transfers, err := d.expandNftTransfers(tx, txItem, token, receipts)
+		if err != nil {
+			return nil, err
+		}
+		for _, transfer := range transfers {
+			if transfer.TxType == database.TxTypeDeposit && isDust(transfer, txItem, token) {
+				log.Warn("deposit below min deposit amount, mark as dust", "businessId", businessId, "txHash", transfer.Hash, "tokenAddress", transfer.TokenAddress, "amount", transfer.Amount)
+				transfer.Dust = true
+			}
+			if err := d.appendTransfer(flow, transfer, txItem); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return flow, nil
+}
+
+// tokenInfo 查询业务方白名单中的代币，未注册的合约返回 nil；原生币返回零地址记录中的设置
+func (d *Deposit) tokenInfo(businessId string, tokenAddress string, cache map[string]*database.Tokens) (*database.Tokens, error) {
+	if token, ok := cache[tokenAddress]; ok {
+		return token, nil
+	}
+	token, err := d.database.Tokens.TokensInfoByAddress(businessId, common.HexToAddress(tokenAddress).String())
+	if err != nil {
+		log.Error("query token info fail", "tokenAddress", tokenAddress, "err", err)
+		return nil, err
+	}
+	cache[tokenAddress] = token
+	return token, nil
+}
+
+// isDust 同质化代币充值金额低于业务方配置的最小充值金额
+func isDust(tx *Transaction, txMsg *account.TxMessage, token *database.Tokens) bool {
+	if token == nil || token.MinDepositAmount == nil || token.MinDepositAmount.Sign() <= 0 || tx.TokenType.IsNft() {
+		return false
+	}
+	return transferAmount(tx, txMsg).Cmp(token.MinDepositAmount) < 0
+}
+
+func isNativeToken(tokenAddress string) bool {
+	return common.HexToAddress(tokenAddress) == (common.Address{})
+}
+
+func (d *Deposit) appendTransfer(flow *businessFlow, tx *Transaction, txItem *account.TxMessage) error {
+	amountBigInt := transferAmount(tx, txItem)
+	log.Info("Transaction amount", "amount", amountBigInt, "fromAddress", tx.FromAddress, "toAddress", tx.ToAddress, "TokenAddress", tx.TokenAddress, "tokenId", tx.TokenId)
+	if tx.Suspense || tx.Quarantine || tx.Dust {
+		// 待审核、隔离和 dust 充值只记录充值单，不入账也不记流水
+		depositItem, _ := d.HandleDeposit(tx, txItem)
+		flow.depositList = append(flow.depositList, depositItem)
+		return nil
+	}
+
+	failed := txFailed(txItem.Status)
+	if failed {
+		log.Warn("transaction failed on chain", "txHash", tx.Hash, "txType", tx.TxType, "status", txItem.Status)
+	}
+
+	log.Info("get transaction success", "txHash", txItem.Hash)
+	transactionFlow, err := d.BuildTransaction(tx, txItem)
+	if err != nil {
+		log.Info("handle transaction flow fail", "err", err)
+		return err
+	}
+
+	flow.transactionFlowList = append(flow.transactionFlowList, transactionFlow)
+
+	// 失败的交易只记录流水，不入账；NFT 记入持有表，不计入余额
+	if !failed && tx.TxType != database.TxTypeChange && tx.TxType != database.TxTypeUnknown {
+		if tx.TokenType.IsNft() && tx.TokenId == "" {
+			log.Warn("nft transfer without token id, skip ownership update", "txHash", tx.Hash, "txType", tx.TxType)
+		} else if tx.TokenType.IsNft() {
+			flow.nftTransfers = append(flow.nftTransfers, &database.NftTransfer{
+				Chain:        d.rpcClient.ChainName,
+				FromAddress:  tx.FromAddress,
+				ToAddress:    tx.ToAddress,
 				TokenAddress: common.HexToAddress(tx.TokenAddress),
-				Balance:      amountBigInt,
+				TokenId:      tx.TokenId,
+				TokenType:    tx.TokenType,
+				Amount:       amountBigInt,
 				TxType:       tx.TxType,
 			})
+		} else {
+			balance := &database.TokenBalance{
+				FromAddress:  tx.FromAddress,
+				ToAddress:    tx.ToAddress,
+				TokenAddress: common.HexToAddress(tx.TokenAddress),
+				Balance:      amountBigInt,
+				TxType:       tx.TxType,
+				SourceGuid:   transactionFlow.GUID,
+				Chain:        d.rpcClient.ChainName,
+				BlockNumber:  tx.BlockNumber,
+			}
+			// 一笔交易的手续费只在第一条转账上记账
+			if tx.Index == 0 {
+				balance.Fee = transactionFlow.Fee
+			}
+			flow.balances = append(flow.balances, balance)
+		}
+	}
 
-			log.Info("get transaction success", "txHash", txItem.Hash)
-			transactionFlow, err := d.BuildTransaction(tx, txItem)
-			if err != nil {
-				log.Info("handle transaction flow fail", "err", err)
+	switch tx.TxType {
+	case database.TxTypeDeposit:
+		depositItem, _ := d.HandleDeposit(tx, txItem)
+		flow.depositList = append(flow.depositList, depositItem)
+		break
+	case database.TxTypeWithdraw:
+		withdrawItem, _ := d.HandleWithdraw(tx, txItem)
+		if failed {
+			flow.failedWithdraws = append(flow.failedWithdraws, withdrawItem)
+		} else {
+			flow.withdrawList = append(flow.withdrawList, withdrawItem)
+		}
+		break
+	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot, database.TxTypeGasTopUp:
+		internalItem, _ := d.HandleInternalTx(tx, txItem)
+		if failed {
+			flow.failedInternals = append(flow.failedInternals, internalItem)
+		} else {
+			flow.internals = append(flow.internals, internalItem)
+		}
+		break
+	default:
+		break
+	}
+	return nil
+}
+
+func (d *Deposit) storeBusinessFlow(tx *database.DB, businessId string, flow *businessFlow) error {
+	if len(flow.depositList) > 0 {
+		log.Info("Store deposit transaction", "totalTx", len(flow.depositList))
+		if err := tx.Deposits.StoreDeposits(businessId, flow.depositList); err != nil {
+			log.Error("store deposits fail", "err", err)
+			return err
+		}
+	}
+
+	// handle balance
+	if len(flow.balances) > 0 {
+		log.Info("handle balance into db", "totalTx", len(flow.balances))
+		if err := tx.Balances.UpdateOrCreate(businessId, flow.balances); err != nil {
+			log.Error("handle balances fail", "err", err)
+			return err
+		}
+	}
+
+	// handle nft ownership
+	if len(flow.nftTransfers) > 0 {
+		log.Info("handle nft ownership into db", "totalTx", len(flow.nftTransfers))
+		if err := tx.Nfts.ApplyTransfers(businessId, flow.nftTransfers); err != nil {
+			log.Error("handle nft ownership fail", "err", err)
+			return err
+		}
+	}
+
+	// handle replacement transactions before updating withdraw and internal status by hash
+	if err := d.settleReplacements(tx, businessId, flow); err != nil {
+		return err
+	}
+
+	// handle withdraw
+	if len(flow.withdrawList) > 0 {
+		if err := tx.Withdraws.UpdateWithdrawStatusByTxHash(businessId, database.TxStatusWalletDone, flow.withdrawList); err != nil {
+			log.Error("handle withdraws fail", "err", err)
+			return err
+		}
+	}
+
+	//  handle collection hot 2 cold and cold 2 hot
+	if len(flow.internals) > 0 {
+		if err := tx.Internals.UpdateInternalStatusByTxHash(businessId, database.TxStatusWalletDone, flow.internals); err != nil {
+			log.Error("handle internals fail", "err", err)
+			return err
+		}
+	}
+
+	// handle failed withdraw and internal
+	if err := d.storeFailedFlow(tx, businessId, flow); err != nil {
+		return err
+	}
+
+	// handle transaction flow
+	if len(flow.transactionFlowList) > 0 {
+		if err := tx.Trasactions.StoreTransactions(businessId, flow.transactionFlowList, uint64(len(flow.transactionFlowList))); err != nil {
+			log.Error("store transactions fail", "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// settleReplacements 上链的交易是替换交易时把原交易的哈希改为上链的哈希，之后按哈希更新原交易的状态；
+// 原交易或任一替换交易上链后，其余替换交易因 nonce 已被占用标记为 failed
+func (d *Deposit) settleReplacements(tx *database.DB, businessId string, flow *businessFlow) error {
+	var minedWithdraws []*database.Withdraws
+	minedWithdraws = append(minedWithdraws, flow.withdrawList...)
+	minedWithdraws = append(minedWithdraws, flow.failedWithdraws...)
+	for _, withdraw := range minedWithdraws {
+		replacement, err := tx.Replaces.QueryReplacementByTxHash(businessId, withdraw.TxHash)
+		if err != nil {
+			log.Error("query replacement fail", "txHash", withdraw.TxHash, "err", err)
+			return err
+		}
+		if replacement != nil {
+			log.Info("replacement withdraw mined", "transactionId", replacement.TransactionId, "txHash", withdraw.TxHash)
+			if err := tx.Withdraws.UpdateWithdrawTxHashById(businessId, replacement.TransactionId, withdraw.TxHash); err != nil {
+				log.Error("update withdraw tx hash fail", "transactionId", replacement.TransactionId, "err", err)
 				return err
 			}
-
-			transactionFlowList = append(transactionFlowList, transactionFlow)
-
-			switch tx.TxType {
-			case database.TxTypeDeposit:
-				depositItem, _ := d.HandleDeposit(tx, txItem)
-				depositList = append(depositList, depositItem)
-				break
-			case database.TxTypeWithdraw:
-				withdrawItem, _ := d.HandleWithdraw(tx, txItem)
-				withdrawList = append(withdrawList, withdrawItem)
-				break
-			case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot:
-				internalItem, _ := d.HandleInternalTx(tx, txItem)
-				internals = append(internals, internalItem)
-				break
-			default:
-				break
+			if err := tx.Replaces.SettleReplacements(businessId, replacement.TransactionId, withdraw.TxHash); err != nil {
+				log.Error("settle replacements fail", "transactionId", replacement.TransactionId, "err", err)
+				return err
 			}
+			continue
 		}
+		original, err := tx.Withdraws.QueryWithdrawsByHash(businessId, withdraw.TxHash)
+		if err != nil {
+			log.Error("query withdraw fail", "txHash", withdraw.TxHash, "err", err)
+			return err
+		}
+		if original == nil {
+			continue
+		}
+		if err := tx.Replaces.SettleReplacements(businessId, original.GUID.String(), withdraw.TxHash); err != nil {
+			log.Error("settle replacements fail", "transactionId", original.GUID, "err", err)
+			return err
+		}
+	}
 
-		retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
-		if _, err := retry.Do[interface{}](d.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
-			if err := d.database.Transaction(func(tx *database.DB) error {
-				if len(depositList) > 0 {
-					log.Info("Store deposit transaction", "totalTx", len(depositList))
-					if err := tx.Deposits.StoreDeposits(business.BusinessUid, depositList); err != nil {
-						log.Error("store deposits fail", "err", err)
-						return err
-					}
-
-					// update deposit confirms
-					if err := tx.Deposits.UpdateDepositsConfirms(business.BusinessUid, batch[business.BusinessUid].BlockHeight, uint64(d.confirms)); err != nil {
-						log.Error("handle confirms fail", "err", err)
-						return err
-					}
-
-					// handle balance
-					if len(balances) > 0 {
-						log.Info("handle balance into db", "totalTx", len(balances))
-						if err := tx.Balances.UpdateOrCreate(business.BusinessUid, balances); err != nil {
-							log.Error("handle balances fail", "err", err)
-							return err
-						}
-					}
-
-					// handle withdraw
-					if len(withdrawList) > 0 {
-						if err := tx.Withdraws.UpdateWithdrawStatusByTxHash(business.BusinessUid, database.TxStatusWalletDone, withdrawList); err != nil {
-							log.Error("handle withdraws fail", "err", err)
-							return err
-						}
-					}
-
-					//  handle collection hot 2 cold and cold 2 hot
-					if len(internals) > 0 {
-						if err := tx.Internals.UpdateInternalStatusByTxHash(business.BusinessUid, database.TxStatusWalletDone, internals); err != nil {
-							log.Error("handle internals fail", "err", err)
-							return err
-						}
-					}
-
-					// handle transaction flow
-					if len(transactionFlowList) > 0 {
-						if err := tx.Trasactions.StoreTransactions(business.BusinessUid, transactionFlowList, uint64(len(transactionFlowList))); err != nil {
-							log.Error("store transactions fail", "err", err)
-							return err
-						}
-					}
-				}
-				return nil
-			}); err != nil {
-				log.Error("unable to persist batch", "err", err)
-				return nil, err
+	var minedInternals []*database.Internals
+	minedInternals = append(minedInternals, flow.internals...)
+	minedInternals = append(minedInternals, flow.failedInternals...)
+	for _, internal := range minedInternals {
+		replacement, err := tx.Replaces.QueryReplacementByTxHash(businessId, internal.TxHash)
+		if err != nil {
+			log.Error("query replacement fail", "txHash", internal.TxHash, "err", err)
+			return err
+		}
+		if replacement != nil {
+			log.Info("replacement internal mined", "transactionId", replacement.TransactionId, "txHash", internal.TxHash)
+			if err := tx.Internals.UpdateInternalTxHashById(businessId, replacement.TransactionId, internal.TxHash); err != nil {
+				log.Error("update internal tx hash fail", "transactionId", replacement.TransactionId, "err", err)
+				return err
 			}
-			return nil, nil
-		}); err != nil {
+			if err := tx.Replaces.SettleReplacements(businessId, replacement.TransactionId, internal.TxHash); err != nil {
+				log.Error("settle replacements fail", "transactionId", replacement.TransactionId, "err", err)
+				return err
+			}
+			continue
+		}
+		original, err := tx.Internals.QueryInternalByTxHash(businessId, internal.TxHash)
+		if err != nil {
+			log.Error("query internal fail", "txHash", internal.TxHash, "err", err)
+			return err
+		}
+		if original == nil {
+			continue
+		}
+		if err := tx.Replaces.SettleReplacements(businessId, original.GUID.String(), internal.TxHash); err != nil {
+			log.Error("settle replacements fail", "transactionId", original.GUID, "err", err)
 			return err
 		}
+	}
+	return nil
+}
 
+// storeFailedFlow 链上失败的提现和内部交易标记为失败，并释放发送时锁定的余额，通知服务按失败状态通知业务方
+func (d *Deposit) storeFailedFlow(tx *database.DB, businessId string, flow *businessFlow) error {
+	var released []*database.Balances
+	for _, failedWithdraw := range flow.failedWithdraws {
+		withdraw, err := tx.Withdraws.QueryWithdrawsByHash(businessId, failedWithdraw.TxHash)
+		if err != nil {
+			log.Error("query failed withdraw fail", "txHash", failedWithdraw.TxHash, "err", err)
+			return err
+		}
+		// 只有钱包发出的提现在发送时锁定过余额
+		if withdraw == nil || withdraw.Status != database.TxStatusBoradcasted {
+			continue
+		}
+		released = append(released, &database.Balances{
+			Address:      withdraw.FromAddress,
+			TokenAddress: withdraw.TokenAddress,
+			LockBalance:  withdraw.Amount,
+			SourceGuid:   withdraw.GUID,
+			Chain:        d.rpcClient.ChainName,
+			BlockNumber:  failedWithdraw.BlockNumber,
+		})
+	}
+	for _, failedInternal := range flow.failedInternals {
+		internal, err := tx.Internals.QueryInternalByTxHash(businessId, failedInternal.TxHash)
+		if err != nil {
+			log.Error("query failed internal fail", "txHash", failedInternal.TxHash, "err", err)
+			return err
+		}
+		if internal == nil || internal.Status != database.TxStatusBoradcasted {
+			continue
+		}
+		released = append(released, &database.Balances{
+			Address:      internal.FromAddress,
+			TokenAddress: internal.TokenAddress,
+			LockBalance:  internal.Amount,
+			SourceGuid:   internal.GUID,
+			Chain:        d.rpcClient.ChainName,
+			BlockNumber:  failedInternal.BlockNumber,
+		})
+	}
+
+	if err := tx.Balances.ReleaseLockBalance(businessId, released); err != nil {
+		log.Error("release lock balance fail", "err", err)
+		return err
+	}
+	if err := tx.Withdraws.UpdateWithdrawStatusByTxHash(businessId, database.TxStatusFailed, flow.failedWithdraws); err != nil {
+		log.Error("handle failed withdraws fail", "err", err)
+		return err
+	}
+	if err := tx.Internals.UpdateInternalStatusByTxHash(businessId, database.TxStatusFailed, flow.failedInternals); err != nil {
+		log.Error("handle failed internals fail", "err", err)
+		return err
 	}
 	return nil
 }
 
+// txFailed 链上执行失败或合约执行回滚的交易
+func txFailed(status account.TxStatus) bool {
+	return status == account.TxStatus_Failed || status == account.TxStatus_ContractExecuteFailed
+}
+
+// transferAmount 优先取同步时记录的该笔转账金额，没有时按转账序号取交易详情中的金额
+func transferAmount(tx *Transaction, txMsg *account.TxMessage) *big.Int {
+	value := tx.Amount
+	if value == "" && int(tx.Index) < len(txMsg.Values) {
+		value = txMsg.Values[tx.Index].Value
+	} else if value == "" && len(txMsg.Values) > 0 {
+		value = txMsg.Values[0].Value
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		log.Warn("invalid transfer amount", "txHash", tx.Hash, "value", value)
+		return big.NewInt(0)
+	}
+	return amount
+}
+
 func (d *Deposit) BuildTransaction(tx *Transaction, txMsg *account.TxMessage) (*database.Transactions, error) {
 	txFee, _ := new(big.Int).SetString(txMsg.Fee, 10)
-	txAmount, _ := new(big.Int).SetString(txMsg.Values[0].Value, 10)
+	txAmount := transferAmount(tx, txMsg)
 	transactionTx := &database.Transactions{
-		GUID:         uuid.New(),
-		BlockHash:    common.Hash{},
-		BlockNumber:  tx.BlockNumber,
-		Hash:         common.HexToHash(tx.Hash),
-		FromAddress:  common.HexToAddress(tx.FromAddress),
-		ToAddress:    common.HexToAddress(tx.ToAddress),
-		TokenAddress: common.HexToAddress(tx.TokenAddress),
-		TokenId:      "0x00",
-		TokenMeta:    "0x00",
-		Fee:          txFee,
-		Status:       txMsg.Status,
-		Amount:       txAmount,
-		TxType:       tx.TxType,
-		Timestamp:    uint64(time.Now().Unix()),
+		GUID:          uuid.New(),
+		Chain:         d.rpcClient.ChainName,
+		BlockHash:     common.Hash{},
+		BlockNumber:   tx.BlockNumber,
+		Hash:          common.HexToHash(tx.Hash),
+		TransferIndex: tx.Index,
+		FromAddress:   tx.FromAddress,
+		ToAddress:     tx.ToAddress,
+		TokenAddress:  common.HexToAddress(tx.TokenAddress),
+		TokenId:       tokenId(tx),
+		TokenMeta:     "0x00",
+		Fee:           txFee,
+		Status:        txMsg.Status,
+		Amount:        txAmount,
+		TxType:        tx.TxType,
+		Timestamp:     uint64(time.Now().Unix()),
 	}
 	return transactionTx, nil
 }
 
 func (d *Deposit) HandleWithdraw(tx *Transaction, txMsg *account.TxMessage) (*database.Withdraws, error) {
-	txAmount, _ := new(big.Int).SetString(txMsg.Values[0].Value, 10)
+	txAmount := transferAmount(tx, txMsg)
 	withdrawTx := &database.Withdraws{
 		GUID:         uuid.New(),
+		Chain:        d.rpcClient.ChainName,
 		BlockHash:    common.Hash{},
 		BlockNumber:  tx.BlockNumber,
 		TxHash:       common.HexToHash(tx.Hash),
-		FromAddress:  common.HexToAddress(tx.FromAddress),
-		ToAddress:    common.HexToAddress(tx.ToAddress),
+		FromAddress:  tx.FromAddress,
+		ToAddress:    tx.ToAddress,
 		TokenAddress: common.HexToAddress(tx.TokenAddress),
-		TokenId:      "0x00",
+		TokenId:      tokenId(tx),
 		TokenMeta:    "0x00",
 		MaxFeePerGas: txMsg.Fee,
 		Amount:       txAmount,
@@ -299,36 +625,52 @@ func (d *Deposit) HandleWithdraw(tx *Transaction, txMsg *account.TxMessage) (*da
 }
 
 func (d *Deposit) HandleDeposit(tx *Transaction, txMsg *account.TxMessage) (*database.Deposits, error) {
-	txAmount, _ := new(big.Int).SetString(txMsg.Values[0].Value, 10)
+	txAmount := transferAmount(tx, txMsg)
 	depositTx := &database.Deposits{
-		GUID:         uuid.New(),
-		BlockHash:    common.Hash{},
-		BlockNumber:  tx.BlockNumber,
-		TxHash:       common.HexToHash(tx.Hash),
-		FromAddress:  common.HexToAddress(tx.FromAddress),
-		ToAddress:    common.HexToAddress(tx.ToAddress),
-		TokenAddress: common.HexToAddress(tx.TokenAddress),
-		TokenId:      "0x00",
-		TokenMeta:    "0x00",
-		MaxFeePerGas: txMsg.Fee,
-		Amount:       txAmount,
-		Status:       database.TxStatusBoradcasted,
-		Timestamp:    uint64(time.Now().Unix()),
+		GUID:          uuid.New(),
+		Chain:         d.rpcClient.ChainName,
+		BlockHash:     common.Hash{},
+		BlockNumber:   tx.BlockNumber,
+		TxHash:        common.HexToHash(tx.Hash),
+		TransferIndex: tx.Index,
+		FromAddress:   tx.FromAddress,
+		ToAddress:     tx.ToAddress,
+		TokenAddress:  common.HexToAddress(tx.TokenAddress),
+		TokenId:       tokenId(tx),
+		TokenMeta:     "0x00",
+		MaxFeePerGas:  txMsg.Fee,
+		Amount:        txAmount,
+		Memo:          tx.Memo,
+		Status:        database.TxStatusBoradcasted,
+		Timestamp:     uint64(time.Now().Unix()),
+	}
+	if tx.Suspense {
+		depositTx.Status = database.TxStatusSuspense
+	}
+	if tx.Quarantine {
+		depositTx.Status = database.TxStatusQuarantine
+	}
+	if tx.Dust {
+		depositTx.Status = database.TxStatusDust
+	}
+	if txFailed(txMsg.Status) {
+		depositTx.Status = database.TxStatusFailed
 	}
 	return depositTx, nil
 }
 
 func (d *Deposit) HandleInternalTx(tx *Transaction, txMsg *account.TxMessage) (*database.Internals, error) {
-	txAmount, _ := new(big.Int).SetString(txMsg.Values[0].Value, 10)
+	txAmount := transferAmount(tx, txMsg)
 	internalsTx := &database.Internals{
 		GUID:         uuid.New(),
+		Chain:        d.rpcClient.ChainName,
 		BlockHash:    common.Hash{},
 		BlockNumber:  tx.BlockNumber,
 		TxHash:       common.HexToHash(tx.Hash),
-		FromAddress:  common.HexToAddress(tx.FromAddress),
-		ToAddress:    common.HexToAddress(tx.ToAddress),
+		FromAddress:  tx.FromAddress,
+		ToAddress:    tx.ToAddress,
 		TokenAddress: common.HexToAddress(tx.TokenAddress),
-		TokenId:      "0x00",
+		TokenId:      tokenId(tx),
 		TokenMeta:    "0x00",
 		MaxFeePerGas: txMsg.Fee,
 		Amount:       txAmount,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -19,13 +20,14 @@ import (
 type Internal struct {
 	rpcClient      *rpcclient.WalletChainAccountClient
 	db             *database.DB
+	maxAttempts    uint64
 	resourceCtx    context.Context
 	resourceCancel context.CancelFunc
 	tasks          tasks.Group
 	ticker         *time.Ticker
 }
 
-func NewInternal(cfg *config.Config,
+func NewInternal(cfg *config.ChainNodeConfig,
 	db *database.DB,
 	rpcClient *rpcclient.WalletChainAccountClient,
 	shutdown context.CancelCauseFunc) (*Internal, error) {
@@ -33,12 +35,13 @@ func NewInternal(cfg *config.Config,
 	return &Internal{
 		rpcClient:      rpcClient,
 		db:             db,
+		maxAttempts:    cfg.MaxBroadcastAttempts,
 		resourceCtx:    resCtx,
 		resourceCancel: resCancel,
 		tasks: tasks.Group{HandleCrit: func(err error) {
-			shutdown(fmt.Errorf("critical error in internals : %w", err))
+			shutdown(fmt.Errorf("critical error in %s internals : %w", rpcClient.ChainName, err))
 		}},
-		ticker: time.NewTicker(cfg.ChainNode.WorkerInterval),
+		ticker: time.NewTicker(cfg.WorkerInterval),
 	}, nil
 }
 
@@ -69,7 +72,7 @@ func (i *Internal) Start() error {
 				}
 
 				for _, business := range businessList {
-					unSendInternalList, err := i.db.Internals.UnSendInternalList(business.BusinessUid)
+					unSendInternalList, err := i.db.Internals.UnSendInternalList(business.BusinessUid, i.rpcClient.ChainName)
 					if err != nil {
 						log.Error("query un send internal list fail: ", "err", err)
 						continue
@@ -79,23 +82,58 @@ func (i *Internal) Start() error {
 						continue
 					}
 
+					// 记录广播时已同步的最新区块，watchdog 据此判断交易是否卡住
+					broadcastBlock := big.NewInt(0)
+					if header, err := i.db.Blocks.LatestBlocks(i.rpcClient.ChainName); err != nil {
+						log.Error("query latest block fail", "err", err)
+					} else if header != nil {
+						broadcastBlock = header.Number
+					}
+
 					var balanceList []*database.Balances
+					var broadcasted []string
 
 					for _, unSendInternalTx := range unSendInternalList {
+						// 广播前按签名交易计算哈希，广播结果不确定的交易上链后同步器也能按哈希匹配
+						if unSendInternalTx.TxHash == (common.Hash{}) {
+							if localHash, err := rpcclient.SignedTxHash(unSendInternalTx.TxSignHex); err != nil {
+								log.Warn("compute signed tx hash fail", "guid", unSendInternalTx.GUID, "err", err)
+							} else {
+								unSendInternalTx.TxHash = localHash
+							}
+						}
 						txHash, err := i.rpcClient.SendTx(unSendInternalTx.TxSignHex)
+						unSendInternalTx.BroadcastAttempts++
 						if err != nil {
-							log.Error("send internal tx fail: ", "err", err)
+							unSendInternalTx.LastError = err.Error()
+							// 达到最大次数后放弃并通知业务方；否则按指数退避等待下次重试。
+							// 超时或 already known 不能说明交易没有进入交易池，nonce 保留到链上 nonce 超过它，不在这里释放
+							if unSendInternalTx.BroadcastAttempts >= i.maxAttempts {
+								log.Error("send internal tx fail, give up", "guid", unSendInternalTx.GUID, "attempts", unSendInternalTx.BroadcastAttempts, "err", err)
+								unSendInternalTx.Status = database.TxStatusBroadcastFailed
+							} else {
+								log.Error("send internal tx fail: ", "guid", unSendInternalTx.GUID, "attempts", unSendInternalTx.BroadcastAttempts, "err", err)
+								unSendInternalTx.NextRetryAt = uint64(time.Now().Add(broadcastBackoff(unSendInternalTx.BroadcastAttempts)).Unix())
+							}
 							continue
 						} else {
 							balanceItem := &database.Balances{
 								TokenAddress: unSendInternalTx.TokenAddress,
 								Address:      unSendInternalTx.FromAddress,
 								LockBalance:  unSendInternalTx.Amount,
+								SourceGuid:   unSendInternalTx.GUID,
+								Chain:        i.rpcClient.ChainName,
+								BlockNumber:  broadcastBlock,
 							}
 							balanceList = append(balanceList, balanceItem)
+							broadcasted = append(broadcasted, unSendInternalTx.GUID.String())
 
-							unSendInternalTx.TxHash = common.HexToHash(txHash)
+							if nodeHash := common.HexToHash(txHash); txHash != "" && nodeHash != unSendInternalTx.TxHash {
+								log.Warn("node tx hash differs from signed tx hash", "guid", unSendInternalTx.GUID, "local", unSendInternalTx.TxHash, "node", nodeHash)
+								unSendInternalTx.TxHash = nodeHash
+							}
 							unSendInternalTx.Status = database.TxStatusBoradcasted
+							unSendInternalTx.BroadcastBlock = broadcastBlock
 						}
 					}
 
@@ -108,13 +146,18 @@ func (i *Internal) Start() error {
 									log.Error("Update address balance fail", "err", err)
 									return err
 								}
+								if err := tx.Nonces.MarkNonceBroadcast(i.rpcClient.ChainName, broadcasted); err != nil {
+									log.Error("mark nonce broadcast fail", "err", err)
+									return err
+								}
+							}
 
-								if len(unSendInternalList) > 0 {
-									err = i.db.Internals.UpdateInternalListById(business.BusinessUid, unSendInternalList)
-									if err != nil {
-										log.Error("update internals status fail", "err", err)
-										return err
-									}
+							// 广播失败的也要保存重试次数和失败原因
+							if len(unSendInternalList) > 0 {
+								err := tx.Internals.UpdateInternalListById(business.BusinessUid, unSendInternalList)
+								if err != nil {
+									log.Error("update internals status fail", "err", err)
+									return err
 								}
 							}
 							return nil
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// attributeMemo 共享地址的充值从交易的 Data 字段读取 memo，按 (地址, memo) 归属用户；
+// memo 缺失或不属于该地址时标记为 Suspense，充值入待审核区，不入账
+func (syncer *BaseSynchronizer) attributeMemo(tx *Transaction) error {
+	if tx.TxMsg == nil {
+		txMsg, err := syncer.getTransaction(tx.Hash)
+		if err != nil {
+			log.Error("get memo transaction failed", "txHash", tx.Hash, "err", err)
+			return err
+		}
+		tx.TxMsg = txMsg
+	}
+
+	tx.Memo = strings.TrimSpace(tx.TxMsg.Data)
+	if tx.Memo == "" || !syncer.addresses.LookupMemo(tx.BusinessId, tx.ToAddress, tx.Memo) {
+		log.Warn("deposit with missing or unknown memo, move to suspense", "businessId", tx.BusinessId, "txHash", tx.Hash, "to", tx.ToAddress, "memo", tx.Memo)
+		tx.Suspense = true
+	}
+	return nil
+}
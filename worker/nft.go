@@ -0,0 +1,289 @@
+package worker
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/common/retry"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+// NFT 转账方法的函数选择器
+const (
+	erc721TransferFrom           = "23b872dd" // transferFrom(address,address,uint256)
+	erc721SafeTransferFrom       = "42842e0e" // safeTransferFrom(address,address,uint256)
+	erc721SafeTransferFromData   = "b88d4fde" // safeTransferFrom(address,address,uint256,bytes)
+	erc1155SafeTransferFrom      = "f242432a" // safeTransferFrom(address,address,uint256,uint256,bytes)
+	erc1155SafeBatchTransferFrom = "2eb2c2d6" // safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)
+)
+
+// NFT 转账事件的 topic
+var (
+	erc721TransferTopic        = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	erc1155TransferSingleTopic = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	erc1155TransferBatchTopic  = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+)
+
+// nftIndexStride 一条转账记录拆分出多个 NFT 时，第 i 个使用 index + i*nftIndexStride：第一个沿用原 index，
+// 其余落在交易内其他转账记录的 index 范围之外，避免 (chain, hash, transfer_index) 冲突
+const nftIndexStride = 1 << 16
+
+type nftTransfer struct {
+	TokenId *big.Int
+	Amount  *big.Int
+}
+
+// nftReceipt 一笔交易回执中的日志，同一交易的多条转账记录共用；consumed 为已经拆分过的日志序号，
+// 同一条日志只记录一次
+type nftReceipt struct {
+	logs     []*types.Log
+	consumed map[uint]struct{}
+}
+
+// abiArgs 按 32 字节对齐的 ABI 编码参数
+type abiArgs []byte
+
+func (args abiArgs) word(offset int) (*big.Int, error) {
+	if offset < 0 || offset+32 > len(args) {
+		return nil, fmt.Errorf("nft transfer input out of range: %d", offset)
+	}
+	return new(big.Int).SetBytes(args[offset : offset+32]), nil
+}
+
+// array 第 position 个参数为动态数组的偏移
+func (args abiArgs) array(position int) ([]*big.Int, error) {
+	offset, err := args.word(position * 32)
+	if err != nil {
+		return nil, err
+	}
+	if !offset.IsInt64() || offset.Int64() > int64(len(args)) {
+		return nil, fmt.Errorf("invalid array offset: %s", offset)
+	}
+	length, err := args.word(int(offset.Int64()))
+	if err != nil {
+		return nil, err
+	}
+	if !length.IsInt64() || length.Int64() > int64(len(args)/32) {
+		return nil, fmt.Errorf("invalid array length: %s", length)
+	}
+	values := make([]*big.Int, length.Int64())
+	for i := range values {
+		if values[i], err = args.word(int(offset.Int64()) + 32*(i+1)); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// batch ERC1155 批量转账的 token id 和数量两个数组
+func (args abiArgs) batch(idsPosition int) ([]nftTransfer, error) {
+	tokenIds, err := args.array(idsPosition)
+	if err != nil {
+		return nil, err
+	}
+	amounts, err := args.array(idsPosition + 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokenIds) != len(amounts) {
+		return nil, fmt.Errorf("token ids and amounts length mismatch: %d != %d", len(tokenIds), len(amounts))
+	}
+	transfers := make([]nftTransfer, len(tokenIds))
+	for i := range tokenIds {
+		transfers[i] = nftTransfer{TokenId: tokenIds[i], Amount: amounts[i]}
+	}
+	return transfers, nil
+}
+
+// decodeNftTransfers 从交易的 calldata 中解析 NFT 的 token id 和数量，ERC721 数量固定为 1；
+// 只支持直接调用 NFT 合约的转账，没有交易回执时使用
+func decodeNftTransfers(tokenType database.TokenType, data string) ([]nftTransfer, error) {
+	input := common.FromHex(data)
+	if len(input) < 4 {
+		return nil, errors.New("nft transfer input too short")
+	}
+	selector := hex.EncodeToString(input[:4])
+	args := abiArgs(input[4:])
+
+	switch {
+	case tokenType == database.TokenTypeERC721 && (selector == erc721TransferFrom || selector == erc721SafeTransferFrom || selector == erc721SafeTransferFromData):
+		tokenId, err := args.word(2 * 32)
+		if err != nil {
+			return nil, err
+		}
+		return []nftTransfer{{TokenId: tokenId, Amount: big.NewInt(1)}}, nil
+	case tokenType == database.TokenTypeERC1155 && selector == erc1155SafeTransferFrom:
+		tokenId, err := args.word(2 * 32)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := args.word(3 * 32)
+		if err != nil {
+			return nil, err
+		}
+		return []nftTransfer{{TokenId: tokenId, Amount: amount}}, nil
+	case tokenType == database.TokenTypeERC1155 && selector == erc1155SafeBatchTransferFrom:
+		return args.batch(2)
+	default:
+		return nil, fmt.Errorf("unsupported %s transfer method: 0x%s", tokenType, selector)
+	}
+}
+
+// decodeNftLogs 从交易回执中解析 tokenAddress 合约 from -> to 的 Transfer / TransferSingle / TransferBatch 日志，
+// 市场合约、multicall、合约钱包发起的转账都能解析；解析过的日志记入 receipt.consumed，
+// matched 表示有匹配的日志，匹配的日志都已经被同一交易的其他转账记录拆分过时返回空
+func decodeNftLogs(tokenType database.TokenType, tokenAddress, from, to common.Address, receipt *nftReceipt) (transfers []nftTransfer, matched bool, err error) {
+	for _, item := range receipt.logs {
+		// 三种事件都是 4 个 topic；ERC20 的 Transfer 事件签名相同但 value 不是 indexed，只有 3 个 topic
+		if item.Address != tokenAddress || len(item.Topics) != 4 {
+			continue
+		}
+		var (
+			logFrom, logTo common.Address
+			decode         func() ([]nftTransfer, error)
+		)
+		switch {
+		case tokenType == database.TokenTypeERC721 && item.Topics[0] == erc721TransferTopic:
+			logFrom, logTo = common.BytesToAddress(item.Topics[1].Bytes()), common.BytesToAddress(item.Topics[2].Bytes())
+			decode = func() ([]nftTransfer, error) {
+				return []nftTransfer{{TokenId: item.Topics[3].Big(), Amount: big.NewInt(1)}}, nil
+			}
+		case tokenType == database.TokenTypeERC1155 && item.Topics[0] == erc1155TransferSingleTopic:
+			logFrom, logTo = common.BytesToAddress(item.Topics[2].Bytes()), common.BytesToAddress(item.Topics[3].Bytes())
+			decode = func() ([]nftTransfer, error) {
+				args := abiArgs(item.Data)
+				tokenId, err := args.word(0)
+				if err != nil {
+					return nil, err
+				}
+				amount, err := args.word(32)
+				if err != nil {
+					return nil, err
+				}
+				return []nftTransfer{{TokenId: tokenId, Amount: amount}}, nil
+			}
+		case tokenType == database.TokenTypeERC1155 && item.Topics[0] == erc1155TransferBatchTopic:
+			logFrom, logTo = common.BytesToAddress(item.Topics[2].Bytes()), common.BytesToAddress(item.Topics[3].Bytes())
+			decode = func() ([]nftTransfer, error) {
+				return abiArgs(item.Data).batch(0)
+			}
+		default:
+			continue
+		}
+		if logFrom != from || logTo != to {
+			continue
+		}
+		matched = true
+		if _, ok := receipt.consumed[item.Index]; ok {
+			continue
+		}
+
+		decoded, err := decode()
+		if err != nil {
+			return nil, matched, err
+		}
+		receipt.consumed[item.Index] = struct{}{}
+		transfers = append(transfers, decoded...)
+	}
+	return transfers, matched, nil
+}
+
+// nftTransferIndex 一条转账记录拆分出的第 i 个 NFT 的 index，见 nftIndexStride
+func nftTransferIndex(index uint32, i int) (uint32, error) {
+	if index >= nftIndexStride || i >= (1<<31)/nftIndexStride {
+		return 0, fmt.Errorf("nft transfer index out of range: index %d, item %d", index, i)
+	}
+	return index + uint32(i)*nftIndexStride, nil
+}
+
+// expandNftTransfers NFT 合约的转账按 token id 拆分，每个 token id 记录一笔转账，数量记为 Amount；
+// 优先解析交易回执中的转账日志，没有配置节点或没有匹配的日志时解析 calldata。
+// 都解析不出来的转账不丢弃：充值进入隔离区等待人工处理，其他类型只记录流水不变更 NFT 持有记录。
+// 非 NFT 合约原样返回
+func (d *Deposit) expandNftTransfers(tx *Transaction, txMsg *account.TxMessage, token *database.Tokens, receipts map[string]*nftReceipt) ([]*Transaction, error) {
+	tokenType := database.TokenTypeERC20
+	if token != nil && token.TokenType != "" {
+		tokenType = token.TokenType
+	}
+	if !tokenType.IsNft() {
+		return []*Transaction{tx}, nil
+	}
+
+	var nfts []nftTransfer
+	receipt, err := d.nftReceipt(tx.Hash, receipts)
+	if err != nil {
+		return nil, err
+	}
+	if receipt != nil {
+		var matched bool
+		nfts, matched, err = decodeNftLogs(tokenType, common.HexToAddress(tx.TokenAddress), common.HexToAddress(tx.FromAddress.String()), common.HexToAddress(tx.ToAddress.String()), receipt)
+		if err != nil {
+			log.Warn("decode nft transfer logs fail", "txHash", tx.Hash, "tokenAddress", tx.TokenAddress, "err", err)
+		} else if matched && len(nfts) == 0 {
+			log.Info("nft transfer logs already recorded by another transfer of the transaction", "txHash", tx.Hash, "index", tx.Index)
+			return nil, nil
+		}
+	}
+	if len(nfts) == 0 {
+		nfts, err = decodeNftTransfers(tokenType, txMsg.Data)
+		if err != nil {
+			log.Warn("decode nft transfer fail, record without token id", "txHash", tx.Hash, "tokenAddress", tx.TokenAddress, "txType", tx.TxType, "err", err)
+			transfer := *tx
+			transfer.TokenType = tokenType
+			transfer.Quarantine = tx.TxType == database.TxTypeDeposit
+			return []*Transaction{&transfer}, nil
+		}
+	}
+
+	transfers := make([]*Transaction, 0, len(nfts))
+	for i, nft := range nfts {
+		index, err := nftTransferIndex(tx.Index, i)
+		if err != nil {
+			return nil, err
+		}
+		transfer := *tx
+		transfer.Index = index
+		transfer.TokenType = tokenType
+		transfer.TokenId = nft.TokenId.String()
+		transfer.Amount = nft.Amount.String()
+		transfers = append(transfers, &transfer)
+	}
+	return transfers, nil
+}
+
+// nftReceipt 查询并缓存交易回执中的日志，没有配置节点时返回 nil
+func (d *Deposit) nftReceipt(hash string, receipts map[string]*nftReceipt) (*nftReceipt, error) {
+	if d.node == nil {
+		return nil, nil
+	}
+	if receipt, ok := receipts[hash]; ok {
+		return receipt, nil
+	}
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	logs, err := retry.Do[[]*types.Log](context.Background(), 5, retryStrategy, func() ([]*types.Log, error) {
+		return d.node.TransactionLogs(hash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	receipt := &nftReceipt{logs: logs, consumed: make(map[uint]struct{})}
+	receipts[hash] = receipt
+	return receipt, nil
+}
+
+// tokenId 同质化代币的 token id 固定为 0x00
+func tokenId(tx *Transaction) string {
+	if tx.TokenId == "" {
+		return "0x00"
+	}
+	return tx.TokenId
+}
@@ -0,0 +1,310 @@
+package worker
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+var (
+	testNftContract = common.HexToAddress("0x5000000000000000000000000000000000000005")
+	testNftFrom     = common.HexToAddress("0x4000000000000000000000000000000000000004")
+	testNftTo       = common.HexToAddress("0x2000000000000000000000000000000000000002")
+)
+
+// abiWords 按 32 字节对齐编码 ABI 参数
+func abiWords(values ...int64) []byte {
+	data := make([]byte, 0, 32*len(values))
+	for _, value := range values {
+		data = append(data, common.BigToHash(big.NewInt(value)).Bytes()...)
+	}
+	return data
+}
+
+// nftCalldata 函数选择器加 ABI 参数的 calldata
+func nftCalldata(selector string, args []byte) string {
+	return "0x" + selector + hex.EncodeToString(args)
+}
+
+func addressWord(address common.Address) []byte {
+	return common.BytesToHash(address.Bytes()).Bytes()
+}
+
+func nftCalldataWithAddresses(selector string, args []byte) string {
+	data := append(addressWord(testNftFrom), addressWord(testNftTo)...)
+	return nftCalldata(selector, append(data, args...))
+}
+
+func nftTransfers(pairs ...int64) []nftTransfer {
+	transfers := make([]nftTransfer, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		transfers = append(transfers, nftTransfer{TokenId: big.NewInt(pairs[i]), Amount: big.NewInt(pairs[i+1])})
+	}
+	return transfers
+}
+
+func TestDecodeNftTransfers(t *testing.T) {
+	// safeBatchTransferFrom(from, to, ids, amounts, data)：ids 偏移 0xa0，amounts 偏移 0x100，data 偏移 0x160
+	batchArgs := abiWords(0xa0, 0x100, 0x160, 2, 7, 8, 2, 3, 4, 0)
+	tests := []struct {
+		name      string
+		tokenType database.TokenType
+		data      string
+		want      []nftTransfer
+		wantErr   bool
+	}{
+		{
+			name:      "erc721 transferFrom",
+			tokenType: database.TokenTypeERC721,
+			data:      nftCalldataWithAddresses(erc721TransferFrom, abiWords(42)),
+			want:      nftTransfers(42, 1),
+		},
+		{
+			name:      "erc721 safeTransferFrom",
+			tokenType: database.TokenTypeERC721,
+			data:      nftCalldataWithAddresses(erc721SafeTransferFrom, abiWords(43)),
+			want:      nftTransfers(43, 1),
+		},
+		{
+			name:      "erc721 safeTransferFrom with data",
+			tokenType: database.TokenTypeERC721,
+			data:      nftCalldataWithAddresses(erc721SafeTransferFromData, abiWords(44, 0x80, 0)),
+			want:      nftTransfers(44, 1),
+		},
+		{
+			name:      "erc1155 safeTransferFrom",
+			tokenType: database.TokenTypeERC1155,
+			data:      nftCalldataWithAddresses(erc1155SafeTransferFrom, abiWords(5, 10, 0xa0, 0)),
+			want:      nftTransfers(5, 10),
+		},
+		{
+			name:      "erc1155 safeBatchTransferFrom",
+			tokenType: database.TokenTypeERC1155,
+			data:      nftCalldataWithAddresses(erc1155SafeBatchTransferFrom, batchArgs),
+			want:      nftTransfers(7, 3, 8, 4),
+		},
+		{
+			name:      "erc1155 batch length mismatch",
+			tokenType: database.TokenTypeERC1155,
+			data:      nftCalldataWithAddresses(erc1155SafeBatchTransferFrom, abiWords(0xa0, 0x100, 0x140, 2, 7, 8, 1, 3, 0)),
+			wantErr:   true,
+		},
+		{
+			name:      "erc1155 batch array offset out of range",
+			tokenType: database.TokenTypeERC1155,
+			data:      nftCalldataWithAddresses(erc1155SafeBatchTransferFrom, abiWords(0x1000, 0x100)),
+			wantErr:   true,
+		},
+		{
+			name:      "erc721 selector on erc1155 token",
+			tokenType: database.TokenTypeERC1155,
+			data:      nftCalldataWithAddresses(erc721TransferFrom, abiWords(42)),
+			wantErr:   true,
+		},
+		{
+			name:      "truncated token id",
+			tokenType: database.TokenTypeERC721,
+			data:      nftCalldata(erc721TransferFrom, addressWord(testNftFrom)),
+			wantErr:   true,
+		},
+		{
+			name:      "input too short",
+			tokenType: database.TokenTypeERC721,
+			data:      "0x23b8",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers, err := decodeNftTransfers(tt.tokenType, tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, transfers)
+		})
+	}
+}
+
+func TestDecodeNftLogs(t *testing.T) {
+	operator := common.HexToHash("0x6000000000000000000000000000000000000006")
+	from, to := common.BytesToHash(testNftFrom.Bytes()), common.BytesToHash(testNftTo.Bytes())
+	erc721Log := func(index uint, tokenId int64) *types.Log {
+		return &types.Log{
+			Address: testNftContract,
+			Topics:  []common.Hash{erc721TransferTopic, from, to, common.BigToHash(big.NewInt(tokenId))},
+			Index:   index,
+		}
+	}
+	// ERC20 的 Transfer 事件 value 不是 indexed，只有 3 个 topic
+	erc20Log := &types.Log{
+		Address: testNftContract,
+		Topics:  []common.Hash{erc721TransferTopic, from, to},
+		Data:    abiWords(100),
+		Index:   9,
+	}
+
+	tests := []struct {
+		name        string
+		tokenType   database.TokenType
+		logs        []*types.Log
+		consumed    []uint
+		want        []nftTransfer
+		wantMatched bool
+	}{
+		{
+			name:        "erc721 transfers",
+			tokenType:   database.TokenTypeERC721,
+			logs:        []*types.Log{erc721Log(0, 1), erc721Log(1, 2), erc20Log},
+			want:        nftTransfers(1, 1, 2, 1),
+			wantMatched: true,
+		},
+		{
+			name:      "other contract and direction ignored",
+			tokenType: database.TokenTypeERC721,
+			logs: []*types.Log{
+				{Address: common.HexToAddress("0x7"), Topics: []common.Hash{erc721TransferTopic, from, to, common.BigToHash(big.NewInt(1))}},
+				{Address: testNftContract, Topics: []common.Hash{erc721TransferTopic, to, from, common.BigToHash(big.NewInt(2))}},
+			},
+			want:        nil,
+			wantMatched: false,
+		},
+		{
+			name:        "consumed logs are matched but not decoded again",
+			tokenType:   database.TokenTypeERC721,
+			logs:        []*types.Log{erc721Log(0, 1), erc721Log(1, 2)},
+			consumed:    []uint{0, 1},
+			want:        nil,
+			wantMatched: true,
+		},
+		{
+			name:      "erc1155 single and batch",
+			tokenType: database.TokenTypeERC1155,
+			logs: []*types.Log{
+				{Address: testNftContract, Topics: []common.Hash{erc1155TransferSingleTopic, operator, from, to}, Data: abiWords(5, 10), Index: 0},
+				{Address: testNftContract, Topics: []common.Hash{erc1155TransferBatchTopic, operator, from, to}, Data: abiWords(0x40, 0xa0, 2, 7, 8, 2, 3, 4), Index: 1},
+			},
+			want:        nftTransfers(5, 10, 7, 3, 8, 4),
+			wantMatched: true,
+		},
+		{
+			name:        "erc721 event on erc1155 token ignored",
+			tokenType:   database.TokenTypeERC1155,
+			logs:        []*types.Log{erc721Log(0, 1)},
+			want:        nil,
+			wantMatched: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := &nftReceipt{logs: tt.logs, consumed: make(map[uint]struct{})}
+			for _, index := range tt.consumed {
+				receipt.consumed[index] = struct{}{}
+			}
+			transfers, matched, err := decodeNftLogs(tt.tokenType, testNftContract, testNftFrom, testNftTo, receipt)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantMatched, matched)
+			require.Equal(t, tt.want, transfers)
+		})
+	}
+}
+
+func TestNftTransferIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   uint32
+		item    int
+		want    uint32
+		wantErr bool
+	}{
+		{name: "first item keeps index", index: 3, item: 0, want: 3},
+		{name: "second item", index: 3, item: 1, want: 3 + nftIndexStride},
+		{name: "largest index", index: nftIndexStride - 1, item: 2, want: 3*nftIndexStride - 1},
+		{name: "index out of range", index: nftIndexStride, item: 0, wantErr: true},
+		{name: "item out of range", index: 0, item: (1 << 31) / nftIndexStride, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := nftTransferIndex(tt.index, tt.item)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, index)
+		})
+	}
+}
+
+// TestExpandNftTransfersIndexCollision 同一交易中批量转账拆分出的记录不能与其他转账记录的 index 冲突
+func TestExpandNftTransfersIndexCollision(t *testing.T) {
+	d := &Deposit{}
+	token := &database.Tokens{TokenAddress: testNftContract, TokenType: database.TokenTypeERC1155}
+	batch := &Transaction{
+		Hash:         "0xnft",
+		Index:        0,
+		FromAddress:  database.AddressFormatEVM.Normalize(testNftFrom.Hex()),
+		ToAddress:    database.AddressFormatEVM.Normalize(testNftTo.Hex()),
+		TokenAddress: testNftContract.Hex(),
+		TxType:       database.TxTypeDeposit,
+	}
+	batchMsg := &account.TxMessage{
+		Data: nftCalldataWithAddresses(erc1155SafeBatchTransferFrom, abiWords(0xa0, 0x120, 0x1c0, 3, 7, 8, 9, 3, 1, 1, 1, 0)),
+	}
+	single := *batch
+	single.Index = 1
+	singleMsg := &account.TxMessage{
+		Data: nftCalldataWithAddresses(erc1155SafeTransferFrom, abiWords(5, 10, 0xa0, 0)),
+	}
+
+	batchTransfers, err := d.expandNftTransfers(batch, batchMsg, token, map[string]*nftReceipt{})
+	require.NoError(t, err)
+	singleTransfers, err := d.expandNftTransfers(&single, singleMsg, token, map[string]*nftReceipt{})
+	require.NoError(t, err)
+
+	indexes := make(map[uint32]string)
+	for _, transfer := range append(batchTransfers, singleTransfers...) {
+		_, ok := indexes[transfer.Index]
+		require.False(t, ok, "duplicate transfer index %d", transfer.Index)
+		indexes[transfer.Index] = transfer.TokenId
+	}
+	require.Equal(t, map[uint32]string{
+		0:                  "7",
+		nftIndexStride:     "8",
+		2 * nftIndexStride: "9",
+		1:                  "5",
+	}, indexes)
+}
+
+func TestExpandNftTransfersUndecodable(t *testing.T) {
+	d := &Deposit{}
+	tests := []struct {
+		name           string
+		txType         database.TransactionType
+		wantQuarantine bool
+	}{
+		{name: "deposit quarantined", txType: database.TxTypeDeposit, wantQuarantine: true},
+		{name: "withdraw recorded", txType: database.TxTypeWithdraw, wantQuarantine: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &Transaction{Hash: "0xnft", Index: 2, TokenAddress: testNftContract.Hex(), TxType: tt.txType}
+			token := &database.Tokens{TokenAddress: testNftContract, TokenType: database.TokenTypeERC721}
+			transfers, err := d.expandNftTransfers(tx, &account.TxMessage{Data: "0x"}, token, map[string]*nftReceipt{})
+			require.NoError(t, err)
+			require.Len(t, transfers, 1)
+			require.Equal(t, uint32(2), transfers[0].Index)
+			require.Equal(t, "", transfers[0].TokenId)
+			require.Equal(t, database.TokenTypeERC721, transfers[0].TokenType)
+			require.Equal(t, tt.wantQuarantine, transfers[0].Quarantine)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/common/retry"
+	"github.com/JokingLove/multichain-sync-account/common/tasks"
+	"github.com/JokingLove/multichain-sync-account/config"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+)
+
+// Rebalancer 定时比较热钱包各代币余额：超过 ColdAmount 时把超出部分转入冷钱包，
+// 低于 HotFloorAmount 时发起冷转热补充到 ColdAmount（未配置 ColdAmount 时补充到 HotFloorAmount）。
+// 生成的交易状态为 create_unsigned，由业务方通过接口取出签名
+type Rebalancer struct {
+	rpcClient      *rpcclient.WalletChainAccountClient
+	db             *database.DB
+	utxo           bool
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+}
+
+func NewRebalancer(cfg *config.ChainNodeConfig, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Rebalancer, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Rebalancer{
+		rpcClient:      rpcClient,
+		db:             db,
+		utxo:           cfg.Utxo,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in %s rebalancer: %w", rpcClient.ChainName, err))
+		}},
+		ticker: time.NewTicker(cfg.RebalanceInterval),
+	}, nil
+}
+
+func (r *Rebalancer) Close() error {
+	var result error
+	r.resourceCancel()
+	r.ticker.Stop()
+	log.Info("stop rebalancer......", "chain", r.rpcClient.ChainName)
+	if err := r.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await rebalancer: %w", err))
+		return result
+	}
+	log.Info("stop rebalancer success", "chain", r.rpcClient.ChainName)
+	return nil
+}
+
+func (r *Rebalancer) Start() error {
+	if r.utxo {
+		log.Info("skip rebalancer for utxo chain", "chain", r.rpcClient.ChainName)
+		return nil
+	}
+	log.Info("starting rebalancer...", "chain", r.rpcClient.ChainName)
+	r.tasks.Go(func() error {
+		for {
+			select {
+			case <-r.ticker.C:
+				businessList, err := r.db.Business.QueryBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				for _, business := range businessList {
+					if err := r.rebalance(business.BusinessUid); err != nil {
+						log.Error("rebalance hot wallet fail", "business", business.BusinessUid, "err", err)
+					}
+				}
+			case <-r.resourceCtx.Done():
+				log.Info("stop rebalancer in worker", "chain", r.rpcClient.ChainName)
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// rebalance 按本链的热钱包余额调拨，同一代币已有未上链的热转冷或冷转热时本轮跳过
+func (r *Rebalancer) rebalance(businessId string) error {
+	hotWallet, err := r.db.Addresses.QueryHotWalletInfo(businessId, r.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	coldWallet, err := r.db.Addresses.QueryColdWalletInfo(businessId, r.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	if hotWallet == nil || coldWallet == nil {
+		log.Debug("business has no hot or cold wallet, skip rebalance", "business", businessId)
+		return nil
+	}
+
+	tokens, err := r.db.Tokens.QueryTokenList(businessId)
+	if err != nil {
+		return err
+	}
+
+	pendingInternals, err := r.db.Internals.QueryPendingInternals(businessId, r.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	pending := make(map[common.Address]struct{})
+	for _, internal := range pendingInternals {
+		if internal.TxType == database.TxTypeHot2Cold || internal.TxType == database.TxTypeCold2Hot {
+			pending[internal.TokenAddress] = struct{}{}
+		}
+	}
+
+	var fee *rpcclient.FeeInfo
+	var internals []*database.Internals
+	for _, token := range tokens {
+		if token.TokenType.IsNft() {
+			continue
+		}
+		coldAmount, floorAmount := amountOrZero(token.ColdAmount), amountOrZero(token.HotFloorAmount)
+		if coldAmount.Sign() <= 0 && floorAmount.Sign() <= 0 {
+			continue
+		}
+		if _, ok := pending[token.TokenAddress]; ok {
+			continue
+		}
+
+		hotBalance, err := r.db.Balances.QueryBalance(businessId, r.rpcClient.ChainName, hotWallet.Address, token.TokenAddress)
+		if err != nil {
+			return err
+		}
+
+		var (
+			txType database.TransactionType
+			from   database.Address
+			to     database.Address
+			amount *big.Int
+		)
+		switch {
+		case coldAmount.Sign() > 0 && hotBalance.Balance.Cmp(coldAmount) > 0:
+			txType, from, to = database.TxTypeHot2Cold, hotWallet.Address, coldWallet.Address
+			amount = new(big.Int).Sub(hotBalance.Balance, coldAmount)
+		case floorAmount.Sign() > 0 && hotBalance.Balance.Cmp(floorAmount) < 0:
+			target := floorAmount
+			if coldAmount.Cmp(floorAmount) > 0 {
+				target = coldAmount
+			}
+			txType, from, to = database.TxTypeCold2Hot, coldWallet.Address, hotWallet.Address
+			amount = new(big.Int).Sub(target, hotBalance.Balance)
+		default:
+			continue
+		}
+
+		if fee == nil {
+			if fee, err = r.rpcClient.GetFee(hotWallet.Address.String()); err != nil {
+				return err
+			}
+		}
+		gasLimit := rpcclient.TokenGasLimit
+		if token.TokenAddress == (common.Address{}) {
+			gasLimit = rpcclient.EthGasLimit
+		}
+		log.Info("rebalance hot wallet", "business", businessId, "txType", txType, "token", token.TokenAddress, "hotBalance", hotBalance.Balance, "amount", amount)
+		internals = append(internals, newInternal(r.rpcClient.ChainName, txType, from, to, token, amount, gasLimit, fee))
+	}
+
+	if len(internals) == 0 {
+		return nil
+	}
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	_, err = retry.Do[interface{}](r.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		return nil, r.db.Internals.StoreInternals(businessId, internals)
+	})
+	return err
+}
+
+func amountOrZero(amount *big.Int) *big.Int {
+	if amount == nil {
+		return big.NewInt(0)
+	}
+	return amount
+}
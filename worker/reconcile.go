@@ -0,0 +1,259 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/JokingLove/multichain-sync-account/common/retry"
+	"github.com/JokingLove/multichain-sync-account/common/tasks"
+	"github.com/JokingLove/multichain-sync-account/config"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+)
+
+// Reconciler 定时通过链账户服务 GetAccount 查询每个地址原生币和已注册代币的链上余额，与该链 balances 的可用余额加锁定余额比较。
+// GetAccount 返回链上最新余额，还没有同步的区块会造成暂时的差额，同一差额连续两轮出现才记录；
+// 配置了节点 json-rpc 时改为查询已同步最新区块上的余额，balances 与区块头在同一事务中写入，按同一区块比较时同步中的交易不会被误报。
+// 差额超过代币的 ReconcileTolerance 且与上次记录不同时写入对账表，由通知服务告警业务方；容差内的差额只记日志
+type Reconciler struct {
+	rpcClient      *rpcclient.WalletChainAccountClient
+	node           *rpcclient.NodeClient
+	db             *database.DB
+	utxo           bool
+	observed       map[string]string
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+}
+
+func NewReconciler(cfg *config.ChainNodeConfig, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Reconciler, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	// 链账户服务只能查询最新区块的余额，按区块查询需要节点的 json-rpc，没有配置时不按区块查询
+	var node *rpcclient.NodeClient
+	if !cfg.Utxo && cfg.RpcUrl != "" {
+		var err error
+		node, err = rpcclient.NewNodeClient(resCtx, cfg.RpcUrl)
+		if err != nil {
+			resCancel()
+			return nil, err
+		}
+	}
+	return &Reconciler{
+		rpcClient:      rpcClient,
+		node:           node,
+		db:             db,
+		utxo:           cfg.Utxo,
+		observed:       make(map[string]string),
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in %s reconciler: %w", rpcClient.ChainName, err))
+		}},
+		ticker: time.NewTicker(cfg.ReconcileInterval),
+	}, nil
+}
+
+func (r *Reconciler) Close() error {
+	var result error
+	r.resourceCancel()
+	r.ticker.Stop()
+	log.Info("stop reconciler......", "chain", r.rpcClient.ChainName)
+	if err := r.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await reconciler: %w", err))
+		return result
+	}
+	if r.node != nil {
+		r.node.Close()
+	}
+	log.Info("stop reconciler success", "chain", r.rpcClient.ChainName)
+	return nil
+}
+
+func (r *Reconciler) Start() error {
+	// UTXO 链的余额由未花费输出组成，没有按地址查询的账户余额
+	if r.utxo {
+		log.Info("skip reconciler for utxo chain", "chain", r.rpcClient.ChainName)
+		return nil
+	}
+	log.Info("starting reconciler...", "chain", r.rpcClient.ChainName, "atBlock", r.node != nil)
+	r.tasks.Go(func() error {
+		for {
+			select {
+			case <-r.ticker.C:
+				businessList, err := r.db.Business.QueryBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				for _, business := range businessList {
+					if err := r.reconcile(business.BusinessUid); err != nil {
+						log.Error("reconcile balances fail", "business", business.BusinessUid, "err", err)
+					}
+				}
+			case <-r.resourceCtx.Done():
+				log.Info("stop reconciler in worker", "chain", r.rpcClient.ChainName)
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// reconcile 共享地址的多个 memo 只查询一次；读取记账余额期间有新区块入库、或查询链上余额失败的地址本轮跳过
+func (r *Reconciler) reconcile(businessId string) error {
+	head, err := r.db.Blocks.LatestBlocks(r.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		return nil
+	}
+	addresses, err := r.db.Addresses.GetAllAddresses(businessId, r.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	tokenList, err := r.db.Tokens.QueryTokenList(businessId)
+	if err != nil {
+		return err
+	}
+	// 原生币没有注册时也要对账，零地址的记录为原生币的容差设置
+	tokens := []*database.Tokens{{TokenAddress: common.Address{}}}
+	for _, token := range tokenList {
+		if token.TokenAddress == (common.Address{}) {
+			tokens[0] = token
+			continue
+		}
+		if token.TokenType.IsNft() {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	var reconciliations []*database.Reconciliations
+	checked := make(map[database.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		if _, ok := checked[address.Address]; ok {
+			continue
+		}
+		checked[address.Address] = struct{}{}
+
+		ledgerBalances := make([]*big.Int, len(tokens))
+		for i, token := range tokens {
+			balance, err := r.db.Balances.QueryBalance(businessId, r.rpcClient.ChainName, address.Address, token.TokenAddress)
+			if err != nil {
+				return err
+			}
+			ledgerBalances[i] = new(big.Int).Add(amountOrZero(balance.Balance), amountOrZero(balance.LockBalance))
+		}
+		latest, err := r.db.Blocks.LatestBlocks(r.rpcClient.ChainName)
+		if err != nil {
+			return err
+		}
+		if latest == nil {
+			return nil
+		}
+		if latest.Hash != head.Hash {
+			log.Info("new blocks synced while reading ledger balances, skip address", "business", businessId, "address", address.Address)
+			head = latest
+			continue
+		}
+
+		for i, token := range tokens {
+			chainBalance, err := r.chainBalance(address.Address, token.TokenAddress, head)
+			if err != nil {
+				log.Warn("query on-chain balance fail, skip reconcile", "business", businessId, "address", address.Address, "token", token.TokenAddress, "block", head.Number, "err", err)
+				continue
+			}
+			reconciliation, err := r.mismatch(businessId, address, token, ledgerBalances[i], chainBalance, head)
+			if err != nil {
+				return err
+			}
+			if reconciliation != nil {
+				reconciliations = append(reconciliations, reconciliation)
+			}
+		}
+	}
+
+	if len(reconciliations) == 0 {
+		return nil
+	}
+	log.Info("store reconciliations", "business", businessId, "chain", r.rpcClient.ChainName, "count", len(reconciliations))
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	_, err = retry.Do[interface{}](r.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		return nil, r.db.Reconciles.StoreReconciliations(businessId, reconciliations)
+	})
+	return err
+}
+
+// chainBalance 配置了节点 json-rpc 时查询 head 区块上的余额，否则通过链账户服务查询最新余额
+func (r *Reconciler) chainBalance(address database.Address, tokenAddress common.Address, head *rpcclient.BlockHeader) (*big.Int, error) {
+	if r.node != nil {
+		return r.node.BalanceAtHash(common.HexToAddress(address.String()), tokenAddress, head.Hash)
+	}
+	contractAddress := "0x00"
+	if tokenAddress != (common.Address{}) {
+		contractAddress = tokenAddress.String()
+	}
+	accountInfo, err := r.rpcClient.GetAccount(address.String(), contractAddress)
+	if err != nil {
+		return nil, err
+	}
+	return accountInfo.Balance, nil
+}
+
+// mismatch 返回需要写入对账表的差异：容差内的差额只记日志，与上次记录的余额相同的差额不重复写入；
+// 按最新余额比较时差额需要连续两轮相同才记录
+func (r *Reconciler) mismatch(businessId string, address *database.Addresses, token *database.Tokens, ledgerBalance, chainBalance *big.Int, head *rpcclient.BlockHeader) (*database.Reconciliations, error) {
+	key := fmt.Sprintf("%s/%s/%s", businessId, address.Address, token.TokenAddress)
+	difference := new(big.Int).Sub(chainBalance, ledgerBalance)
+	difference.Abs(difference)
+	if difference.Sign() == 0 {
+		delete(r.observed, key)
+		return nil, nil
+	}
+	if r.node == nil {
+		observation := ledgerBalance.String() + "/" + chainBalance.String()
+		if r.observed[key] != observation {
+			r.observed[key] = observation
+			log.Info("balance mismatch at latest chain state, confirm next round", "business", businessId, "chain", r.rpcClient.ChainName, "address", address.Address, "token", token.TokenAddress, "ledgerBalance", ledgerBalance, "chainBalance", chainBalance)
+			return nil, nil
+		}
+	}
+	if difference.Cmp(amountOrZero(token.ReconcileTolerance)) <= 0 {
+		log.Warn("balance mismatch within tolerance", "business", businessId, "chain", r.rpcClient.ChainName, "address", address.Address, "token", token.TokenAddress, "block", head.Number, "ledgerBalance", ledgerBalance, "chainBalance", chainBalance)
+		return nil, nil
+	}
+
+	previous, err := r.db.Reconciles.QueryLatestReconciliation(businessId, r.rpcClient.ChainName, address.Address, token.TokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && previous.LedgerBalance.Cmp(ledgerBalance) == 0 && previous.ChainBalance.Cmp(chainBalance) == 0 {
+		return nil, nil
+	}
+	log.Error("balance mismatch exceeds tolerance", "business", businessId, "chain", r.rpcClient.ChainName, "address", address.Address, "token", token.TokenAddress, "block", head.Number, "ledgerBalance", ledgerBalance, "chainBalance", chainBalance, "tolerance", token.ReconcileTolerance)
+	return &database.Reconciliations{
+		GUID:          uuid.New(),
+		Chain:         r.rpcClient.ChainName,
+		Address:       address.Address,
+		AddressType:   address.AddressType,
+		TokenAddress:  token.TokenAddress,
+		LedgerBalance: ledgerBalance,
+		ChainBalance:  chainBalance,
+		Difference:    difference,
+		Exceeded:      true,
+		Timestamp:     uint64(time.Now().Unix()),
+	}, nil
+}
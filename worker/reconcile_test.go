@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+// testBalanceService 只实现 GetAccount，按合约地址返回余额
+type testBalanceService struct {
+	account.WalletAccountServiceClient
+	balances map[string]string
+}
+
+func (s *testBalanceService) GetAccount(_ context.Context, in *account.AccountRequest, _ ...grpc.CallOption) (*account.AccountResponse, error) {
+	return &account.AccountResponse{Balance: s.balances[in.ContractAddress]}, nil
+}
+
+func TestReconcilerChainBalanceFromAccountService(t *testing.T) {
+	token := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	r := &Reconciler{
+		rpcClient: &rpcclient.WalletChainAccountClient{
+			Ctx:       context.Background(),
+			ChainName: "Ethereum",
+			AccountRpcClient: &testBalanceService{balances: map[string]string{
+				"0x00":         "100",
+				token.String(): "7",
+			}},
+		},
+		observed: make(map[string]string),
+	}
+
+	native, err := r.chainBalance(testUserAddress, common.Address{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "100", native.String())
+
+	erc20, err := r.chainBalance(testUserAddress, token, nil)
+	require.NoError(t, err)
+	require.Equal(t, "7", erc20.String())
+}
+
+func TestReconcilerMismatchWaitsForRepeatAtLatestState(t *testing.T) {
+	r := &Reconciler{
+		rpcClient: &rpcclient.WalletChainAccountClient{ChainName: "Ethereum"},
+		observed:  make(map[string]string),
+	}
+	address := &database.Addresses{Address: testUserAddress, AddressType: database.AddressTypeEOA}
+	token := &database.Tokens{TokenAddress: common.Address{}}
+	head := &rpcclient.BlockHeader{Number: big.NewInt(1)}
+
+	// 第一次出现的差额可能来自还没有同步的区块，不记录
+	reconciliation, err := r.mismatch(testBusinessId, address, token, big.NewInt(100), big.NewInt(90), head)
+	require.NoError(t, err)
+	require.Nil(t, reconciliation)
+	require.Len(t, r.observed, 1)
+
+	// 差额变化时重新等待
+	reconciliation, err = r.mismatch(testBusinessId, address, token, big.NewInt(100), big.NewInt(80), head)
+	require.NoError(t, err)
+	require.Nil(t, reconciliation)
+
+	// 余额一致后清除观察记录
+	reconciliation, err = r.mismatch(testBusinessId, address, token, big.NewInt(100), big.NewInt(100), head)
+	require.NoError(t, err)
+	require.Nil(t, reconciliation)
+	require.Empty(t, r.observed)
+}
@@ -0,0 +1,232 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/common/bigint"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+)
+
+var errBatchNotCommitted = errors.New("previous block batch has not been committed yet")
+
+// continuousHeaders 截取批次中父哈希连续的前缀，链在拉取过程中发生重组时，
+// 断点之后的区块留到下一轮再与已存储的链尖比对
+func (syncer *BaseSynchronizer) continuousHeaders(headers []rpcclient.BlockHeader) []rpcclient.BlockHeader {
+	for i := 1; i < len(headers); i++ {
+		if headers[i].ParentHash != headers[i-1].Hash {
+			log.Warn("batch headers are not continuous", "number", headers[i].Number, "parentHash", headers[i].ParentHash, "expected", headers[i-1].Hash)
+			syncer.blockBatch.Reset(&headers[i-1])
+			return headers[:i]
+		}
+	}
+	return headers
+}
+
+// handleReorg 校验新区块的父哈希与数据库链尖是否一致，不一致时回滚到公共祖先并重置同步游标。
+// 上一批区块尚未落库时返回 errBatchNotCommitted，本批次留到下一轮重试
+func (syncer *BaseSynchronizer) handleReorg(header rpcclient.BlockHeader) (bool, error) {
+	latest, err := syncer.database.Blocks.LatestBlocks(syncer.rpcClient.ChainName)
+	if err != nil {
+		return false, err
+	}
+	if latest == nil {
+		return false, nil
+	}
+	switch new(big.Int).Add(latest.Number, bigint.One).Cmp(header.Number) {
+	case -1:
+		return false, errBatchNotCommitted
+	case 1:
+		return false, nil
+	}
+	if header.ParentHash == latest.Hash {
+		return false, nil
+	}
+
+	log.Warn("chain reorg detected", "chain", syncer.rpcClient.ChainName, "number", header.Number, "parentHash", header.ParentHash, "storedTip", latest.Hash)
+	ancestor, err := syncer.findCommonAncestor(header.ParentHash)
+	if err != nil {
+		return false, err
+	}
+
+	log.Info("rollback to common ancestor", "number", ancestor.Number, "hash", ancestor.Hash)
+	if err := syncer.rollback(ancestor.Number); err != nil {
+		return false, err
+	}
+	syncer.blockBatch.Reset(ancestor)
+	return true, nil
+}
+
+// findCommonAncestor 从新链的父区块开始按哈希向前回溯，直到与数据库中同高度的区块哈希一致
+func (syncer *BaseSynchronizer) findCommonAncestor(hash common.Hash) (*rpcclient.BlockHeader, error) {
+	for {
+		header, err := syncer.rpcClient.GetBlockHeaderByHash(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		stored, err := syncer.database.Blocks.BlockHeaderByNumber(syncer.rpcClient.ChainName, header.Number)
+		if err != nil {
+			return nil, err
+		}
+		if stored == nil || stored.Hash == header.Hash {
+			return header, nil
+		}
+
+		log.Warn("orphaned block", "number", stored.Number, "storedHash", stored.Hash, "canonicalHash", header.Hash)
+		hash = header.ParentHash
+	}
+}
+
+// rollback 撤销所有业务方在本链 number 之后的充值、交易流水、余额变动，并把提现和内部交易状态退回到已广播
+func (syncer *BaseSynchronizer) rollback(number *big.Int) error {
+	businessList, err := syncer.database.Business.QueryBusinessList()
+	if err != nil {
+		log.Error("query business list failed", "err", err)
+		return err
+	}
+
+	chain := syncer.rpcClient.ChainName
+	return syncer.database.Transaction(func(tx *database.DB) error {
+		for _, business := range businessList {
+			transactions, err := tx.Trasactions.QueryTransactionsAfterBlock(business.BusinessUid, chain, number)
+			if err != nil {
+				log.Error("query orphaned transactions failed", "businessId", business.BusinessUid, "err", err)
+				return err
+			}
+
+			var (
+				balances  []*database.TokenBalance
+				nfts      []*database.NftTransfer
+				relocks   []*database.Balances
+				withdraws []*database.Withdraws
+				internals []*database.Internals
+			)
+			for _, transaction := range transactions {
+				failed := txFailed(transaction.Status)
+				switch transaction.TxType {
+				case database.TxTypeDeposit, database.TxTypeInternalTransfer:
+				case database.TxTypeWithdraw:
+					withdraws = append(withdraws, &database.Withdraws{TxHash: transaction.Hash})
+				case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot, database.TxTypeGasTopUp:
+					internals = append(internals, &database.Internals{TxHash: transaction.Hash})
+				default:
+					continue
+				}
+				// 失败的交易没有入账，但钱包发出的提现和内部交易在失败时释放了锁定余额，回退到已广播时需要重新锁定
+				if failed {
+					relock, err := failedLockBalance(tx, business.BusinessUid, transaction)
+					if err != nil {
+						return err
+					}
+					if relock != nil {
+						relocks = append(relocks, relock)
+					}
+					continue
+				}
+				if transaction.TokenId != "" && transaction.TokenId != "0x00" {
+					token, err := tx.Tokens.TokensInfoByAddress(business.BusinessUid, transaction.TokenAddress.String())
+					if err != nil {
+						log.Error("query token info failed", "tokenAddress", transaction.TokenAddress, "err", err)
+						return err
+					}
+					if token != nil && token.TokenType.IsNft() {
+						nfts = append(nfts, &database.NftTransfer{
+							Chain:        transaction.Chain,
+							FromAddress:  transaction.FromAddress,
+							ToAddress:    transaction.ToAddress,
+							TokenAddress: transaction.TokenAddress,
+							TokenId:      transaction.TokenId,
+							TokenType:    token.TokenType,
+							Amount:       transaction.Amount,
+							TxType:       transaction.TxType,
+						})
+						continue
+					}
+				}
+				balance := &database.TokenBalance{
+					FromAddress:  transaction.FromAddress,
+					ToAddress:    transaction.ToAddress,
+					TokenAddress: transaction.TokenAddress,
+					Balance:      transaction.Amount,
+					TxType:       transaction.TxType,
+					SourceGuid:   transaction.GUID,
+					Chain:        transaction.Chain,
+					BlockNumber:  transaction.BlockNumber,
+				}
+				if transaction.TransferIndex == 0 {
+					balance.Fee = transaction.Fee
+				}
+				balances = append(balances, balance)
+			}
+
+			log.Info("rollback business flow", "businessId", business.BusinessUid, "chain", chain, "number", number, "txn", len(transactions))
+			if err := tx.Balances.RollbackBalances(business.BusinessUid, balances); err != nil {
+				log.Error("rollback balances failed", "err", err)
+				return err
+			}
+			if err := tx.Nfts.RollbackTransfers(business.BusinessUid, nfts); err != nil {
+				log.Error("rollback nft ownerships failed", "err", err)
+				return err
+			}
+			if err := tx.Balances.UpdateBalanceListByTwoAddress(business.BusinessUid, relocks); err != nil {
+				log.Error("relock balances failed", "err", err)
+				return err
+			}
+			if err := tx.Withdraws.UpdateWithdrawStatusByTxHash(business.BusinessUid, database.TxStatusBoradcasted, withdraws); err != nil {
+				log.Error("rollback withdraws failed", "err", err)
+				return err
+			}
+			if err := tx.Internals.UpdateInternalStatusByTxHash(business.BusinessUid, database.TxStatusBoradcasted, internals); err != nil {
+				log.Error("rollback internals failed", "err", err)
+				return err
+			}
+			if err := tx.Deposits.DeleteDepositsAfterBlock(business.BusinessUid, chain, number); err != nil {
+				log.Error("rollback deposits failed", "err", err)
+				return err
+			}
+			if err := tx.Trasactions.DeleteTransactionsAfterBlock(business.BusinessUid, chain, number); err != nil {
+				log.Error("rollback transactions failed", "err", err)
+				return err
+			}
+		}
+
+		return tx.Blocks.DeleteBlocksAfter(chain, number)
+	})
+}
+
+// failedLockBalance 查询失败的提现或内部交易在失败时释放的锁定金额，不是钱包发出的交易返回 nil
+func failedLockBalance(tx *database.DB, businessId string, transaction *database.Transactions) (*database.Balances, error) {
+	released := func(status database.TxStatus) bool {
+		return status == database.TxStatusFailed || status == database.TxStatusFailedNotified
+	}
+
+	switch transaction.TxType {
+	case database.TxTypeWithdraw:
+		withdraw, err := tx.Withdraws.QueryWithdrawsByHash(businessId, transaction.Hash)
+		if err != nil {
+			log.Error("query failed withdraw failed", "txHash", transaction.Hash, "err", err)
+			return nil, err
+		}
+		if withdraw == nil || !released(withdraw.Status) {
+			return nil, nil
+		}
+		return &database.Balances{Address: withdraw.FromAddress, TokenAddress: withdraw.TokenAddress, LockBalance: withdraw.Amount, SourceGuid: withdraw.GUID, Chain: transaction.Chain, BlockNumber: transaction.BlockNumber}, nil
+	case database.TxTypeCollection, database.TxTypeHot2Cold, database.TxTypeCold2Hot, database.TxTypeGasTopUp:
+		internal, err := tx.Internals.QueryInternalByTxHash(businessId, transaction.Hash)
+		if err != nil {
+			log.Error("query failed internal failed", "txHash", transaction.Hash, "err", err)
+			return nil, err
+		}
+		if internal == nil || !released(internal.Status) {
+			return nil, nil
+		}
+		return &database.Balances{Address: internal.FromAddress, TokenAddress: internal.TokenAddress, LockBalance: internal.Amount, SourceGuid: internal.GUID, Chain: transaction.Chain, BlockNumber: transaction.BlockNumber}, nil
+	default:
+		return nil, nil
+	}
+}
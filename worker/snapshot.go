@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/common/retry"
+	"github.com/JokingLove/multichain-sync-account/common/tasks"
+	"github.com/JokingLove/multichain-sync-account/config"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+)
+
+// snapshotCheckInterval 检查是否到达快照时间点的间隔
+const snapshotCheckInterval = time.Minute
+
+// Snapshotter 按 SnapshotInterval 对齐的时间点（UTC，默认每天零点）为每个业务方写入余额快照，
+// 快照为该时间点之前最后一个区块上的余额，作为日终余额归档
+type Snapshotter struct {
+	rpcClient      *rpcclient.WalletChainAccountClient
+	db             *database.DB
+	interval       time.Duration
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+}
+
+func NewSnapshotter(cfg *config.ChainNodeConfig, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Snapshotter, error) {
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Snapshotter{
+		rpcClient:      rpcClient,
+		db:             db,
+		interval:       cfg.SnapshotInterval,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in %s snapshotter: %w", rpcClient.ChainName, err))
+		}},
+		ticker: time.NewTicker(snapshotCheckInterval),
+	}, nil
+}
+
+func (s *Snapshotter) Close() error {
+	var result error
+	s.resourceCancel()
+	s.ticker.Stop()
+	log.Info("stop snapshotter......", "chain", s.rpcClient.ChainName)
+	if err := s.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await snapshotter: %w", err))
+		return result
+	}
+	log.Info("stop snapshotter success", "chain", s.rpcClient.ChainName)
+	return nil
+}
+
+func (s *Snapshotter) Start() error {
+	log.Info("starting snapshotter...", "chain", s.rpcClient.ChainName, "interval", s.interval)
+	s.tasks.Go(func() error {
+		for {
+			select {
+			case <-s.ticker.C:
+				businessList, err := s.db.Business.QueryBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				for _, business := range businessList {
+					if err := s.snapshot(business.BusinessUid); err != nil {
+						log.Error("take balance snapshot fail", "business", business.BusinessUid, "err", err)
+					}
+				}
+			case <-s.resourceCtx.Done():
+				log.Info("stop snapshotter in worker", "chain", s.rpcClient.ChainName)
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// snapshot 最近一次快照早于当前周期的起点时写入新的快照，快照时间记为周期起点，
+// 每天零点的快照即为前一天的日终余额
+func (s *Snapshotter) snapshot(businessId string) error {
+	boundary := time.Now().UTC().Truncate(s.interval)
+	latest, err := s.db.Snapshots.LatestSnapshotTime(businessId, s.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	if latest >= uint64(boundary.Unix()) {
+		return nil
+	}
+
+	// 同步到周期起点之后的区块时，起点之前的区块才全部入账
+	latestHeader, err := s.db.Blocks.LatestBlocks(s.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+	if latestHeader == nil || latestHeader.Timestamp < uint64(boundary.Unix()) {
+		log.Debug("chain not synced to snapshot time, wait", "chain", s.rpcClient.ChainName, "boundary", boundary)
+		return nil
+	}
+	header, err := s.db.Blocks.BlockHeaderByTime(s.rpcClient.ChainName, uint64(boundary.Unix()))
+	if err != nil {
+		return err
+	}
+	if header == nil {
+		log.Debug("chain has no synced block before snapshot time, skip snapshot", "chain", s.rpcClient.ChainName)
+		return nil
+	}
+
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	count, err := retry.Do[int64](s.resourceCtx, 10, retryStrategy, func() (int64, error) {
+		return s.db.Snapshots.TakeSnapshot(businessId, s.rpcClient.ChainName, header.Number, uint64(boundary.Unix()))
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("take balance snapshot", "business", businessId, "chain", s.rpcClient.ChainName, "number", header.Number, "balances", count)
+	return nil
+}
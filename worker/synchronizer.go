@@ -2,27 +2,50 @@ package worker
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/JokingLove/multichain-sync-account/common/clock"
+	"github.com/JokingLove/multichain-sync-account/common/retry"
 	"github.com/JokingLove/multichain-sync-account/database"
 	"github.com/JokingLove/multichain-sync-account/rpcclient"
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
 )
 
 type Transaction struct {
 	BusinessId     string
 	BlockNumber    *big.Int
-	FromAddress    string
-	ToAddress      string
+	FromAddress    database.Address
+	ToAddress      database.Address
 	Hash           string
+	Index          uint32 // 转账在交易内的序号：账户模型链为 Transfer 日志序号，UTXO 链为输出序号
 	TokenAddress   string
 	ContractWallet string
 	TxType         database.TransactionType
+
+	// NFT 转账的代币类型和 token id，Amount 为转移数量
+	TokenType database.TokenType
+	TokenId   string
+
+	// UTXO 链按输出拆分记账，Amount 为该输出的金额，TxMsg 为同步时已拉取的交易详情
+	Amount string
+	TxMsg  *account.TxMessage
+
+	// 共享地址充值的 memo，Suspense 表示 memo 缺失或无法识别
+	Memo     string
+	Suspense bool
+
+	// Quarantine 充值的代币不在业务方的白名单中，或 NFT 充值解析不出 token id
+	Quarantine bool
+
+	// Dust 充值金额低于代币的最小充值金额
+	Dust bool
 }
 
 type Config struct {
@@ -35,12 +58,16 @@ type Config struct {
 type BaseSynchronizer struct {
 	loopInterval     time.Duration
 	headerBufferSize uint64
+	fetchWorkers     int
+	utxo             bool
+	addressFormat    database.AddressFormat
 
-	businessChannels chan map[string]*TransactionChannel
+	businessChannels chan *BusinessBatch
 
 	rpcClient  *rpcclient.WalletChainAccountClient
 	blockBatch *rpcclient.BatchBlock
 	database   *database.DB
+	addresses  *AddressIndex
 
 	headers []rpcclient.BlockHeader
 	worker  *clock.LoopFn
@@ -52,6 +79,13 @@ type TransactionChannel struct {
 	Transactions []*Transaction
 }
 
+// BusinessBatch 一批同步完成的区块，区块头与各业务方交易在同一个数据库事务中落库，
+// 落库后的最新区块即为下次重启时的同步起点
+type BusinessBatch struct {
+	Blocks           []database.Blocks
+	BusinessChannels map[string]*TransactionChannel
+}
+
 func (syncer *BaseSynchronizer) Start() error {
 	if syncer.worker != nil {
 		return errors.New("worker is already started")
@@ -65,7 +99,7 @@ func (syncer *BaseSynchronizer) Start() error {
 }
 
 func (syncer *BaseSynchronizer) Close() error {
-	if syncer.worker != nil {
+	if syncer.worker == nil {
 		return nil
 	}
 	return syncer.worker.Close()
@@ -78,14 +112,14 @@ func (syncer *BaseSynchronizer) tick(_ context.Context) {
 		newHeaders, err := syncer.blockBatch.NextHeaders(syncer.headerBufferSize)
 		if err != nil {
 			log.Error("error querying for headers", "err", err)
-		} else if len(newHeaders) > 0 {
+		} else if len(newHeaders) == 0 {
 			log.Warn("no new headers, syncer at head ?")
 		} else {
 			syncer.headers = newHeaders
 		}
 	}
 	err := syncer.processBatch(syncer.headers)
-	if err != nil {
+	if err == nil {
 		syncer.headers = nil
 	}
 }
@@ -95,86 +129,83 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 		return nil
 	}
 
+	headers = syncer.continuousHeaders(headers)
+	reorged, err := syncer.handleReorg(headers[0])
+	if errors.Is(err, errBatchNotCommitted) {
+		log.Info("waiting for previous batch to be committed", "number", headers[0].Number)
+		return err
+	} else if err != nil {
+		log.Error("handle chain reorg failed", "err", err)
+		return err
+	}
+	if reorged {
+		return nil
+	}
+
+	blockTxList, err := syncer.fetchBlockTransactions(headers)
+	if err != nil {
+		log.Error("fetch block transactions failed", "err", err)
+		return err
+	}
+
+	var utxoTxs map[string]*account.TxMessage
+	if syncer.utxo {
+		utxoTxs, err = syncer.fetchUtxoTransactions(blockTxList)
+		if err != nil {
+			log.Error("fetch utxo transactions failed", "err", err)
+			return err
+		}
+	}
+
+	businessList, err := syncer.database.Business.QueryBusinessList()
+	if err != nil {
+		log.Error(" query business list failed", "err", err)
+		return err
+	}
+	for _, business := range businessList {
+		if err := syncer.addresses.Refresh(business.BusinessUid); err != nil {
+			return err
+		}
+	}
+
 	businessTxChannel := make(map[string]*TransactionChannel)
 	blockHeaders := make([]database.Blocks, len(headers))
 
 	for i := range headers {
 		log.Info("Sync block data", "height", headers[i].Number)
 		blockHeaders[i] = database.Blocks{
+			Chain:      syncer.rpcClient.ChainName,
 			Hash:       headers[i].Hash,
 			ParentHash: headers[i].ParentHash,
 			Number:     headers[i].Number,
 			Timestamp:  headers[i].Timestamp,
 		}
-		txList, err := syncer.rpcClient.GetBlockInfo(headers[i].Number)
-		if err != nil {
-			log.Error(" get block info failed", "err", err)
-			return err
-		}
-
-		businessList, err := syncer.database.Business.QueryBusinessList()
-		if err != nil {
-			log.Error(" query business list failed", "err", err)
-			return err
-		}
+		txList := blockTxList[i]
+		indexes, counts := transferIndexes(txList)
+		txMsgs := make(map[string]*account.TxMessage)
 
 		for _, business := range businessList {
 			var businessTransactions []*Transaction
-			for _, tx := range txList {
-				toAddress := common.HexToAddress(tx.To)
-				fromAddress := common.HexToAddress(tx.From)
-				existToAddress, toAddressType := syncer.database.Addresses.AddressExists(business.BusinessUid, &toAddress)
-				existFromAddress, fromAddressType := syncer.database.Addresses.AddressExists(business.BusinessUid, &fromAddress)
-				if !existToAddress && !existFromAddress {
+			classifier := ClassifierByName(business.Classifier)
+			for j, tx := range txList {
+				if syncer.utxo {
+					businessTransactions = append(businessTransactions, syncer.utxoTransfers(business.BusinessUid, classifier, headers[i].Number, tx, utxoTxs[tx.Hash])...)
 					continue
 				}
 
-				log.Info("Found transaction ", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-				txItem := &Transaction{
-					BusinessId:     business.BusinessUid,
-					BlockNumber:    headers[i].Number,
-					FromAddress:    tx.From,
-					ToAddress:      tx.To,
-					Hash:           tx.Hash,
-					TokenAddress:   tx.TokenAddress,
-					ContractWallet: tx.ContractWallet,
-					TxType:         database.TxTypeUnknown,
-				}
-
-				/*
-				 * If the 'from' address is an external address and the 'to' address is an internal user address, it is a deposit; call the callback interface to notifier the business side.
-				 * If the 'from' address is a user address and the 'to' address is a hot wallet address, it is consolidation; call the callback interface to notifier the business side.
-				 * If the 'from' address is a hot wallet address and the 'to' address is an external user address, it is a withdrawal; call the callback interface to notifier the business side.
-				 * If the 'from' address is a hot wallet address and the 'to' address is a cold wallet address, it is a hot-to-cold transfer; call the callback interface to notifier the business side.
-				 * If the 'from' address is a cold wallet address and the 'to' address is a hot wallet address, it is a cold-to-hot transfer; call the callback interface to notifier the business side.
-				 */
-				if !existFromAddress && (existToAddress && toAddressType == database.AddressTypeEOA) { // 充值
-					log.Info("Found deposit transaction ", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeDeposit
-				}
-				// 提现
-				if (existFromAddress && fromAddressType == database.AddressTypeHot) && !existToAddress {
-					log.Info("Found withdraw transaction ", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeWithdraw
-				}
-
-				// 归集
-				if (existFromAddress && fromAddressType == database.AddressTypeEOA) && (existToAddress && toAddressType == database.AddressTypeHot) {
-					log.Info("Found collection transaction ", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeCollection
+				transfers, err := syncer.accountTransfers(business.BusinessUid, classifier, headers[i].Number, tx, indexes[j], counts[tx.Hash] == 1, txMsgs)
+				if err != nil {
+					return err
 				}
-				// 热转冷
-				if (existFromAddress && fromAddressType == database.AddressTypeHot) && (existToAddress && toAddressType == database.AddressTypeCold) {
-					log.Info("Found hot2cold transaction ", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeHot2Cold
-				}
-				// 冷转热
-				if (existFromAddress && fromAddressType == database.AddressTypeCold) && (existToAddress && toAddressType == database.AddressTypeHot) {
-					log.Info("Found cold2hot transaction ", "txHash", tx.Hash, "from", fromAddress, "to", toAddress)
-					txItem.TxType = database.TxTypeCold2Hot
+				for _, txItem := range transfers {
+					if txItem.TxType == database.TxTypeDeposit && syncer.addresses.SharedAddress(business.BusinessUid, txItem.ToAddress) {
+						if err := syncer.attributeMemo(txItem); err != nil {
+							return err
+						}
+					}
 				}
 
-				businessTransactions = append(businessTransactions, txItem)
+				businessTransactions = append(businessTransactions, transfers...)
 			}
 
 			if len(businessTransactions) > 0 {
@@ -191,17 +222,146 @@ func (syncer *BaseSynchronizer) processBatch(headers []rpcclient.BlockHeader) er
 		}
 	}
 
-	if len(blockHeaders) > 0 {
-		log.Info("Store block headers success", "totalBlockHeader", len(blockHeaders))
-		if err := syncer.database.Blocks.StoreBlocks(blockHeaders); err != nil {
-			return err
+	log.Info("business tx channel", "businessTxChannel", businessTxChannel, "map length", len(businessTxChannel))
+	syncer.businessChannels <- &BusinessBatch{
+		Blocks:           blockHeaders,
+		BusinessChannels: businessTxChannel,
+	}
+
+	return nil
+}
+
+// fetchBlockTransactions 并发拉取区块交易列表，并发数由 fetchWorkers 限制，
+// 结果按 headers 的顺序返回，保证后续分类输出与区块高度顺序一致
+func (syncer *BaseSynchronizer) fetchBlockTransactions(headers []rpcclient.BlockHeader) ([][]*account.BlockInfoTransactionList, error) {
+	blockTxList := make([][]*account.BlockInfoTransactionList, len(headers))
+
+	group, ctx := errgroup.WithContext(context.Background())
+	group.SetLimit(max(syncer.fetchWorkers, 1))
+	for i := range headers {
+		group.Go(func() error {
+			retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+			txList, err := retry.Do[[]*account.BlockInfoTransactionList](ctx, 5, retryStrategy, func() ([]*account.BlockInfoTransactionList, error) {
+				return syncer.rpcClient.GetBlockInfo(headers[i].Number)
+			})
+			if err != nil {
+				log.Error("get block info failed", "number", headers[i].Number, "err", err)
+				return err
+			}
+			blockTxList[i] = txList
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return blockTxList, nil
+}
+
+// fetchUtxoTransactions UTXO 链的区块交易列表只有单个 from/to，需要拉取每笔交易的完整输入输出
+func (syncer *BaseSynchronizer) fetchUtxoTransactions(blockTxList [][]*account.BlockInfoTransactionList) (map[string]*account.TxMessage, error) {
+	var hashes []string
+	for _, txList := range blockTxList {
+		for _, tx := range txList {
+			hashes = append(hashes, tx.Hash)
 		}
 	}
 
-	log.Info("business tx channel", "businessTxChannel", businessTxChannel, "map length", len(businessTxChannel))
-	if len(businessTxChannel) > 0 {
-		syncer.businessChannels <- businessTxChannel
+	var mu sync.Mutex
+	txMessages := make(map[string]*account.TxMessage, len(hashes))
+
+	group, ctx := errgroup.WithContext(context.Background())
+	group.SetLimit(max(syncer.fetchWorkers, 1))
+	for _, hash := range hashes {
+		group.Go(func() error {
+			retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+			txMsg, err := retry.Do[*account.TxMessage](ctx, 5, retryStrategy, func() (*account.TxMessage, error) {
+				txMsg, err := syncer.rpcClient.GetTransactionByHash(hash)
+				if err == nil && txMsg == nil {
+					err = fmt.Errorf("transaction not found: %s", hash)
+				}
+				return txMsg, err
+			})
+			if err != nil {
+				log.Error("get utxo transaction failed", "txHash", hash, "err", err)
+				return err
+			}
+			mu.Lock()
+			txMessages[hash] = txMsg
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
 	}
+	return txMessages, nil
+}
 
-	return nil
+// utxoTransfers 把一笔 UTXO 交易按输出拆分：付款方取输入中第一个属于业务方的地址，
+// 每个转给业务方或由业务方转出的输出单独分类；转回输入地址的输出视为找零
+func (syncer *BaseSynchronizer) utxoTransfers(businessId string, classifier Classifier, blockNumber *big.Int, tx *account.BlockInfoTransactionList, txMsg *account.TxMessage) []*Transaction {
+	if txMsg == nil {
+		return nil
+	}
+
+	var (
+		from        AddressRole
+		fromAddress database.Address
+	)
+	inputs := make(map[database.Address]struct{}, len(txMsg.Froms))
+	for _, input := range txMsg.Froms {
+		address := syncer.addressFormat.Normalize(input.Address)
+		inputs[address] = struct{}{}
+		if from.Exists {
+			continue
+		}
+		if exists, addressType := syncer.addresses.Lookup(businessId, address); exists {
+			from = AddressRole{Exists: true, Type: addressType}
+			fromAddress = address
+		}
+	}
+	if fromAddress == "" && len(txMsg.Froms) > 0 {
+		fromAddress = syncer.addressFormat.Normalize(txMsg.Froms[0].Address)
+	}
+
+	var transfers []*Transaction
+	for i, output := range txMsg.Tos {
+		if i >= len(txMsg.Values) {
+			log.Warn("utxo output without value", "txHash", txMsg.Hash, "index", i)
+			break
+		}
+		toAddress := syncer.addressFormat.Normalize(output.Address)
+		exists, addressType := syncer.addresses.Lookup(businessId, toAddress)
+		to := AddressRole{Exists: exists, Type: addressType}
+		if !from.Exists && !to.Exists {
+			continue
+		}
+
+		txType := database.TxTypeChange
+		if _, change := inputs[toAddress]; !(from.Exists && change) {
+			var forward bool
+			txType, forward = classifier.Classify(from, to, tx)
+			if !forward {
+				log.Info("Drop utxo output by classifier", "txHash", txMsg.Hash, "index", i, "to", toAddress)
+				continue
+			}
+		}
+
+		log.Info("Found utxo output", "txHash", txMsg.Hash, "index", i, "from", fromAddress, "to", toAddress, "txType", txType)
+		transfers = append(transfers, &Transaction{
+			BusinessId:     businessId,
+			BlockNumber:    blockNumber,
+			FromAddress:    fromAddress,
+			ToAddress:      toAddress,
+			Hash:           txMsg.Hash,
+			Index:          uint32(i),
+			TokenAddress:   tx.TokenAddress,
+			ContractWallet: tx.ContractWallet,
+			TxType:         txType,
+			Amount:         txMsg.Values[i].Value,
+			TxMsg:          txMsg,
+		})
+	}
+	return transfers
 }
@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/JokingLove/multichain-sync-account/common/retry"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+// transferIndexes 链账户服务对一笔交易中的每个 Transfer 日志各返回一条记录，
+// 按返回顺序给同一交易哈希下的记录编号，作为转账在交易内的 index
+func transferIndexes(txList []*account.BlockInfoTransactionList) ([]uint32, map[string]int) {
+	indexes := make([]uint32, len(txList))
+	counts := make(map[string]int, len(txList))
+	for i, tx := range txList {
+		indexes[i] = uint32(counts[tx.Hash])
+		counts[tx.Hash]++
+	}
+	return indexes, counts
+}
+
+// accountTransfers 账户模型链的一条转账记录；交易只返回一条记录但 TxMessage 中有多个收款方时（批量转账），
+// 按收款方逐个拆分并分别分类，收款方在 TxMessage 中的下标作为 index。
+// 批量转账的 To 是合约本身，付款方和合约都不属于业务方时也要先展开收款方再决定是否丢弃；
+// txMsgs 缓存本批次已拉取的 TxMessage，同一笔交易在多个业务方之间只查询一次
+func (syncer *BaseSynchronizer) accountTransfers(businessId string, classifier Classifier, blockNumber *big.Int, tx *account.BlockInfoTransactionList, index uint32, single bool, txMsgs map[string]*account.TxMessage) ([]*Transaction, error) {
+	fromAddress := syncer.addressFormat.Normalize(tx.From)
+	existFrom, fromAddressType := syncer.addresses.Lookup(businessId, fromAddress)
+	from := AddressRole{Exists: existFrom, Type: fromAddressType}
+
+	toAddress := syncer.addressFormat.Normalize(tx.To)
+	existTo, _ := syncer.addresses.Lookup(businessId, toAddress)
+	if !existFrom && !existTo && !single {
+		return nil, nil
+	}
+
+	var txMsg *account.TxMessage
+	if single {
+		txMsg = txMsgs[tx.Hash]
+		if txMsg == nil {
+			var err error
+			txMsg, err = syncer.getTransaction(tx.Hash)
+			if err != nil {
+				log.Error("get transaction failed", "txHash", tx.Hash, "err", err)
+				return nil, err
+			}
+			txMsgs[tx.Hash] = txMsg
+		}
+	}
+	if txMsg == nil || len(txMsg.Tos) <= 1 {
+		if !existFrom && !existTo {
+			return nil, nil
+		}
+		transfer := syncer.classifyTransfer(businessId, classifier, blockNumber, tx, from, fromAddress, toAddress, index, tx.Amount)
+		if transfer == nil {
+			return nil, nil
+		}
+		transfer.TxMsg = txMsg
+		return []*Transaction{transfer}, nil
+	}
+
+	var transfers []*Transaction
+	for i, output := range txMsg.Tos {
+		if i >= len(txMsg.Values) {
+			log.Warn("transfer without value", "txHash", txMsg.Hash, "index", i)
+			break
+		}
+		transfer := syncer.classifyTransfer(businessId, classifier, blockNumber, tx, from, fromAddress, syncer.addressFormat.Normalize(output.Address), uint32(i), txMsg.Values[i].Value)
+		if transfer == nil {
+			continue
+		}
+		transfer.TxMsg = txMsg
+		transfers = append(transfers, transfer)
+	}
+	return transfers, nil
+}
+
+func (syncer *BaseSynchronizer) classifyTransfer(businessId string, classifier Classifier, blockNumber *big.Int, tx *account.BlockInfoTransactionList, from AddressRole, fromAddress, toAddress database.Address, index uint32, amount string) *Transaction {
+	exists, addressType := syncer.addresses.Lookup(businessId, toAddress)
+	to := AddressRole{Exists: exists, Type: addressType}
+	if !from.Exists && !to.Exists {
+		return nil
+	}
+
+	txType, forward := classifier.Classify(from, to, tx)
+	if !forward {
+		log.Info("Drop transaction by classifier", "txHash", tx.Hash, "index", index, "from", fromAddress, "to", toAddress)
+		return nil
+	}
+
+	log.Info("Found transaction ", "txHash", tx.Hash, "index", index, "from", fromAddress, "to", toAddress, "txType", txType)
+	return &Transaction{
+		BusinessId:     businessId,
+		BlockNumber:    blockNumber,
+		FromAddress:    fromAddress,
+		ToAddress:      toAddress,
+		Hash:           tx.Hash,
+		Index:          index,
+		TokenAddress:   tx.TokenAddress,
+		ContractWallet: tx.ContractWallet,
+		TxType:         txType,
+		Amount:         amount,
+	}
+}
+
+func (syncer *BaseSynchronizer) getTransaction(hash string) (*account.TxMessage, error) {
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	return retry.Do[*account.TxMessage](context.Background(), 5, retryStrategy, func() (*account.TxMessage, error) {
+		txMsg, err := syncer.rpcClient.GetTransactionByHash(hash)
+		if err == nil && txMsg == nil {
+			err = fmt.Errorf("transaction not found: %s", hash)
+		}
+		return txMsg, err
+	})
+}
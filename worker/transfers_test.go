@@ -0,0 +1,218 @@
+package worker
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+	"github.com/JokingLove/multichain-sync-account/rpcclient/chain-account/account"
+)
+
+const testBusinessId = "test"
+
+var (
+	testHotAddress  = database.AddressFormatEVM.Normalize("0x1000000000000000000000000000000000000001")
+	testUserAddress = database.AddressFormatEVM.Normalize("0x2000000000000000000000000000000000000002")
+	testUser2       = database.AddressFormatEVM.Normalize("0x3000000000000000000000000000000000000003")
+	testExternal    = database.AddressFormatEVM.Normalize("0x4000000000000000000000000000000000000004")
+)
+
+// testAccountService 只实现 GetTxByHash，其他接口调用时 panic
+type testAccountService struct {
+	account.WalletAccountServiceClient
+	txs map[string]*account.TxMessage
+}
+
+func (s *testAccountService) GetTxByHash(_ context.Context, in *account.TxHashRequest, _ ...grpc.CallOption) (*account.TxHashResponse, error) {
+	return &account.TxHashResponse{Tx: s.txs[in.Hash]}, nil
+}
+
+func newTestSynchronizer(txs map[string]*account.TxMessage) *BaseSynchronizer {
+	addresses := NewAddressIndex(nil, "Ethereum")
+	addresses.addresses[testBusinessId] = map[database.Address]database.AddressType{
+		testHotAddress:  database.AddressTypeHot,
+		testUserAddress: database.AddressTypeEOA,
+		testUser2:       database.AddressTypeEOA,
+	}
+	return &BaseSynchronizer{
+		addressFormat: database.AddressFormatEVM,
+		addresses:     addresses,
+		rpcClient: &rpcclient.WalletChainAccountClient{
+			Ctx:              context.Background(),
+			AccountRpcClient: &testAccountService{txs: txs},
+		},
+	}
+}
+
+func TestTransferIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		hashes  []string
+		indexes []uint32
+		counts  map[string]int
+	}{
+		{
+			name:    "empty",
+			hashes:  nil,
+			indexes: []uint32{},
+			counts:  map[string]int{},
+		},
+		{
+			name:    "one transfer per tx",
+			hashes:  []string{"0xa", "0xb"},
+			indexes: []uint32{0, 0},
+			counts:  map[string]int{"0xa": 1, "0xb": 1},
+		},
+		{
+			name:    "multi transfer tx",
+			hashes:  []string{"0xa", "0xa", "0xa"},
+			indexes: []uint32{0, 1, 2},
+			counts:  map[string]int{"0xa": 3},
+		},
+		{
+			name:    "interleaved txs",
+			hashes:  []string{"0xa", "0xb", "0xa", "0xb", "0xc"},
+			indexes: []uint32{0, 0, 1, 1, 0},
+			counts:  map[string]int{"0xa": 2, "0xb": 2, "0xc": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txList := make([]*account.BlockInfoTransactionList, len(tt.hashes))
+			for i, hash := range tt.hashes {
+				txList[i] = &account.BlockInfoTransactionList{Hash: hash}
+			}
+			indexes, counts := transferIndexes(txList)
+			require.Equal(t, tt.indexes, indexes)
+			require.Equal(t, tt.counts, counts)
+		})
+	}
+}
+
+func TestAccountTransfers(t *testing.T) {
+	batchTx := &account.TxMessage{
+		Hash: "0xbatch",
+		Tos: []*account.Address{
+			{Address: testUserAddress.String()},
+			{Address: testExternal.String()},
+			{Address: testUser2.String()},
+		},
+		Values: []*account.Value{{Value: "1"}, {Value: "2"}, {Value: "3"}},
+	}
+	shortValuesTx := &account.TxMessage{
+		Hash: "0xshort",
+		Tos: []*account.Address{
+			{Address: testUserAddress.String()},
+			{Address: testUser2.String()},
+		},
+		Values: []*account.Value{{Value: "1"}},
+	}
+	singleTx := &account.TxMessage{
+		Hash:   "0xsingle",
+		Tos:    []*account.Address{{Address: testUserAddress.String()}},
+		Values: []*account.Value{{Value: "5"}},
+	}
+	externalTx := &account.TxMessage{
+		Hash:   "0xexternal",
+		Tos:    []*account.Address{{Address: "0x5000000000000000000000000000000000000005"}},
+		Values: []*account.Value{{Value: "1"}},
+	}
+	syncer := newTestSynchronizer(map[string]*account.TxMessage{
+		batchTx.Hash:       batchTx,
+		shortValuesTx.Hash: shortValuesTx,
+		singleTx.Hash:      singleTx,
+		externalTx.Hash:    externalTx,
+	})
+	multisendContract := "0x6000000000000000000000000000000000000006"
+
+	type transfer struct {
+		to     database.Address
+		index  uint32
+		amount string
+		txType database.TransactionType
+	}
+	tests := []struct {
+		name   string
+		tx     *account.BlockInfoTransactionList
+		index  uint32
+		single bool
+		want   []transfer
+	}{
+		{
+			name:  "external to external",
+			tx:    &account.BlockInfoTransactionList{Hash: "0xother", From: testExternal.String(), To: "0x5000000000000000000000000000000000000005", Amount: "1"},
+			index: 0,
+			want:  nil,
+		},
+		{
+			name:   "single transfer tx between external addresses",
+			tx:     &account.BlockInfoTransactionList{Hash: externalTx.Hash, From: testExternal.String(), To: "0x5000000000000000000000000000000000000005", Amount: "1"},
+			single: true,
+			want:   nil,
+		},
+		{
+			name:   "batch transfer through an untracked contract",
+			tx:     &account.BlockInfoTransactionList{Hash: batchTx.Hash, From: testExternal.String(), To: multisendContract, Amount: "6"},
+			single: true,
+			want: []transfer{
+				{to: testUserAddress, index: 0, amount: "1", txType: database.TxTypeDeposit},
+				{to: testUser2, index: 2, amount: "3", txType: database.TxTypeDeposit},
+			},
+		},
+		{
+			name:  "one of several transfers keeps its log index",
+			tx:    &account.BlockInfoTransactionList{Hash: "0xmulti", From: testExternal.String(), To: testUserAddress.String(), Amount: "7"},
+			index: 3,
+			want:  []transfer{{to: testUserAddress, index: 3, amount: "7", txType: database.TxTypeDeposit}},
+		},
+		{
+			name:   "single transfer tx with one recipient",
+			tx:     &account.BlockInfoTransactionList{Hash: singleTx.Hash, From: testExternal.String(), To: testUserAddress.String(), Amount: "5"},
+			single: true,
+			want:   []transfer{{to: testUserAddress, index: 0, amount: "5", txType: database.TxTypeDeposit}},
+		},
+		{
+			name:   "batch transfer split by recipient",
+			tx:     &account.BlockInfoTransactionList{Hash: batchTx.Hash, From: testHotAddress.String(), To: testUserAddress.String(), Amount: "6"},
+			single: true,
+			want: []transfer{
+				{to: testUserAddress, index: 0, amount: "1", txType: database.TxTypeGasTopUp},
+				{to: testExternal, index: 1, amount: "2", txType: database.TxTypeWithdraw},
+				{to: testUser2, index: 2, amount: "3", txType: database.TxTypeGasTopUp},
+			},
+		},
+		{
+			name:   "batch transfer skips external recipients of an external sender",
+			tx:     &account.BlockInfoTransactionList{Hash: batchTx.Hash, From: testExternal.String(), To: testUserAddress.String(), Amount: "6"},
+			single: true,
+			want: []transfer{
+				{to: testUserAddress, index: 0, amount: "1", txType: database.TxTypeDeposit},
+				{to: testUser2, index: 2, amount: "3", txType: database.TxTypeDeposit},
+			},
+		},
+		{
+			name:   "recipients without value are dropped",
+			tx:     &account.BlockInfoTransactionList{Hash: shortValuesTx.Hash, From: testExternal.String(), To: testUserAddress.String(), Amount: "1"},
+			single: true,
+			want:   []transfer{{to: testUserAddress, index: 0, amount: "1", txType: database.TxTypeDeposit}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers, err := syncer.accountTransfers(testBusinessId, RuleClassifier{}, big.NewInt(1), tt.tx, tt.index, tt.single, make(map[string]*account.TxMessage))
+			require.NoError(t, err)
+			require.Len(t, transfers, len(tt.want))
+			for i, want := range tt.want {
+				require.Equal(t, want.to, transfers[i].ToAddress)
+				require.Equal(t, want.index, transfers[i].Index)
+				require.Equal(t, want.amount, transfers[i].Amount)
+				require.Equal(t, want.txType, transfers[i].TxType)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/google/uuid"
+
+	"github.com/JokingLove/multichain-sync-account/common/retry"
+	"github.com/JokingLove/multichain-sync-account/common/tasks"
+	"github.com/JokingLove/multichain-sync-account/config"
+	"github.com/JokingLove/multichain-sync-account/database"
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+)
+
+const (
+	// watchdogCheckInterval 检查卡住交易和广播已签名替换交易的间隔
+	watchdogCheckInterval = 30 * time.Second
+	// minFeeBumpPercent 节点接受同 nonce 替换交易要求手续费至少提高 10%
+	minFeeBumpPercent = 110
+)
+
+// Watchdog 已广播的提现和内部交易超过 StuckBlocks 个区块仍未上链时，用相同 nonce 和提高后的 EIP-1559 手续费
+// 构建替换交易，由通知服务推送业务方重新签名；签名后的替换交易在这里广播。
+// 原交易和替换交易中哪一笔上链由充值扫块按交易哈希处理，一笔交易同时只有一个等待签名或广播的替换交易
+type Watchdog struct {
+	rpcClient      *rpcclient.WalletChainAccountClient
+	db             *database.DB
+	chainId        string
+	utxo           bool
+	stuckBlocks    *big.Int
+	feeBumpPercent int64
+	resourceCtx    context.Context
+	resourceCancel context.CancelFunc
+	tasks          tasks.Group
+	ticker         *time.Ticker
+}
+
+func NewWatchdog(cfg *config.ChainNodeConfig, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Watchdog, error) {
+	feeBumpPercent := int64(cfg.FeeBumpPercent)
+	if feeBumpPercent < minFeeBumpPercent {
+		log.Warn("fee bump percent too low, use minimum", "chain", cfg.ChainName, "feeBumpPercent", cfg.FeeBumpPercent, "minimum", minFeeBumpPercent)
+		feeBumpPercent = minFeeBumpPercent
+	}
+	resCtx, resCancel := context.WithCancel(context.Background())
+	return &Watchdog{
+		rpcClient:      rpcClient,
+		db:             db,
+		chainId:        strconv.FormatUint(cfg.ChainId, 10),
+		utxo:           cfg.Utxo,
+		stuckBlocks:    new(big.Int).SetUint64(cfg.StuckBlocks),
+		feeBumpPercent: feeBumpPercent,
+		resourceCtx:    resCtx,
+		resourceCancel: resCancel,
+		tasks: tasks.Group{HandleCrit: func(err error) {
+			shutdown(fmt.Errorf("critical error in %s watchdog: %w", rpcClient.ChainName, err))
+		}},
+		ticker: time.NewTicker(watchdogCheckInterval),
+	}, nil
+}
+
+func (w *Watchdog) Close() error {
+	var result error
+	w.resourceCancel()
+	w.ticker.Stop()
+	log.Info("stop watchdog......", "chain", w.rpcClient.ChainName)
+	if err := w.tasks.Wait(); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to await watchdog: %w", err))
+		return result
+	}
+	log.Info("stop watchdog success", "chain", w.rpcClient.ChainName)
+	return nil
+}
+
+func (w *Watchdog) Start() error {
+	// UTXO 链没有账户 nonce，不能按 nonce 替换交易
+	if w.utxo {
+		log.Info("skip watchdog for utxo chain", "chain", w.rpcClient.ChainName)
+		return nil
+	}
+	log.Info("starting watchdog...", "chain", w.rpcClient.ChainName, "stuckBlocks", w.stuckBlocks, "feeBumpPercent", w.feeBumpPercent)
+	w.tasks.Go(func() error {
+		for {
+			select {
+			case <-w.ticker.C:
+				header, err := w.db.Blocks.LatestBlocks(w.rpcClient.ChainName)
+				if err != nil {
+					log.Error("query latest block fail", "chain", w.rpcClient.ChainName, "err", err)
+					continue
+				}
+				if header == nil {
+					continue
+				}
+				businessList, err := w.db.Business.QueryBusinessList()
+				if err != nil {
+					log.Error("query business list fail", "err", err)
+					continue
+				}
+				for _, business := range businessList {
+					if err := w.broadcastReplacements(business.BusinessUid, header.Number); err != nil {
+						log.Error("broadcast replacements fail", "business", business.BusinessUid, "err", err)
+					}
+					if err := w.replaceStuckTransactions(business.BusinessUid, header.Number); err != nil {
+						log.Error("replace stuck transactions fail", "business", business.BusinessUid, "err", err)
+					}
+				}
+			case <-w.resourceCtx.Done():
+				log.Info("stop watchdog in worker", "chain", w.rpcClient.ChainName)
+				return nil
+			}
+		}
+	})
+	return nil
+}
+
+// broadcastReplacements 广播业务方已签名的替换交易，广播失败的下次继续
+func (w *Watchdog) broadcastReplacements(businessId string, number *big.Int) error {
+	replacements, err := w.db.Replaces.QuerySignedReplacements(businessId, w.rpcClient.ChainName)
+	if err != nil {
+		return err
+	}
+
+	var broadcasted []*database.Replacements
+	for _, replacement := range replacements {
+		txHash, err := w.rpcClient.SendTx(replacement.TxSignHex)
+		if err != nil {
+			log.Error("send replacement transaction fail", "replacement", replacement.GUID, "transactionId", replacement.TransactionId, "err", err)
+			continue
+		}
+		log.Info("replacement transaction broadcast", "transactionId", replacement.TransactionId, "nonce", replacement.Nonce, "txHash", txHash, "maxFeePerGas", replacement.MaxFeePerGas)
+		replacement.TxHash = common.HexToHash(txHash)
+		replacement.Status = database.TxStatusBoradcasted
+		replacement.BroadcastBlock = number
+		broadcasted = append(broadcasted, replacement)
+	}
+
+	if len(broadcasted) == 0 {
+		return nil
+	}
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	_, err = retry.Do[interface{}](w.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		return nil, w.db.Replaces.UpdateReplacementsBroadcast(businessId, broadcasted)
+	})
+	return err
+}
+
+// replaceStuckTransactions 广播区块早于 number - StuckBlocks 的提现和内部交易可能卡住，
+// 是否需要替换还要看最近一次替换交易的广播区块
+func (w *Watchdog) replaceStuckTransactions(businessId string, number *big.Int) error {
+	threshold := new(big.Int).Sub(number, w.stuckBlocks)
+	if threshold.Sign() <= 0 {
+		return nil
+	}
+
+	withdraws, err := w.db.Withdraws.QueryStuckWithdraws(businessId, w.rpcClient.ChainName, threshold)
+	if err != nil {
+		return err
+	}
+	for _, withdraw := range withdraws {
+		stuck := &database.Replacements{
+			TransactionId:        withdraw.GUID.String(),
+			TxType:               withdraw.TxType,
+			FromAddress:          withdraw.FromAddress,
+			ToAddress:            withdraw.ToAddress,
+			Amount:               withdraw.Amount,
+			GasLimit:             withdraw.GasLimit,
+			MaxFeePerGas:         withdraw.MaxFeePerGas,
+			MaxPriorityFeePerGas: withdraw.MaxPriorityFeePerGas,
+			TokenType:            withdraw.TokenType,
+			TokenAddress:         withdraw.TokenAddress,
+			TokenId:              withdraw.TokenId,
+			BroadcastBlock:       withdraw.BroadcastBlock,
+		}
+		if err := w.replace(businessId, number, stuck); err != nil {
+			log.Error("replace stuck withdraw fail", "business", businessId, "transactionId", withdraw.GUID, "err", err)
+		}
+	}
+
+	internals, err := w.db.Internals.QueryStuckInternals(businessId, w.rpcClient.ChainName, threshold)
+	if err != nil {
+		return err
+	}
+	for _, internal := range internals {
+		stuck := &database.Replacements{
+			TransactionId:        internal.GUID.String(),
+			TxType:               internal.TxType,
+			FromAddress:          internal.FromAddress,
+			ToAddress:            internal.ToAddress,
+			Amount:               internal.Amount,
+			GasLimit:             internal.GasLimit,
+			MaxFeePerGas:         internal.MaxFeePerGas,
+			MaxPriorityFeePerGas: internal.MaxPriorityFeePerGas,
+			TokenType:            internal.TokenType,
+			TokenAddress:         internal.TokenAddress,
+			TokenId:              internal.TokenId,
+			BroadcastBlock:       internal.BroadcastBlock,
+		}
+		if err := w.replace(businessId, number, stuck); err != nil {
+			log.Error("replace stuck internal fail", "business", businessId, "transactionId", internal.GUID, "err", err)
+		}
+	}
+	return nil
+}
+
+// replace stuck 为原交易的参数，最近一次广播（原交易或替换交易）之后超过 StuckBlocks 个区块仍未上链时，
+// 在最近一次广播的手续费基础上按 FeeBumpPercent 提高，且不低于当前链上的手续费，构建新的替换交易
+func (w *Watchdog) replace(businessId string, number *big.Int, stuck *database.Replacements) error {
+	replacements, err := w.db.Replaces.QueryReplacementsByTransaction(businessId, stuck.TransactionId)
+	if err != nil {
+		return err
+	}
+	lastBroadcast := stuck.BroadcastBlock
+	maxFeePerGas, maxPriorityFeePerGas := stuck.MaxFeePerGas, stuck.MaxPriorityFeePerGas
+	for _, replacement := range replacements {
+		switch replacement.Status {
+		case database.TxStatusCreateUnsigned, database.TxStatusSigned:
+			// 上一笔替换交易还在等待签名或广播
+			return nil
+		case database.TxStatusBoradcasted:
+			if replacement.BroadcastBlock.Cmp(lastBroadcast) > 0 {
+				lastBroadcast = replacement.BroadcastBlock
+			}
+			maxFeePerGas, maxPriorityFeePerGas = replacement.MaxFeePerGas, replacement.MaxPriorityFeePerGas
+		}
+	}
+	if new(big.Int).Sub(number, lastBroadcast).Cmp(w.stuckBlocks) < 0 {
+		return nil
+	}
+
+	reservation, err := w.db.Nonces.QueryNonceByTransaction(w.rpcClient.ChainName, stuck.TransactionId)
+	if err != nil {
+		return err
+	}
+	if reservation == nil {
+		log.Warn("stuck transaction has no nonce reservation, skip replace", "business", businessId, "transactionId", stuck.TransactionId)
+		return nil
+	}
+
+	maxFee, ok := new(big.Int).SetString(maxFeePerGas, 10)
+	if !ok {
+		return fmt.Errorf("invalid max fee per gas: %s", maxFeePerGas)
+	}
+	tip, ok := new(big.Int).SetString(maxPriorityFeePerGas, 10)
+	if !ok {
+		return fmt.Errorf("invalid max priority fee per gas: %s", maxPriorityFeePerGas)
+	}
+	maxFee = w.bumpFee(maxFee)
+	tip = w.bumpFee(tip)
+	// 链上手续费已经涨过提高后的手续费时按当前手续费替换，查询失败时只按比例提高
+	if feeInfo, err := w.rpcClient.GetFee(stuck.FromAddress.String()); err != nil {
+		log.Warn("get fee fail, bump previous fee only", "transactionId", stuck.TransactionId, "err", err)
+	} else {
+		maxFee = bigMax(maxFee, feeInfo.MaxPriorityFee)
+		tip = bigMax(tip, feeInfo.MultipliedTip)
+	}
+	maxFee = bigMax(maxFee, tip)
+
+	replacement := &database.Replacements{
+		GUID:                 uuid.New(),
+		TransactionId:        stuck.TransactionId,
+		TxType:               stuck.TxType,
+		Chain:                w.rpcClient.ChainName,
+		ChainId:              w.chainId,
+		Nonce:                reservation.Nonce,
+		FromAddress:          stuck.FromAddress,
+		ToAddress:            stuck.ToAddress,
+		Amount:               stuck.Amount,
+		GasLimit:             stuck.GasLimit,
+		MaxFeePerGas:         maxFee.String(),
+		MaxPriorityFeePerGas: tip.String(),
+		TokenType:            stuck.TokenType,
+		TokenAddress:         stuck.TokenAddress,
+		TokenId:              stuck.TokenId,
+		BroadcastBlock:       big.NewInt(0),
+		Status:               database.TxStatusCreateUnsigned,
+		Timestamp:            uint64(time.Now().Unix()),
+	}
+	unSignTx, err := w.rpcClient.CreateUnSignTransaction(replacementTx(replacement))
+	if err != nil {
+		return err
+	}
+	replacement.UnSignTx = unSignTx
+
+	log.Info("replace stuck transaction", "business", businessId, "transactionId", stuck.TransactionId, "nonce", reservation.Nonce, "lastBroadcast", lastBroadcast, "maxFeePerGas", replacement.MaxFeePerGas, "maxPriorityFeePerGas", replacement.MaxPriorityFeePerGas)
+	retryStrategy := &retry.ExponentialStrategy{Min: 1000, Max: 20_000, MaxJitter: 250}
+	_, err = retry.Do[interface{}](w.resourceCtx, 10, retryStrategy, func() (interface{}, error) {
+		return nil, w.db.Replaces.StoreReplacement(businessId, replacement)
+	})
+	return err
+}
+
+func (w *Watchdog) bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(w.feeBumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// replacementTx 替换交易的待签名交易参数，业务方签名后组装交易时按相同的参数构建
+func replacementTx(replacement *database.Replacements) *rpcclient.Eip1559DynamicFeeTx {
+	contractAddress := "0x00"
+	if replacement.TokenAddress != (common.Address{}) {
+		contractAddress = replacement.TokenAddress.String()
+	}
+	return &rpcclient.Eip1559DynamicFeeTx{
+		ChainId:              replacement.ChainId,
+		Nonce:                replacement.Nonce,
+		FromAddress:          replacement.FromAddress.String(),
+		ToAddress:            replacement.ToAddress.String(),
+		GasLimit:             replacement.GasLimit,
+		MaxFeePerGas:         replacement.MaxFeePerGas,
+		MaxPriorityFeePerGas: replacement.MaxPriorityFeePerGas,
+		Amount:               replacement.Amount.String(),
+		ContractAddress:      contractAddress,
+		TokenType:            string(replacement.TokenType),
+		TokenId:              replacement.TokenId,
+	}
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if b != nil && b.Cmp(a) > 0 {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/JokingLove/multichain-sync-account/rpcclient"
+)
+
+func TestWatchdogBumpFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		percent int64
+		fee     int64
+		want    int64
+	}{
+		{name: "exact", percent: 110, fee: 100, want: 110},
+		{name: "round up", percent: 110, fee: 101, want: 112},
+		{name: "round up small fee", percent: 110, fee: 1, want: 2},
+		{name: "zero fee", percent: 110, fee: 0, want: 0},
+		{name: "large percent", percent: 150, fee: 3, want: 5},
+		{name: "gwei", percent: 125, fee: 2_000_000_000, want: 2_500_000_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Watchdog{feeBumpPercent: tt.percent}
+			fee := big.NewInt(tt.fee)
+			require.Equal(t, big.NewInt(tt.want).String(), w.bumpFee(fee).String())
+			require.Equal(t, big.NewInt(tt.fee).String(), fee.String())
+		})
+	}
+}
+
+// TestParseFastFee 看门狗按链上手续费抬高替换交易的手续费，rpcclient 包的测试无法编译，放在这里
+func TestParseFastFee(t *testing.T) {
+	tests := []struct {
+		name           string
+		fee            string
+		multipliedTip  int64
+		maxPriorityFee int64
+		wantErr        bool
+	}{
+		{name: "multiplier", fee: "100|10|*2", multipliedTip: 20, maxPriorityFee: 140},
+		{name: "multiplier without prefix", fee: "100|10|3", multipliedTip: 30, maxPriorityFee: 160},
+		{name: "zero tip", fee: "100|0|*2", multipliedTip: 0, maxPriorityFee: 100},
+		{name: "missing part", fee: "100|10", wantErr: true},
+		{name: "invalid gas price", fee: "abc|10|*2", wantErr: true},
+		{name: "invalid tip", fee: "100|1.5|*2", wantErr: true},
+		{name: "invalid multiplier", fee: "100|10|x2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feeInfo, err := rpcclient.ParseFastFee(tt.fee)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, big.NewInt(tt.multipliedTip).String(), feeInfo.MultipliedTip.String())
+			require.Equal(t, big.NewInt(tt.maxPriorityFee).String(), feeInfo.MaxPriorityFee.String())
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -19,23 +20,25 @@ import (
 type Withdraw struct {
 	rpcClient      *rpcclient.WalletChainAccountClient
 	db             *database.DB
+	maxAttempts    uint64
 	resourceCtx    context.Context
 	resourceCancel context.CancelFunc
 	tasks          tasks.Group
 	ticker         *time.Ticker
 }
 
-func NewWithdraw(cfg *config.Config, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Withdraw, error) {
+func NewWithdraw(cfg *config.ChainNodeConfig, db *database.DB, rpcClient *rpcclient.WalletChainAccountClient, shutdown context.CancelCauseFunc) (*Withdraw, error) {
 	resCtx, resCancel := context.WithCancel(context.Background())
 	return &Withdraw{
 		rpcClient:      rpcClient,
 		db:             db,
+		maxAttempts:    cfg.MaxBroadcastAttempts,
 		resourceCtx:    resCtx,
 		resourceCancel: resCancel,
 		tasks: tasks.Group{HandleCrit: func(err error) {
-			shutdown(fmt.Errorf("critical error in withdraw: %w", err))
+			shutdown(fmt.Errorf("critical error in %s withdraw: %w", rpcClient.ChainName, err))
 		}},
-		ticker: time.NewTicker(cfg.ChainNode.WorkerInterval),
+		ticker: time.NewTicker(cfg.WorkerInterval),
 	}, nil
 }
 
@@ -65,7 +68,7 @@ func (w *Withdraw) Start() error {
 				}
 
 				for _, business := range businessList {
-					unSendTransactionList, err := w.db.Withdraws.UnSendWithdrawList(business.BusinessUid)
+					unSendTransactionList, err := w.db.Withdraws.UnSendWithdrawList(business.BusinessUid, w.rpcClient.ChainName)
 					if err != nil {
 						log.Error("query un send withdraw list failed", "err", err)
 						continue
@@ -76,22 +79,57 @@ func (w *Withdraw) Start() error {
 						continue
 					}
 
+					// 记录广播时已同步的最新区块，watchdog 据此判断交易是否卡住
+					broadcastBlock := big.NewInt(0)
+					if header, err := w.db.Blocks.LatestBlocks(w.rpcClient.ChainName); err != nil {
+						log.Error("query latest block fail", "err", err)
+					} else if header != nil {
+						broadcastBlock = header.Number
+					}
+
 					var balanceList []*database.Balances
+					var broadcasted []string
 
 					for _, unSendTransaction := range unSendTransactionList {
+						// 广播前按签名交易计算哈希，广播结果不确定的交易上链后同步器也能按哈希匹配
+						if unSendTransaction.TxHash == (common.Hash{}) {
+							if localHash, err := rpcclient.SignedTxHash(unSendTransaction.TxSignHex); err != nil {
+								log.Warn("compute signed tx hash fail", "guid", unSendTransaction.GUID, "err", err)
+							} else {
+								unSendTransaction.TxHash = localHash
+							}
+						}
 						txHash, err := w.rpcClient.SendTx(unSendTransaction.TxSignHex)
+						unSendTransaction.BroadcastAttempts++
 						if err != nil {
-							log.Error("send transaction failed", "err", err)
+							unSendTransaction.LastError = err.Error()
+							// 达到最大次数后放弃并通知业务方；否则按指数退避等待下次重试。
+							// 超时或 already known 不能说明交易没有进入交易池，nonce 保留到链上 nonce 超过它，不在这里释放
+							if unSendTransaction.BroadcastAttempts >= w.maxAttempts {
+								log.Error("send transaction failed, give up", "guid", unSendTransaction.GUID, "attempts", unSendTransaction.BroadcastAttempts, "err", err)
+								unSendTransaction.Status = database.TxStatusBroadcastFailed
+							} else {
+								log.Error("send transaction failed", "guid", unSendTransaction.GUID, "attempts", unSendTransaction.BroadcastAttempts, "err", err)
+								unSendTransaction.NextRetryAt = uint64(time.Now().Add(broadcastBackoff(unSendTransaction.BroadcastAttempts)).Unix())
+							}
 							continue
 						} else {
 							balanceItem := &database.Balances{
 								Address:      unSendTransaction.FromAddress,
-								TokenAddress: unSendTransaction.ToAddress,
+								TokenAddress: unSendTransaction.TokenAddress,
 								LockBalance:  unSendTransaction.Amount,
+								SourceGuid:   unSendTransaction.GUID,
+								Chain:        w.rpcClient.ChainName,
+								BlockNumber:  broadcastBlock,
 							}
 							balanceList = append(balanceList, balanceItem)
-							unSendTransaction.TxHash = common.HexToHash(txHash)
+							broadcasted = append(broadcasted, unSendTransaction.GUID.String())
+							if nodeHash := common.HexToHash(txHash); txHash != "" && nodeHash != unSendTransaction.TxHash {
+								log.Warn("node tx hash differs from signed tx hash", "guid", unSendTransaction.GUID, "local", unSendTransaction.TxHash, "node", nodeHash)
+								unSendTransaction.TxHash = nodeHash
+							}
 							unSendTransaction.Status = database.TxStatusBoradcasted
+							unSendTransaction.BroadcastBlock = broadcastBlock
 						}
 					}
 
@@ -104,10 +142,14 @@ func (w *Withdraw) Start() error {
 									log.Error("Update address balance fail", "err", err)
 									return err
 								}
+								if err := tx.Nonces.MarkNonceBroadcast(w.rpcClient.ChainName, broadcasted); err != nil {
+									log.Error("mark nonce broadcast fail", "err", err)
+									return err
+								}
 							}
 
 							if len(unSendTransactionList) > 0 {
-								err = w.db.Withdraws.UpdateWithdrawListById(business.BusinessUid, unSendTransactionList)
+								err := tx.Withdraws.UpdateWithdrawListById(business.BusinessUid, unSendTransactionList)
 								if err != nil {
 									log.Error("Update address withdraw status failed", "err", err)
 									return err